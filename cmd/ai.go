@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"github.com/snip/internal/handler"
+	"github.com/spf13/cobra"
+)
+
+var aiCmd = &cobra.Command{
+	Use:   "ai",
+	Short: "Chat with AI and inspect AI usage",
+	Long:  `Commands for chatting with AI about your notes and inspecting AI usage and costs.`,
+}
+
+var aiUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show AI token usage and estimated cost",
+	Long: `Show how many tokens the AI commands consumed, grouped by model or by command,
+with an estimated cost based on the price table in ~/.snip/config.json.
+
+Examples:
+  snip ai usage
+  snip ai usage --since 7d
+  snip ai usage --since 2025-01-01 --by command`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, _ := cmd.Flags().GetString("since")
+		by, _ := cmd.Flags().GetString("by")
+		return executeWithAIHandler(func(h handler.AIHandler) error {
+			return h.UsageReport(since, by)
+		})
+	},
+}
+
+var aiLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show actions the AI took through tools",
+	Long: `Show the latest tool calls made by the AI in 'snip ai chat': searches, and the
+task and checklist changes it proposed, with whether they were executed,
+declined or failed.
+
+Examples:
+  snip ai log
+  snip ai log --limit 50`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		return executeWithAIHandler(func(h handler.AIHandler) error {
+			return h.ActionLog(limit)
+		})
+	},
+}
+
+var aiPromptsCmd = &cobra.Command{
+	Use:   "prompts",
+	Short: "List the AI prompt templates and their overrides",
+	Long: `List the prompt templates used by the AI commands for the configured
+language and whether each comes from the built-in default or from an override.
+
+Prompts are Go templates (text/template) with a "system" and a "user" block.
+A file named <prompt>.tmpl in ~/.snip/prompts/<language>/ or ~/.snip/prompts/
+replaces the built-in one. Use --export to copy the defaults there as a
+starting point.
+
+The language is set with "language" in ~/.snip/config.json ("pt" or "en") or
+the SNIP_AI_LANGUAGE environment variable. It selects the prompts and the
+language AI answers are written in.
+
+Examples:
+  snip ai prompts
+  snip ai prompts --export`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		export, _ := cmd.Flags().GetBool("export")
+		return executeWithAIHandler(func(h handler.AIHandler) error {
+			return h.Prompts(export)
+		})
+	},
+}
+
+var aiCacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the AI response cache",
+	Long: `AI answers are cached in the database, keyed by a hash of the model, the
+messages and the request settings, so running the same command with the same
+input again does not call the API. Entries expire after "cache_ttl_hours" in
+~/.snip/config.json (default 168, a week; a negative value disables the cache).
+
+Use --no-cache on any command to ask the AI again.
+
+Examples:
+  snip ai cache stats
+  snip ai cache clear
+  snip ai cache clear --expired
+  snip ai-code "http server" --no-cache`,
+}
+
+var aiCacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how many AI answers are cached and reused",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithAIHandler(func(h handler.AIHandler) error {
+			return h.CacheStats()
+		})
+	},
+}
+
+var aiCacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete cached AI answers",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		expired, _ := cmd.Flags().GetBool("expired")
+		return executeWithAIHandler(func(h handler.AIHandler) error {
+			return h.ClearCache(expired)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(aiCmd)
+	aiCmd.AddCommand(aiUsageCmd)
+	aiCmd.AddCommand(aiLogCmd)
+	aiCmd.AddCommand(aiPromptsCmd)
+	aiCmd.AddCommand(aiCacheCmd)
+	aiCacheCmd.AddCommand(aiCacheStatsCmd)
+	aiCacheCmd.AddCommand(aiCacheClearCmd)
+
+	aiUsageCmd.Flags().String("since", "30d", "Only include calls since a date (2025-01-01) or duration (7d, 2w, 1m)")
+	aiUsageCmd.Flags().String("by", "model", "Group by model or command")
+	aiLogCmd.Flags().Int("limit", 20, "Number of actions to show")
+	aiPromptsCmd.Flags().Bool("export", false, "Copy the default prompts to ~/.snip/prompts/<language>/ for editing")
+	aiCacheClearCmd.Flags().Bool("expired", false, "Only delete entries older than the cache TTL")
+}
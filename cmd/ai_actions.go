@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"github.com/snip/internal/handler"
+	"github.com/spf13/cobra"
+)
+
+var (
+	aiActionsProject int
+	aiActionsYes     bool
+)
+
+func init() {
+	aiActionsCmd.Flags().IntVarP(&aiActionsProject, "project", "p", 0, "Project the tasks are created in (required)")
+	aiActionsCmd.Flags().BoolVarP(&aiActionsYes, "yes", "y", false, "Create every new action item without asking")
+	aiActionsCmd.MarkFlagRequired("project")
+	rootCmd.AddCommand(aiActionsCmd)
+}
+
+var aiActionsCmd = &cobra.Command{
+	Use:   "ai-actions [note-id]",
+	Short: "Turn the action items in a note into tasks",
+	Long: `Extract action items (title, owner and due date) from a note, such as
+meeting minutes, and create the ones you select as tasks in a project.
+
+Each task remembers the note it came from, shown by "snip task show". Items
+already created from the same note are listed but not selected again.
+
+Examples:
+  snip ai-actions 42 --project 3
+  snip ai-actions 42 -p 3 --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithActionItemHandler(func(h handler.ActionItemHandler) error {
+			return h.ExtractActionItems(args[0], aiActionsProject, aiActionsYes)
+		})
+	},
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/snip/internal/handler"
@@ -25,13 +24,11 @@ Examples:
   snip ai-ask "Summarize my meeting notes"
   snip ai-ask "What are the main topics in my notes?"`,
 	Args: cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithHandler(func(h handler.Handler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithHandler(func(h handler.Handler) error {
 			question := strings.Join(args, " ")
 			return h.AskAI(question)
-		}); err != nil {
-			fmt.Printf("Error: %v\n", err)
-		}
+		})
 	},
 }
 
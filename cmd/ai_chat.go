@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"github.com/snip/internal/handler"
+	"github.com/spf13/cobra"
+)
+
+var aiChatCmd = &cobra.Command{
+	Use:   "chat",
+	Short: "Chat with AI about your notes",
+	Long: `Start an interactive chat with AI. Conversations are stored and can be resumed
+later with --session.
+
+The AI can use tools to search notes, list projects and tasks, create tasks,
+change a task's status and toggle checklist items. Every change is confirmed
+before it runs and is logged (see 'snip ai log'). Use --no-tools to turn tools
+off and get streamed answers.
+
+Inside the chat:
+  /add #42 [#43 ...]  add notes to the conversation context
+  /search <term>      add the best matching notes to the context
+  /save               save the transcript as a note
+  /save last          save the last answer as a note
+  /exit               leave the chat (Ctrl+D also works)
+
+Examples:
+  snip ai chat
+  snip ai chat --session 3
+  snip ai chat --list
+  snip ai chat --no-tools`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		session, _ := cmd.Flags().GetInt("session")
+		list, _ := cmd.Flags().GetBool("list")
+		noTools, _ := cmd.Flags().GetBool("no-tools")
+		return executeWithChatHandler(!noTools, func(h handler.ChatHandler) error {
+			if list {
+				return h.ListSessions(20)
+			}
+			return h.Chat(session)
+		})
+	},
+}
+
+func init() {
+	aiCmd.AddCommand(aiChatCmd)
+
+	aiChatCmd.Flags().Int("session", 0, "Resume a stored chat session by ID")
+	aiChatCmd.Flags().Bool("list", false, "List recent chat sessions")
+	aiChatCmd.Flags().Bool("no-tools", false, "Do not let the AI use tools; answers are streamed")
+}
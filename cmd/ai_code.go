@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/snip/internal/handler"
@@ -10,10 +9,18 @@ import (
 
 var aiCodeLang string
 var aiCodeContext string
+var aiCodeNoStream bool
+var aiCodeSave bool
+var aiCodeTag string
+var aiCodeOut string
 
 func init() {
 	aiCodeCmd.Flags().StringVarP(&aiCodeLang, "lang", "l", "go", "Programming language")
 	aiCodeCmd.Flags().StringVarP(&aiCodeContext, "context", "c", "", "Additional context for code generation")
+	aiCodeCmd.Flags().BoolVar(&aiCodeNoStream, "no-stream", false, "Wait for the full response and render it as markdown")
+	aiCodeCmd.Flags().BoolVarP(&aiCodeSave, "save", "s", false, "Save the answer as a snippet note with its language")
+	aiCodeCmd.Flags().StringVarP(&aiCodeTag, "tag", "t", "", "Extra tags for the saved note (with --save)")
+	aiCodeCmd.Flags().StringVarP(&aiCodeOut, "out", "o", "", "Write the code blocks of the answer to this file")
 	rootCmd.AddCommand(aiCodeCmd)
 }
 
@@ -23,14 +30,23 @@ var aiCodeCmd = &cobra.Command{
 	Long: `Generate code in a specific programming language using AI.
 
 The AI will generate clean, well-documented code following best practices.
+The code is streamed to the terminal as it is generated; press Ctrl+C to stop.
+Use --no-stream to wait for the full response and render it as markdown.
+
+--save stores the answer as a note with the language recorded and the tags
+"snippet" and the language (plus any given with --tag). --out writes the
+fenced code blocks of the answer to a file; when the answer has blocks in
+several languages, only those matching --lang or the file extension are kept.
 
 Examples:
   snip ai-code "function to reverse a string"
   snip ai-code "REST API endpoint" --lang "python" --context "Use FastAPI"
-  snip ai-code "binary search algorithm" --lang "javascript"`,
+  snip ai-code "binary search algorithm" --lang "javascript"
+  snip ai-code "retry with backoff" --save --tag "http"
+  snip ai-code "CSV to JSON converter" --out csv2json.go`,
 	Args: cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithHandler(func(h handler.Handler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithHandler(func(h handler.Handler) error {
 			description := strings.Join(args, " ")
 			context := ""
 			if aiCodeContext != "" {
@@ -40,10 +56,12 @@ Examples:
 			if aiCodeLang != "" {
 				lang = aiCodeLang
 			}
-			return h.GenerateCodeWithAI(lang, description, context)
-		}); err != nil {
-			fmt.Printf("Error: %v\n", err)
-		}
+			var tag *string
+			if aiCodeTag != "" {
+				tag = &aiCodeTag
+			}
+			return h.GenerateCodeWithAI(lang, description, context, !aiCodeNoStream, aiCodeSave, tag, aiCodeOut)
+		})
 	},
 }
 
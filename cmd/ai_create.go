@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/snip/internal/handler"
@@ -11,10 +10,12 @@ import (
 
 var aiCreateTag string
 var aiCreateContext string
+var aiCreateNoStream bool
 
 func init() {
 	aiCreateCmd.Flags().StringVarP(&aiCreateTag, "tag", "t", "", "Tag for the note")
 	aiCreateCmd.Flags().StringVarP(&aiCreateContext, "context", "c", "", "Additional context for AI generation")
+	aiCreateCmd.Flags().BoolVar(&aiCreateNoStream, "no-stream", false, "Wait for the full response instead of streaming it")
 	rootCmd.AddCommand(aiCreateCmd)
 }
 
@@ -26,13 +27,16 @@ var aiCreateCmd = &cobra.Command{
 The AI will generate well-structured and useful content about the topic.
 You can provide additional context using the --context flag.
 
+The response is streamed to the terminal as it is generated and saved into the
+note once complete. Press Ctrl+C to cancel; nothing is saved in that case.
+
 Examples:
   snip ai-create "Python Decorators"
   snip ai-create "Machine Learning Basics" --tag "learning"
   snip ai-create "REST API Design" --context "Focus on best practices" --tag "api"`,
 	Args: cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithHandler(func(h handler.Handler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithHandler(func(h handler.Handler) error {
 			validator := validation.NewValidator()
 			topic := strings.Join(args, " ")
 			var tag *string
@@ -43,10 +47,8 @@ Examples:
 			if aiCreateContext != "" {
 				context = aiCreateContext
 			}
-			return h.CreateNoteWithAI(topic, context, tag)
-		}); err != nil {
-			fmt.Printf("Error: %v\n", err)
-		}
+			return h.CreateNoteWithAI(topic, context, tag, !aiCreateNoStream)
+		})
 	},
 }
 
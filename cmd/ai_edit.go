@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/snip/internal/handler"
+	"github.com/spf13/cobra"
+)
+
+var aiEditYes bool
+
+func init() {
+	aiEditCmd.Flags().BoolVarP(&aiEditYes, "yes", "y", false, "Apply the rewrite without asking")
+	rootCmd.AddCommand(aiEditCmd)
+}
+
+var aiEditCmd = &cobra.Command{
+	Use:   "ai-edit [id] [instruction]",
+	Short: "Rewrite an existing note with AI",
+	Long: `Rewrite a note following an instruction and review the change as a diff.
+
+The proposed content is shown as a unified diff against the current note. You
+can apply it, discard it, or open it in your editor to adjust it first; the
+note is only changed when the rewrite is applied.
+
+Examples:
+  snip ai-edit 42 "make this a runbook"
+  snip ai-edit 42 "fix typos and keep everything else"`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithHandler(func(h handler.Handler) error {
+			return h.AIEditNote(args[0], args[1], aiEditYes)
+		})
+	},
+}
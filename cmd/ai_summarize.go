@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/snip/internal/handler"
+	"github.com/spf13/cobra"
+)
+
+var summarizeTag string
+var summarizeProject int
+var summarizeSave bool
+
+func init() {
+	aiSummarizeCmd.Flags().StringVarP(&summarizeTag, "tag", "t", "", "Summarize every note with this tag")
+	aiSummarizeCmd.Flags().IntVarP(&summarizeProject, "project", "p", 0, "Summarize a project and its tasks")
+	aiSummarizeCmd.Flags().BoolVarP(&summarizeSave, "save", "s", false, "Save the summary as a new note linked to its sources")
+	rootCmd.AddCommand(aiSummarizeCmd)
+}
+
+var aiSummarizeCmd = &cobra.Command{
+	Use:   "ai-summarize [note-id]",
+	Short: "Summarize a note, a tag or a project with AI",
+	Long: `Produce a concise summary of a note, of all notes with a tag, or of a project
+and its tasks. Long input is split into parts that are summarized separately
+and then combined, so it always fits the model's context.
+
+With --save the summary is stored as a new note tagged "summary" and linked to
+the notes or project it was built from.
+
+Examples:
+  snip ai-summarize 42
+  snip ai-summarize --tag meeting --save
+  snip ai-summarize --project 3`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		targets := len(args)
+		if summarizeTag != "" {
+			targets++
+		}
+		if summarizeProject > 0 {
+			targets++
+		}
+		if targets != 1 || len(args) > 1 {
+			return fmt.Errorf("provide exactly one of: a note ID, --tag or --project")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithSummaryHandler(func(h handler.SummaryHandler) error {
+			switch {
+			case summarizeTag != "":
+				return h.SummarizeTag(summarizeTag, summarizeSave)
+			case summarizeProject > 0:
+				return h.SummarizeProject(summarizeProject, summarizeSave)
+			default:
+				return h.SummarizeNote(args[0], summarizeSave)
+			}
+		})
+	},
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/snip/internal/handler"
+	"github.com/spf13/cobra"
+)
+
+var aiTagAllUntagged bool
+var aiTagYes bool
+
+func init() {
+	aiTagCmd.Flags().BoolVar(&aiTagAllUntagged, "all-untagged", false, "Suggest tags for every note without tags")
+	aiTagCmd.Flags().BoolVarP(&aiTagYes, "yes", "y", false, "Apply suggestions without asking")
+	rootCmd.AddCommand(aiTagCmd)
+}
+
+var aiTagCmd = &cobra.Command{
+	Use:   "ai-tag [id]",
+	Short: "Suggest tags and a better title for notes with AI",
+	Long: `Suggest tags and, when the current one is vague, a better title for a note.
+
+Existing tags are preferred so the same topic keeps the same tag. Each suggestion
+is shown before anything is changed; suggested tags are added to the note's
+current tags.
+
+Set "auto_tag_on_create": true in ~/.snip/config.json to get suggestions right
+after 'snip create' when no --tag is given.
+
+Examples:
+  snip ai-tag 42
+  snip ai-tag --all-untagged
+  snip ai-tag --all-untagged --yes`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if aiTagAllUntagged {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithHandler(func(h handler.Handler) error {
+			id := ""
+			if len(args) > 0 {
+				id = args[0]
+			}
+			return h.AITagNotes(id, aiTagAllUntagged, aiTagYes)
+		})
+	},
+}
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/snip/internal/handler"
 	"github.com/spf13/cobra"
 )
@@ -23,11 +21,9 @@ This is the recommended method for backing up your notes as it:
 
 Examples:
   snip backup          # Create a backup with current timestamp`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithHandler(func(h handler.Handler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithHandler(func(h handler.Handler) error {
 			return h.BackupDatabase()
-		}); err != nil {
-			fmt.Printf("Error: %v\n", err)
-		}
+		})
 	},
 }
@@ -1,10 +1,10 @@
 package cmd
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/snip/internal/apperr"
 	"github.com/snip/internal/handler"
 	"github.com/spf13/cobra"
 )
@@ -43,8 +43,8 @@ var checklistCreateCmd = &cobra.Command{
 	Use:   "create [title]",
 	Short: "Criar uma nova checklist",
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
 			title := strings.Join(args, " ")
 			var taskID, projectID *int
 			if checklistTaskID > 0 {
@@ -54,9 +54,7 @@ var checklistCreateCmd = &cobra.Command{
 				projectID = &checklistProjectID
 			}
 			return h.CreateChecklist(title, checklistDescription, taskID, projectID)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -64,8 +62,8 @@ var checklistAICreateCmd = &cobra.Command{
 	Use:   "ai-create [topic]",
 	Short: "Criar checklist com itens gerados por IA",
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
 			topic := strings.Join(args, " ")
 			var taskID, projectID *int
 			if checklistTaskID > 0 {
@@ -75,17 +73,15 @@ var checklistAICreateCmd = &cobra.Command{
 				projectID = &checklistProjectID
 			}
 			return h.CreateChecklistWithAI(topic, checklistDescription, checklistNumItems, taskID, projectID)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
 var checklistListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Listar checklists",
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
 			var taskID, projectID *int
 			if checklistTaskID > 0 {
 				taskID = &checklistTaskID
@@ -94,9 +90,7 @@ var checklistListCmd = &cobra.Command{
 				projectID = &checklistProjectID
 			}
 			return h.ListChecklists(taskID, projectID)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -104,16 +98,14 @@ var checklistShowCmd = &cobra.Command{
 	Use:   "show [id]",
 	Short: "Mostrar detalhes de uma checklist",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
-				return fmt.Errorf("ID inválido: %s", args[0])
+				return apperr.Invalid("ID inválido: %s", args[0])
 			}
 			return h.ShowChecklist(id)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -121,16 +113,14 @@ var checklistDeleteCmd = &cobra.Command{
 	Use:   "delete [id]",
 	Short: "Deletar uma checklist",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
-				return fmt.Errorf("ID inválido: %s", args[0])
+				return apperr.Invalid("ID inválido: %s", args[0])
 			}
 			return h.DeleteChecklist(id)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -138,17 +128,15 @@ var checklistItemAddCmd = &cobra.Command{
 	Use:   "item-add [checklist_id] [title]",
 	Short: "Adicionar item a uma checklist",
 	Args:  cobra.MinimumNArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
 			checklistID, err := strconv.Atoi(args[0])
 			if err != nil {
-				return fmt.Errorf("ID inválido: %s", args[0])
+				return apperr.Invalid("ID inválido: %s", args[0])
 			}
 			title := strings.Join(args[1:], " ")
 			return h.AddChecklistItem(checklistID, title, checklistItemDescription)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -156,16 +144,14 @@ var checklistItemToggleCmd = &cobra.Command{
 	Use:   "item-toggle [item_id]",
 	Short: "Marcar/desmarcar item como concluído",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
-				return fmt.Errorf("ID inválido: %s", args[0])
+				return apperr.Invalid("ID inválido: %s", args[0])
 			}
 			return h.ToggleChecklistItem(id)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -173,16 +159,14 @@ var checklistItemDeleteCmd = &cobra.Command{
 	Use:   "item-delete [item_id]",
 	Short: "Deletar um item de checklist",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithChecklistHandler(func(h handler.ChecklistHandler) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
-				return fmt.Errorf("ID inválido: %s", args[0])
+				return apperr.Invalid("ID inválido: %s", args[0])
 			}
 			return h.DeleteChecklistItem(id)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
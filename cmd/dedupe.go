@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"github.com/snip/internal/dedupe"
+	"github.com/snip/internal/handler"
+	"github.com/spf13/cobra"
+)
+
+var dedupeThreshold float64
+var dedupeAI bool
+var dedupeList bool
+
+func init() {
+	dedupeCmd.Flags().Float64VarP(&dedupeThreshold, "threshold", "t", dedupe.DefaultThreshold, "Minimum overlap between two notes, from 0 to 1")
+	dedupeCmd.Flags().BoolVar(&dedupeAI, "ai", false, "Ask the AI to confirm each pair before showing it")
+	dedupeCmd.Flags().BoolVarP(&dedupeList, "list", "l", false, "Only list the pairs, without merging")
+	rootCmd.AddCommand(dedupeCmd)
+}
+
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Find duplicate and near-duplicate notes",
+	Long: `Find notes that cover the same content and merge or ignore them.
+
+Notes are compared locally, without network access, by how many three-word
+sequences they share (MinHash). With --ai each candidate pair is also checked by
+the AI, and pairs it does not consider duplicates are skipped.
+
+Every pair is shown side by side. Merging keeps one note, appends the other's
+content (unless one already contains the other), adds its tags, moves its links
+and deletes it. Ignored pairs are remembered and not shown again.
+
+Examples:
+  snip dedupe
+  snip dedupe --threshold 0.3 --list
+  snip dedupe --ai`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithDedupeHandler(func(h handler.DedupeHandler) error {
+			return h.FindDuplicates(dedupeThreshold, dedupeAI, dedupeList)
+		})
+	},
+}
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/snip/internal/handler"
 	"github.com/spf13/cobra"
 )
@@ -21,11 +19,9 @@ Examples:
   
 Tip: Use 'snip list' or 'snip show [id]' to verify the note before deletion.`,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithHandler(func(h handler.Handler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithHandler(func(h handler.Handler) error {
 			return h.DeleteNote(args[0])
-		}); err != nil {
-			fmt.Printf("Error: %v\n", err)
-		}
+		})
 	},
 }
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// portugueseCommands are the command groups whose messages are written in
+// Portuguese, so their errors start with "Erro:" instead of "Error:".
+var portugueseCommands = map[string]bool{
+	"project":   true,
+	"task":      true,
+	"checklist": true,
+	"report":    true,
+}
+
+// printError writes err to w. With --output json or yaml it is written as an
+// apperr.Report so scripts can read the kind and exit code.
+func printError(w io.Writer, cmd *cobra.Command, err error) {
+	value, _ := cmd.Flags().GetString("output")
+	if format, perr := output.ParseFormat(value); perr == nil && (format == output.FormatJSON || format == output.FormatYAML) {
+		if output.Write(w, format, apperr.Describe(err), nil) == nil {
+			return
+		}
+	}
+
+	prefix := "Error"
+	if portugueseCommands[topLevel(cmd).Name()] {
+		prefix = "Erro"
+	}
+	fmt.Fprintf(w, "%s: %v\n", prefix, err)
+}
+
+// topLevel returns the child of rootCmd that cmd belongs to, or rootCmd.
+func topLevel(cmd *cobra.Command) *cobra.Command {
+	for cmd.HasParent() && cmd.Parent() != rootCmd {
+		cmd = cmd.Parent()
+	}
+	return cmd
+}
+
+// exitCode prints err and returns the code the process should exit with.
+// Errors raised before the command ran, such as a missing argument or an
+// unknown flag, are usage errors.
+func exitCode(cmd *cobra.Command, err error) int {
+	if err == nil {
+		return 0
+	}
+	if !commandStarted {
+		err = apperr.New(apperr.KindUsage, err)
+	}
+	printError(os.Stderr, cmd, err)
+	return apperr.ExitCode(err)
+}
@@ -4,9 +4,14 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/ai"
 	"github.com/snip/internal/database"
 	"github.com/snip/internal/handler"
+	"github.com/snip/internal/mcp"
 	"github.com/snip/internal/repository"
+	"github.com/snip/internal/server"
+	"github.com/snip/internal/tui"
 )
 
 var (
@@ -16,6 +21,14 @@ var (
 	globalTaskRepo      repository.TaskRepository
 	globalChecklistRepo repository.ChecklistRepository
 	globalChecklistItemRepo repository.ChecklistItemRepository
+	globalEmbeddingRepo repository.EmbeddingRepository
+	globalUsageRepo     repository.UsageRepository
+	globalLinkRepo      repository.LinkRepository
+	globalChatRepo      repository.ChatRepository
+	globalActionRepo    repository.ActionRepository
+	globalActivityRepo  repository.ActivityRepository
+	globalCacheRepo     repository.CacheRepository
+	globalDedupeRepo    repository.DedupeRepository
 	repoOnce            sync.Once
 )
 
@@ -48,6 +61,43 @@ func getRepository() (repository.NoteRepository, repository.TagRepository, error
 			return
 		}
 		globalChecklistItemRepo, err = repository.NewChecklistItemRepository(db)
+		if err != nil {
+			return
+		}
+		globalEmbeddingRepo, err = repository.NewEmbeddingRepository(db)
+		if err != nil {
+			return
+		}
+		globalUsageRepo, err = repository.NewUsageRepository(db)
+		if err != nil {
+			return
+		}
+		ai.SetUsageLedger(globalUsageRepo)
+		globalLinkRepo, err = repository.NewLinkRepository(db)
+		if err != nil {
+			return
+		}
+		globalChatRepo, err = repository.NewChatRepository(db)
+		if err != nil {
+			return
+		}
+		globalActionRepo, err = repository.NewActionRepository(db)
+		if err != nil {
+			return
+		}
+		globalActivityRepo, err = repository.NewActivityRepository(db)
+		if err != nil {
+			return
+		}
+		globalCacheRepo, err = repository.NewCacheRepository(db)
+		if err != nil {
+			return
+		}
+		ai.SetResponseCache(globalCacheRepo)
+		globalDedupeRepo, err = repository.NewDedupeRepository(db)
+		if err != nil {
+			return
+		}
 	})
 	return globalNoteRepo, globalTagRepo, err
 }
@@ -55,10 +105,10 @@ func getRepository() (repository.NoteRepository, repository.TagRepository, error
 func setupHandler() (handler.Handler, error) {
 	noteRepo, tagRepo, err := getRepository()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
 	}
 
-	h := handler.NewHandler(noteRepo, tagRepo)
+	h := handler.NewHandler(noteRepo, tagRepo, globalEmbeddingRepo)
 
 	return h, nil
 }
@@ -66,33 +116,155 @@ func setupHandler() (handler.Handler, error) {
 func setupProjectHandler() (handler.ProjectHandler, error) {
 	_, _, err := getRepository()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
 	}
 
-	h := handler.NewProjectHandler(globalProjectRepo, globalTaskRepo)
+	h := handler.NewProjectHandler(globalProjectRepo, globalTaskRepo, globalChecklistRepo, globalChecklistItemRepo)
 	return h, nil
 }
 
 func setupTaskHandler() (handler.TaskHandler, error) {
 	_, _, err := getRepository()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
 	}
 
-	h := handler.NewTaskHandler(globalTaskRepo, globalProjectRepo)
+	h := handler.NewTaskHandler(globalTaskRepo, globalProjectRepo, globalChecklistRepo, globalChecklistItemRepo)
 	return h, nil
 }
 
 func setupChecklistHandler() (handler.ChecklistHandler, error) {
 	_, _, err := getRepository()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
 	}
 
 	h := handler.NewChecklistHandler(globalChecklistRepo, globalChecklistItemRepo)
 	return h, nil
 }
 
+func setupAIHandler() (handler.AIHandler, error) {
+	_, _, err := getRepository()
+	if err != nil {
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
+	}
+
+	h := handler.NewAIHandler(globalUsageRepo, globalActionRepo, globalCacheRepo)
+	return h, nil
+}
+
+func setupSummaryHandler() (handler.SummaryHandler, error) {
+	noteRepo, tagRepo, err := getRepository()
+	if err != nil {
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
+	}
+
+	h := handler.NewSummaryHandler(noteRepo, tagRepo, globalProjectRepo, globalTaskRepo, globalLinkRepo)
+	return h, nil
+}
+
+func setupActionItemHandler() (handler.ActionItemHandler, error) {
+	noteRepo, _, err := getRepository()
+	if err != nil {
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
+	}
+
+	h := handler.NewActionItemHandler(noteRepo, globalProjectRepo, globalTaskRepo)
+	return h, nil
+}
+
+func setupChatHandler(withTools bool) (handler.ChatHandler, error) {
+	noteRepo, tagRepo, err := getRepository()
+	if err != nil {
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
+	}
+
+	var tools *handler.ToolRunner
+	if withTools {
+		taskHandler := handler.NewTaskHandler(globalTaskRepo, globalProjectRepo, globalChecklistRepo, globalChecklistItemRepo)
+		checklistHandler := handler.NewChecklistHandler(globalChecklistRepo, globalChecklistItemRepo)
+		tools = handler.NewToolRunner(noteRepo, globalProjectRepo, globalTaskRepo, globalChecklistItemRepo, globalActionRepo, taskHandler, checklistHandler)
+	}
+
+	h := handler.NewChatHandler(noteRepo, tagRepo, globalChatRepo, globalLinkRepo, tools)
+	return h, nil
+}
+
+func setupReportHandler() (handler.ReportHandler, error) {
+	noteRepo, tagRepo, err := getRepository()
+	if err != nil {
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
+	}
+
+	h := handler.NewReportHandler(globalTaskRepo, globalProjectRepo, globalActivityRepo, noteRepo, tagRepo)
+	return h, nil
+}
+
+func setupDedupeHandler() (handler.DedupeHandler, error) {
+	noteRepo, _, err := getRepository()
+	if err != nil {
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
+	}
+
+	h := handler.NewDedupeHandler(noteRepo, globalDedupeRepo)
+	return h, nil
+}
+
+func setupServer(token string) (*server.Server, error) {
+	noteRepo, tagRepo, err := getRepository()
+	if err != nil {
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
+	}
+
+	s := server.New(server.Repositories{
+		Notes:          noteRepo,
+		Tags:           tagRepo,
+		Projects:       globalProjectRepo,
+		Tasks:          globalTaskRepo,
+		Checklists:     globalChecklistRepo,
+		ChecklistItems: globalChecklistItemRepo,
+	}, token)
+	return s, nil
+}
+
+func setupMCPServer(readOnly bool) (*mcp.Server, error) {
+	noteRepo, tagRepo, err := getRepository()
+	if err != nil {
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
+	}
+
+	// Unlike other AI commands, an invalid config is an error here: it holds
+	// the exclude_tags and redact_patterns that keep notes private.
+	config, err := ai.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	s := mcp.New(mcp.Repositories{
+		Notes:          noteRepo,
+		Tags:           tagRepo,
+		Projects:       globalProjectRepo,
+		Tasks:          globalTaskRepo,
+		ChecklistItems: globalChecklistItemRepo,
+		Actions:        globalActionRepo,
+	}, config, readOnly)
+	return s, nil
+}
+
+func setupTUI() (*tui.Model, error) {
+	noteRepo, tagRepo, err := getRepository()
+	if err != nil {
+		return nil, apperr.New(apperr.KindDatabase, fmt.Errorf("failed to connect to database: %w", err))
+	}
+
+	return tui.NewModel(tui.Repositories{
+		Notes:    noteRepo,
+		Tags:     tagRepo,
+		Projects: globalProjectRepo,
+		Tasks:    globalTaskRepo,
+	}, handler.NewEditorHandler()), nil
+}
+
 func executeWithHandler(fn func(handler.Handler) error) error {
 	h, err := setupHandler()
 	if err != nil {
@@ -128,3 +300,84 @@ func executeWithChecklistHandler(fn func(handler.ChecklistHandler) error) error
 
 	return fn(h)
 }
+
+func executeWithAIHandler(fn func(handler.AIHandler) error) error {
+	h, err := setupAIHandler()
+	if err != nil {
+		return fmt.Errorf("failed to setup AI handler: %w", err)
+	}
+
+	return fn(h)
+}
+
+func executeWithSummaryHandler(fn func(handler.SummaryHandler) error) error {
+	h, err := setupSummaryHandler()
+	if err != nil {
+		return fmt.Errorf("failed to setup summary handler: %w", err)
+	}
+
+	return fn(h)
+}
+
+func executeWithActionItemHandler(fn func(handler.ActionItemHandler) error) error {
+	h, err := setupActionItemHandler()
+	if err != nil {
+		return fmt.Errorf("failed to setup action item handler: %w", err)
+	}
+
+	return fn(h)
+}
+
+func executeWithChatHandler(withTools bool, fn func(handler.ChatHandler) error) error {
+	h, err := setupChatHandler(withTools)
+	if err != nil {
+		return fmt.Errorf("failed to setup chat handler: %w", err)
+	}
+
+	return fn(h)
+}
+
+func executeWithReportHandler(fn func(handler.ReportHandler) error) error {
+	h, err := setupReportHandler()
+	if err != nil {
+		return fmt.Errorf("failed to setup report handler: %w", err)
+	}
+
+	return fn(h)
+}
+
+func executeWithDedupeHandler(fn func(handler.DedupeHandler) error) error {
+	h, err := setupDedupeHandler()
+	if err != nil {
+		return fmt.Errorf("failed to setup dedupe handler: %w", err)
+	}
+
+	return fn(h)
+}
+
+func executeWithServer(token string, fn func(*server.Server) error) error {
+	s, err := setupServer(token)
+	if err != nil {
+		return fmt.Errorf("failed to setup server: %w", err)
+	}
+
+	return fn(s)
+}
+
+func executeWithMCPServer(readOnly bool, fn func(*mcp.Server) error) error {
+	s, err := setupMCPServer(readOnly)
+	if err != nil {
+		return fmt.Errorf("failed to setup MCP server: %w", err)
+	}
+
+	return fn(s)
+}
+
+func executeWithTUI(fn func(*tui.Model) error) error {
+	m, err := setupTUI()
+	if err != nil {
+		return fmt.Errorf("failed to setup tui: %w", err)
+	}
+
+	return fn(m)
+}
@@ -1,13 +1,20 @@
 package cmd
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/snip/internal/handler"
 	"github.com/spf13/cobra"
 )
 
+var findSemantic bool
+var findLimit int
+
+func init() {
+	findCmd.Flags().BoolVarP(&findSemantic, "semantic", "s", false, "Search by meaning using the local embedding index")
+	findCmd.Flags().IntVarP(&findLimit, "limit", "l", 10, "Maximum number of results for semantic search")
+}
+
 var findCmd = &cobra.Command{
 	Use:   "find [text]",
 	Short: "Search for notes containing specific text in title or content",
@@ -17,19 +24,31 @@ The search looks through both note titles and content, returning all matches.
 Multiple search terms can be provided and will be joined together. The search
 is case-insensitive and matches partial words.
 
+With --semantic, notes are ranked by meaning instead of exact words: each note
+is embedded into a vector (locally by default, or via SNIP_EMBEDDING_URL) and
+the cosine-similarity results are merged with the full-text matches. The index
+is kept up to date automatically when notes are created or updated.
+
+Flags:
+  --semantic, -s   Rank notes by meaning using the embedding index
+  --limit, -l      Maximum number of semantic results (default 10)
+
 Examples:
   snip find meeting            # Find notes containing "meeting"
   snip find "project ideas"    # Find notes with "project ideas"
   snip find TODO urgent        # Find notes containing "TODO urgent"
   snip find golang             # Find notes about golang
+  snip find --semantic "how do we rotate certs"   # Find notes by meaning
+  snip find -s "deploy checklist" -l 5            # Top 5 semantic matches
 
 Tip: Use quotes for exact phrases, or separate words for broader matching.`,
 	Args: cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithHandler(func(h handler.Handler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithHandler(func(h handler.Handler) error {
+			if findSemantic {
+				return h.SemanticFindNotes(strings.Join(args, " "), findLimit)
+			}
 			return h.FindNotes(strings.Join(args, " "))
-		}); err != nil {
-			fmt.Printf("Error: %v\n", err)
-		}
+		})
 	},
 }
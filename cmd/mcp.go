@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/snip/internal/mcp"
+	"github.com/spf13/cobra"
+)
+
+var mcpReadOnly bool
+
+func init() {
+	mcpCmd.Flags().BoolVar(&mcpReadOnly, "read-only", false, "Only offer the tools that read data")
+	rootCmd.AddCommand(mcpCmd)
+}
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run a Model Context Protocol server over stdio for AI agents",
+	Long: `Run an MCP server on stdin/stdout so AI coding agents can use your notes and tasks.
+
+Tools:
+  search_notes           full-text search, returns snippets
+  get_note               full content of a note
+  create_note            new note with optional tags
+  list_tasks             tasks, by project and status
+  create_task            new task in a project
+  toggle_checklist_item  mark a checklist item done or not done
+
+Notes are also resources, addressed as snip://note/<id>.
+
+Notes with a tag in exclude_tags are never shared, and secrets are masked the
+same way as in AI requests (see ~/.snip/config.json). Every tool call is logged
+with the AI actions. With --read-only the tools that change data are hidden.
+
+The agent starts this command itself. For example, in its MCP configuration:
+
+  {"mcpServers": {"snip": {"command": "snip", "args": ["mcp"]}}}`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Stdout carries the protocol, so everything else goes to stderr,
+		// including the error Execute prints.
+		return executeWithMCPServer(mcpReadOnly, func(s *mcp.Server) error {
+			s.SetLog(os.Stderr)
+			return s.Serve(os.Stdin, os.Stdout)
+		})
+	},
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// outputAnnotation marks the commands that print their results through
+// output.Print and so accept --output json, yaml and table.
+const outputAnnotation = "snip/output"
+
+func init() {
+	for _, c := range []*cobra.Command{
+		listCmd, showCmd, findCmd, recentCmd,
+		projectListCmd, projectShowCmd,
+		taskListCmd, taskShowCmd,
+		checklistListCmd, checklistShowCmd,
+	} {
+		if c.Annotations == nil {
+			c.Annotations = map[string]string{}
+		}
+		c.Annotations[outputAnnotation] = "true"
+	}
+}
+
+// setOutputFormat applies the global --output flag. Commands that only
+// print messages refuse the structured formats rather than ignore them.
+func setOutputFormat(cmd *cobra.Command) error {
+	value, _ := cmd.Flags().GetString("output")
+	format, err := output.ParseFormat(value)
+	if err != nil {
+		return apperr.New(apperr.KindUsage, err)
+	}
+	if format != output.FormatText && cmd.Annotations[outputAnnotation] == "" {
+		return apperr.New(apperr.KindUsage, fmt.Errorf("%s does not support --output %s", cmd.CommandPath(), format))
+	}
+	output.SetFormat(format)
+	return nil
+}
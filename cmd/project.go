@@ -1,20 +1,23 @@
 package cmd
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/snip/internal/apperr"
 	"github.com/snip/internal/handler"
 	"github.com/spf13/cobra"
 )
 
 var projectDescription string
 var projectStatus string
+var projectYes bool
 
 func init() {
 	projectCreateCmd.Flags().StringVarP(&projectDescription, "description", "d", "", "Descrição do projeto")
 	projectUpdateCmd.Flags().StringVarP(&projectDescription, "description", "d", "", "Descrição do projeto")
+	projectAICreateCmd.Flags().StringVarP(&projectDescription, "description", "d", "", "Descrição do projeto")
+	projectAICreateCmd.Flags().BoolVarP(&projectYes, "yes", "y", false, "Criar sem pedir confirmação")
 	projectUpdateCmd.Flags().StringVarP(&projectStatus, "status", "s", "", "Status do projeto (active, completed, archived)")
 	rootCmd.AddCommand(projectCmd)
 }
@@ -29,25 +32,21 @@ var projectCreateCmd = &cobra.Command{
 	Use:   "create [name]",
 	Short: "Criar um novo projeto",
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithProjectHandler(func(h handler.ProjectHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithProjectHandler(func(h handler.ProjectHandler) error {
 			name := strings.Join(args, " ")
 			return h.CreateProject(name, projectDescription)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
 var projectListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Listar projetos",
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithProjectHandler(func(h handler.ProjectHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithProjectHandler(func(h handler.ProjectHandler) error {
 			return h.ListProjects(projectStatus)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -55,16 +54,14 @@ var projectShowCmd = &cobra.Command{
 	Use:   "show [id]",
 	Short: "Mostrar detalhes de um projeto",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithProjectHandler(func(h handler.ProjectHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithProjectHandler(func(h handler.ProjectHandler) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
-				return fmt.Errorf("ID inválido: %s", args[0])
+				return apperr.Invalid("ID inválido: %s", args[0])
 			}
 			return h.ShowProject(id)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -72,17 +69,15 @@ var projectUpdateCmd = &cobra.Command{
 	Use:   "update [id] [name]",
 	Short: "Atualizar um projeto",
 	Args:  cobra.MinimumNArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithProjectHandler(func(h handler.ProjectHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithProjectHandler(func(h handler.ProjectHandler) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
-				return fmt.Errorf("ID inválido: %s", args[0])
+				return apperr.Invalid("ID inválido: %s", args[0])
 			}
 			name := strings.Join(args[1:], " ")
 			return h.UpdateProject(id, name, projectDescription, projectStatus)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -90,30 +85,32 @@ var projectDeleteCmd = &cobra.Command{
 	Use:   "delete [id]",
 	Short: "Deletar um projeto",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithProjectHandler(func(h handler.ProjectHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithProjectHandler(func(h handler.ProjectHandler) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
-				return fmt.Errorf("ID inválido: %s", args[0])
+				return apperr.Invalid("ID inválido: %s", args[0])
 			}
 			return h.DeleteProject(id)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
 var projectAICreateCmd = &cobra.Command{
 	Use:   "ai-create [name]",
 	Short: "Criar projeto com plano gerado por IA",
+	Long: `Gera um plano estruturado (fases, tarefas com prioridade e prazo, checklists),
+mostra uma prévia e, após confirmação, cria o projeto com as tarefas e checklists.
+
+Exemplos:
+  snip project ai-create "Lançar blog" -d "Blog pessoal sobre Go"
+  snip project ai-create "Migração de banco" --yes`,
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithProjectHandler(func(h handler.ProjectHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithProjectHandler(func(h handler.ProjectHandler) error {
 			name := strings.Join(args, " ")
-			return h.CreateProjectWithAI(name, projectDescription)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+			return h.CreateProjectWithAI(name, projectDescription, projectYes)
+		})
 	},
 }
 
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"github.com/snip/internal/handler"
+	"github.com/spf13/cobra"
+)
+
+var reportProjectID int
+var reportAI bool
+var reportSave bool
+
+func init() {
+	for _, c := range []*cobra.Command{reportStandupCmd, reportWeeklyCmd} {
+		c.Flags().IntVarP(&reportProjectID, "project", "p", 0, "Apenas tarefas deste projeto")
+		c.Flags().BoolVar(&reportAI, "ai", false, "Reescrever o relatório em texto corrido com IA")
+		c.Flags().BoolVarP(&reportSave, "save", "s", false, "Salvar o relatório como nota")
+		reportCmd.AddCommand(c)
+	}
+	rootCmd.AddCommand(reportCmd)
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Gerar relatórios a partir da atividade das tarefas",
+	Long: `Gere relatórios em markdown com as tarefas concluídas, iniciadas e atrasadas.
+
+As mudanças de status das tarefas são registradas automaticamente; o relatório
+usa esse histórico. Com --ai o relatório é reescrito em texto corrido e com
+--save ele é salvo como nota.`,
+}
+
+var reportStandupCmd = &cobra.Command{
+	Use:   "standup",
+	Short: "Relatório desde o último dia útil",
+	Long: `Relatório de standup com a atividade desde o último dia útil (sexta-feira
+quando executado na segunda).
+
+Exemplos:
+  snip report standup
+  snip report standup --project 3 --ai
+  snip report standup --save`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithReportHandler(func(h handler.ReportHandler) error {
+			return h.Report(handler.ReportStandup, reportProjectID, reportAI, reportSave)
+		})
+	},
+}
+
+var reportWeeklyCmd = &cobra.Command{
+	Use:   "weekly",
+	Short: "Relatório dos últimos 7 dias",
+	Long: `Relatório semanal com a atividade dos últimos 7 dias.
+
+Exemplos:
+  snip report weekly
+  snip report weekly --project 3 --ai --save
+  snip report weekly > semana.md`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithReportHandler(func(h handler.ReportHandler) error {
+			return h.Report(handler.ReportWeekly, reportProjectID, reportAI, reportSave)
+		})
+	},
+}
@@ -1,6 +1,11 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"strings"
+
+	"github.com/snip/internal/ai"
+	"github.com/spf13/cobra"
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "snip",
@@ -20,13 +25,46 @@ Exemplos rápidos:
   snip project create "Meu Projeto"
   snip task create "Nova Tarefa" --project 1
   snip checklist ai-create "Preparação" --items 5`,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Cobra checks required flags only after this hook, so check them
+		// here while a failure still counts as a usage mistake.
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return err
+		}
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return err
+		}
+
+		// Arguments and flags were accepted; from here on a failure is the
+		// command's, not a usage mistake, so the usage text is not repeated.
+		commandStarted = true
+		cmd.SilenceUsage = true
+
+		// Attribute AI usage to the command that triggered it, e.g. "task ai-create".
+		ai.SetCommand(strings.TrimPrefix(cmd.CommandPath(), "snip "))
+		if noCache, _ := cmd.Flags().GetBool("no-cache"); noCache {
+			ai.DisableCache()
+		}
+		return setOutputFormat(cmd)
+	},
 }
 
-func Execute() error {
-	return rootCmd.Execute()
+// commandStarted is set once cobra has parsed the arguments and is about to
+// run the command.
+var commandStarted bool
+
+// Execute runs the command line and returns the process exit code: 0 on
+// success, otherwise the code of the error's apperr kind.
+func Execute() int {
+	cmd, err := rootCmd.ExecuteC()
+	return exitCode(cmd, err)
 }
 
 func init() {
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Ask the AI again instead of reusing cached answers")
+	rootCmd.PersistentFlags().String("output", "text", "Output format for list, show and find commands: text, json, yaml or table")
+
 	rootCmd.AddCommand(createCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(showCmd)
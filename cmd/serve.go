@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/snip/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var serveAddr string
+var serveToken string
+var serveNoAuth bool
+var serveAllowOrigin string
+var serveOpenAPI bool
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", server.DefaultAddr, "Address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Token clients must send (default: $SNIP_API_TOKEN or ~/.snip/api_token)")
+	serveCmd.Flags().BoolVar(&serveNoAuth, "no-auth", false, "Accept requests without a token")
+	serveCmd.Flags().StringVar(&serveAllowOrigin, "allow-origin", "", "Let browser pages from this origin call the API (CORS)")
+	serveCmd.Flags().BoolVar(&serveOpenAPI, "openapi", false, "Print the OpenAPI document and exit")
+	rootCmd.AddCommand(serveCmd)
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve notes, tags, projects, tasks and checklists over a local REST API",
+	Long: `Start a local HTTP server with JSON endpoints for editor plugins and dashboards.
+
+Every endpoint except /health and /openapi.json needs the header
+"Authorization: Bearer <token>". The token comes from --token, then
+$SNIP_API_TOKEN, then ~/.snip/api_token, which is created on first run.
+
+Endpoints:
+  /notes, /tags, /projects, /tasks, /checklists   list and create
+  /<resource>/{id}                                get, update (PATCH) and delete
+  /projects/{id}/tasks, /checklists/{id}/items    nested resources
+
+Lists take ?limit= and ?offset= and most take ?q= to search. GET /openapi.json
+describes every route, parameter and schema.
+
+Examples:
+  snip serve
+  snip serve --addr 127.0.0.1:8080 --allow-origin http://localhost:3000
+  curl -H "Authorization: Bearer $(cat ~/.snip/api_token)" "127.0.0.1:7777/notes?q=docker"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if serveOpenAPI {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(server.OpenAPI(server.New(server.Repositories{}, "").Routes()))
+		}
+
+		token, err := serveAuthToken()
+		if err != nil {
+			return err
+		}
+
+		return executeWithServer(token, func(s *server.Server) error {
+			s.AllowOrigin(serveAllowOrigin)
+			return listenAndServe(s)
+		})
+	},
+}
+
+func serveAuthToken() (string, error) {
+	switch {
+	case serveNoAuth:
+		return "", nil
+	case serveToken != "":
+		return serveToken, nil
+	}
+
+	token, created, err := server.LoadToken()
+	if err != nil {
+		return "", err
+	}
+	if created {
+		path, _ := server.TokenPath()
+		fmt.Printf("Generated an API token and saved it to %s:\n  %s\n\n", path, token)
+	}
+	return token, nil
+}
+
+func listenAndServe(s *server.Server) error {
+	srv := &http.Server{
+		Addr:              serveAddr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.ListenAndServe()
+	}()
+
+	fmt.Printf("Serving the Snip API on http://%s (Ctrl+C to stop)\n", serveAddr)
+	if serveNoAuth {
+		fmt.Println("Warning: authentication is off, any local process can read and change your notes.")
+	}
+	fmt.Printf("OpenAPI document: http://%s/openapi.json\n", serveAddr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	fmt.Println("\nShutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
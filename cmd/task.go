@@ -1,11 +1,11 @@
 package cmd
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/snip/internal/apperr"
 	"github.com/snip/internal/handler"
 	"github.com/spf13/cobra"
 )
@@ -15,6 +15,9 @@ var taskStatus string
 var taskPriority string
 var taskDueDate string
 var taskProjectID int
+var taskBreakdownAs string
+var taskBreakdownSteps int
+var taskYes bool
 
 func init() {
 	taskCreateCmd.Flags().StringVarP(&taskDescription, "description", "d", "", "Descrição da tarefa")
@@ -30,6 +33,10 @@ func init() {
 	taskUpdateCmd.Flags().StringVarP(&taskPriority, "priority", "p", "", "Prioridade (low, medium, high)")
 	taskUpdateCmd.Flags().StringVarP(&taskDueDate, "due", "", "", "Data de vencimento (YYYY-MM-DD)")
 	
+	taskBreakdownCmd.Flags().StringVarP(&taskBreakdownAs, "as", "", "tasks", "Salvar como subtarefas (tasks) ou checklist (checklist)")
+	taskBreakdownCmd.Flags().IntVarP(&taskBreakdownSteps, "steps", "n", 8, "Número máximo de passos")
+	taskBreakdownCmd.Flags().BoolVarP(&taskYes, "yes", "y", false, "Salvar sem pedir confirmação")
+
 	rootCmd.AddCommand(taskCmd)
 }
 
@@ -43,37 +50,34 @@ var taskCreateCmd = &cobra.Command{
 	Use:   "create [title]",
 	Short: "Criar uma nova tarefa",
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithTaskHandler(func(h handler.TaskHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithTaskHandler(func(h handler.TaskHandler) error {
 			title := strings.Join(args, " ")
 			var dueDate *time.Time
 			if taskDueDate != "" {
 				parsed, err := time.Parse("2006-01-02", taskDueDate)
 				if err != nil {
-					return fmt.Errorf("data inválida: %w", err)
+					return apperr.Invalid("data inválida: %w", err)
 				}
 				dueDate = &parsed
 			}
-			return h.CreateTask(taskProjectID, title, taskDescription, taskPriority, dueDate)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+			_, err := h.CreateTask(taskProjectID, title, taskDescription, taskPriority, dueDate)
+			return err
+		})
 	},
 }
 
 var taskListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Listar tarefas",
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithTaskHandler(func(h handler.TaskHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithTaskHandler(func(h handler.TaskHandler) error {
 			projectID := 0
 			if taskProjectID > 0 {
 				projectID = taskProjectID
 			}
 			return h.ListTasks(projectID, taskStatus)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -81,16 +85,14 @@ var taskShowCmd = &cobra.Command{
 	Use:   "show [id]",
 	Short: "Mostrar detalhes de uma tarefa",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithTaskHandler(func(h handler.TaskHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithTaskHandler(func(h handler.TaskHandler) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
-				return fmt.Errorf("ID inválido: %s", args[0])
+				return apperr.Invalid("ID inválido: %s", args[0])
 			}
 			return h.ShowTask(id)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -98,25 +100,23 @@ var taskUpdateCmd = &cobra.Command{
 	Use:   "update [id] [title]",
 	Short: "Atualizar uma tarefa",
 	Args:  cobra.MinimumNArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithTaskHandler(func(h handler.TaskHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithTaskHandler(func(h handler.TaskHandler) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
-				return fmt.Errorf("ID inválido: %s", args[0])
+				return apperr.Invalid("ID inválido: %s", args[0])
 			}
 			title := strings.Join(args[1:], " ")
 			var dueDate *time.Time
 			if taskDueDate != "" {
 				parsed, err := time.Parse("2006-01-02", taskDueDate)
 				if err != nil {
-					return fmt.Errorf("data inválida: %w", err)
+					return apperr.Invalid("data inválida: %w", err)
 				}
 				dueDate = &parsed
 			}
 			return h.UpdateTask(id, title, taskDescription, taskStatus, taskPriority, dueDate)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -124,16 +124,14 @@ var taskDeleteCmd = &cobra.Command{
 	Use:   "delete [id]",
 	Short: "Deletar uma tarefa",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithTaskHandler(func(h handler.TaskHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithTaskHandler(func(h handler.TaskHandler) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
-				return fmt.Errorf("ID inválido: %s", args[0])
+				return apperr.Invalid("ID inválido: %s", args[0])
 			}
 			return h.DeleteTask(id)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
 	},
 }
 
@@ -141,16 +139,36 @@ var taskToggleCmd = &cobra.Command{
 	Use:   "toggle [id]",
 	Short: "Marcar/desmarcar tarefa como concluída",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := executeWithTaskHandler(func(h handler.TaskHandler) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithTaskHandler(func(h handler.TaskHandler) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
-				return fmt.Errorf("ID inválido: %s", args[0])
+				return apperr.Invalid("ID inválido: %s", args[0])
 			}
 			return h.ToggleTaskComplete(id)
-		}); err != nil {
-			fmt.Printf("Erro: %v\n", err)
-		}
+		})
+	},
+}
+
+var taskBreakdownCmd = &cobra.Command{
+	Use:   "ai-breakdown [id]",
+	Short: "Dividir uma tarefa em passos menores com IA",
+	Long: `Envia a tarefa e o contexto do projeto para a IA e cria os passos sugeridos
+como subtarefas ou como uma checklist vinculada à tarefa.
+
+Exemplos:
+  snip task ai-breakdown 12
+  snip task ai-breakdown 12 --as checklist --steps 5
+  snip task ai-breakdown 12 --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithTaskHandler(func(h handler.TaskHandler) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return apperr.Invalid("ID inválido: %s", args[0])
+			}
+			return h.BreakdownTaskWithAI(id, taskBreakdownAs, taskBreakdownSteps, taskYes)
+		})
 	},
 }
 
@@ -161,5 +179,6 @@ func init() {
 	taskCmd.AddCommand(taskUpdateCmd)
 	taskCmd.AddCommand(taskDeleteCmd)
 	taskCmd.AddCommand(taskToggleCmd)
+	taskCmd.AddCommand(taskBreakdownCmd)
 }
 
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/snip/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse notes and the project board in a full-screen interface",
+	Long: `Open a full-screen interface with two screens; tab switches between them.
+
+Notes: a tag sidebar, the note list and a Markdown preview of the selected note.
+  j/k      move                 /        search as you type (esc clears)
+  t, h/l   tags or list         ctrl+d/u scroll the preview
+  n        new note (editor)    e, enter edit in the editor
+  r        rename               d        delete
+
+Board: the tasks of a project in Pendentes, Em andamento and Concluídas columns.
+  [ ]      previous/next project    h/l, j/k  move
+  space    toggle completed         < >       move to the previous/next column
+  n        new task in the column   e         edit the description
+  d        delete
+
+Press ? for all keys and q to quit. Notes and tasks open in the same editor as
+snip create and snip update (see snip editor).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeWithTUI(tui.Run)
+	},
+}
@@ -0,0 +1,30 @@
+package action
+
+import "time"
+
+const (
+	StatusExecuted = "executed"
+	StatusDeclined = "declined"
+	StatusFailed   = "failed"
+)
+
+// Action records one tool call the AI made: what it asked for, whether it
+// ran and what came back.
+type Action struct {
+	ID        int       `json:"id"`
+	SessionID *int      `json:"session_id,omitempty"`
+	Tool      string    `json:"tool"`
+	Arguments string    `json:"arguments"`
+	Status    string    `json:"status"` // executed, declined, failed
+	Result    string    `json:"result"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewAction(sessionID *int, tool, arguments string) *Action {
+	return &Action{
+		SessionID: sessionID,
+		Tool:      tool,
+		Arguments: arguments,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,16 @@
+package activity
+
+import "time"
+
+// TaskActivity is one status transition of a task, recorded by a trigger on
+// the tasks table. The title is copied so the entry survives the task being
+// deleted.
+type TaskActivity struct {
+	ID         int       `json:"id"`
+	TaskID     int       `json:"task_id"`
+	ProjectID  int       `json:"project_id"`
+	TaskTitle  string    `json:"task_title"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,96 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidActionItems = errors.New("invalid action items")
+
+const maxActionItems = 30
+
+// ActionItems is the structured answer for `ai-actions`. An empty list is a
+// valid answer for notes without follow-ups.
+type ActionItems struct {
+	Items []ActionItem `json:"items"`
+}
+
+type ActionItem struct {
+	Title string `json:"title"`
+	Owner string `json:"owner"`
+	Due   string `json:"due"` // YYYY-MM-DD or empty
+}
+
+// DueDate parses Due. It is nil when the item has no due date.
+func (i ActionItem) DueDate() *time.Time {
+	if i.Due == "" {
+		return nil
+	}
+	due, err := time.ParseInLocation("2006-01-02", i.Due, time.Local)
+	if err != nil {
+		return nil
+	}
+	return &due
+}
+
+// ParseActionItems decodes and validates extracted action items, trimming
+// fields and rejecting malformed due dates.
+func ParseActionItems(raw string) (*ActionItems, error) {
+	var items ActionItems
+	if err := decodeStrict(raw, &items); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidActionItems, err)
+	}
+
+	if len(items.Items) > maxActionItems {
+		return nil, fmt.Errorf("%w: too many items (%d, max %d)", ErrInvalidActionItems, len(items.Items), maxActionItems)
+	}
+
+	for i := range items.Items {
+		item := &items.Items[i]
+		item.Title = strings.TrimSpace(item.Title)
+		item.Owner = strings.TrimSpace(item.Owner)
+		item.Due = strings.TrimSpace(item.Due)
+		if item.Title == "" {
+			return nil, fmt.Errorf("%w: item %d has no title", ErrInvalidActionItems, i+1)
+		}
+		if item.Due != "" {
+			if _, err := time.Parse("2006-01-02", item.Due); err != nil {
+				return nil, fmt.Errorf("%w: item %q has invalid due date %q (use YYYY-MM-DD)", ErrInvalidActionItems, item.Title, item.Due)
+			}
+		}
+	}
+
+	return &items, nil
+}
+
+// ExtractActionItems finds the follow-ups agreed in a note, typically meeting
+// minutes. today anchors relative dates such as "next Friday".
+func (g *GroqClient) ExtractActionItems(ctx context.Context, title, content string, today time.Time) (*ActionItems, error) {
+	// Items can come from anywhere in the note, so it is never cut.
+	if tokens, limit := EstimateTokens(content), g.contextRoom(title); tokens > limit {
+		return nil, fmt.Errorf("note is too long to extract action items (~%d tokens, limit %d)", tokens, limit)
+	}
+
+	messages, err := g.renderPrompt("action_items", map[string]any{
+		"Today":   today,
+		"Title":   title,
+		"Content": content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var items *ActionItems
+	err = g.chatJSONWithRepair(ctx, messages, 2000, 0.2, func(result string) error {
+		var err error
+		items, err = ParseActionItems(result)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
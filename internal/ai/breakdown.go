@@ -0,0 +1,96 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidBreakdown = errors.New("invalid task breakdown")
+
+const MaxBreakdownSteps = 20
+
+// TaskBreakdown is the structured answer for `task ai-breakdown`. Steps are
+// listed in the order they should be done.
+type TaskBreakdown struct {
+	Steps []BreakdownStep `json:"steps"`
+}
+
+type BreakdownStep struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Priority    string `json:"priority"`
+}
+
+// ParseTaskBreakdown decodes and validates a breakdown, normalizing
+// priorities and dropping steps beyond maxSteps.
+func ParseTaskBreakdown(raw string, maxSteps int) (*TaskBreakdown, error) {
+	var breakdown TaskBreakdown
+	if err := decodeStrict(raw, &breakdown); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBreakdown, err)
+	}
+
+	if len(breakdown.Steps) == 0 {
+		return nil, fmt.Errorf("%w: no steps", ErrInvalidBreakdown)
+	}
+
+	for i := range breakdown.Steps {
+		step := &breakdown.Steps[i]
+		step.Title = strings.TrimSpace(step.Title)
+		step.Description = strings.TrimSpace(step.Description)
+		if step.Title == "" {
+			return nil, fmt.Errorf("%w: step %d has no title", ErrInvalidBreakdown, i+1)
+		}
+
+		priority, ok := normalizePriority(step.Priority)
+		if !ok {
+			return nil, fmt.Errorf("%w: step %q has invalid priority %q", ErrInvalidBreakdown, step.Title, step.Priority)
+		}
+		step.Priority = priority
+	}
+
+	if maxSteps > 0 && len(breakdown.Steps) > maxSteps {
+		breakdown.Steps = breakdown.Steps[:maxSteps]
+	}
+
+	return &breakdown, nil
+}
+
+// BreakdownProject describes the project of a task being broken down so the
+// steps do not duplicate existing work.
+type BreakdownProject struct {
+	Name        string
+	Description string
+	OtherTasks  []string // "title [status]"
+	MoreTasks   int      // other tasks left out of OtherTasks
+}
+
+// GenerateTaskBreakdown splits a task into ordered steps. project may be nil
+// when the task's project is unknown.
+func (g *GroqClient) GenerateTaskBreakdown(ctx context.Context, title, description string, project *BreakdownProject, maxSteps int) (*TaskBreakdown, error) {
+	if maxSteps <= 0 || maxSteps > MaxBreakdownSteps {
+		maxSteps = MaxBreakdownSteps
+	}
+
+	messages, err := g.renderPrompt("task_breakdown", map[string]any{
+		"MaxSteps":    maxSteps,
+		"Title":       title,
+		"Description": description,
+		"Project":     project,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var breakdown *TaskBreakdown
+	err = g.chatJSONWithRepair(ctx, messages, 2000, 0.4, func(result string) error {
+		var err error
+		breakdown, err = ParseTaskBreakdown(result, maxSteps)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return breakdown, nil
+}
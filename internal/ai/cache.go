@@ -0,0 +1,79 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/snip/internal/cache"
+)
+
+// DefaultCacheTTLHours is how long cached answers are reused when the config
+// does not set "cache_ttl_hours".
+const DefaultCacheTTLHours = 7 * 24
+
+// ResponseCache stores answers by request hash. It is satisfied by
+// repository.CacheRepository and registered once at startup.
+type ResponseCache interface {
+	Get(key string, since time.Time) (*cache.Entry, error)
+	Put(e *cache.Entry) error
+}
+
+var (
+	responseCache ResponseCache
+	cacheDisabled bool
+)
+
+// SetResponseCache enables the response cache for every client. Passing nil
+// disables it.
+func SetResponseCache(c ResponseCache) {
+	responseCache = c
+}
+
+// DisableCache bypasses the cache for the rest of the process (--no-cache):
+// nothing is read from it and nothing is stored.
+func DisableCache() {
+	cacheDisabled = true
+}
+
+// CacheKey hashes everything that decides the answer: model, messages
+// (already redacted), temperature, token limit and response format. Streamed
+// and non-streamed requests share entries.
+func CacheKey(reqBody ChatRequest) string {
+	reqBody.Stream = false
+	data, _ := json.Marshal(reqBody)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheable excludes requests with tools: their answers trigger actions that
+// must not be replayed.
+func (g *GroqClient) cacheable(reqBody ChatRequest) bool {
+	return responseCache != nil && !cacheDisabled && len(reqBody.Tools) == 0 && g.config.CacheTTL() > 0
+}
+
+// cachedResponse returns a stored answer for the request, if any. Cache
+// failures are treated as misses.
+func (g *GroqClient) cachedResponse(reqBody ChatRequest) (string, bool) {
+	if !g.cacheable(reqBody) {
+		return "", false
+	}
+	e, err := responseCache.Get(CacheKey(reqBody), time.Now().Add(-g.config.CacheTTL()))
+	if err != nil {
+		return "", false
+	}
+	fmt.Fprintln(os.Stderr, "⚡ Using cached AI answer (--no-cache to ask again)")
+	return e.Response, true
+}
+
+func (g *GroqClient) storeResponse(reqBody ChatRequest, response string) {
+	if !g.cacheable(reqBody) || response == "" {
+		return
+	}
+	if err := responseCache.Put(cache.NewEntry(CacheKey(reqBody), reqBody.Model, response)); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to cache AI answer: %v\n", err)
+	}
+}
@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// CodeBlock is a fenced code block from a markdown answer.
+type CodeBlock struct {
+	Language string // the fence info string, lowercased; may be empty
+	Code     string
+}
+
+// ExtractCodeBlocks returns the fenced code blocks (``` or ~~~) in markdown,
+// in order. A block left open at the end, as in a cancelled stream, is kept.
+func ExtractCodeBlocks(markdown string) []CodeBlock {
+	var blocks []CodeBlock
+	var current *CodeBlock
+	var fence string
+	var lines []string
+
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if current == nil {
+			if marker := fenceMarker(trimmed); marker != "" {
+				fence = marker
+				info := strings.Fields(strings.TrimLeft(trimmed, marker[:1]))
+				current = &CodeBlock{}
+				if len(info) > 0 {
+					current.Language = strings.ToLower(info[0])
+				}
+				lines = nil
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			current.Code = strings.Join(lines, "\n")
+			blocks = append(blocks, *current)
+			current = nil
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if current != nil && len(lines) > 0 {
+		current.Code = strings.TrimRight(strings.Join(lines, "\n"), "\n")
+		blocks = append(blocks, *current)
+	}
+	return blocks
+}
+
+// fenceMarker returns the opening fence of line (three or more backticks or
+// tildes), or "" when the line does not open a code block.
+func fenceMarker(line string) string {
+	for _, c := range []string{"`", "~"} {
+		n := len(line) - len(strings.TrimLeft(line, c))
+		if n >= 3 {
+			return line[:n]
+		}
+	}
+	return ""
+}
+
+// languageAliases maps fence labels and file extensions to the names used
+// with `ai-code --lang`.
+var languageAliases = map[string]string{
+	"golang":     "go",
+	"py":         "python",
+	"python3":    "python",
+	"js":         "javascript",
+	"jsx":        "javascript",
+	"mjs":        "javascript",
+	"ts":         "typescript",
+	"tsx":        "typescript",
+	"rb":         "ruby",
+	"rs":         "rust",
+	"sh":         "bash",
+	"shell":      "bash",
+	"zsh":        "bash",
+	"yml":        "yaml",
+	"kt":         "kotlin",
+	"cs":         "csharp",
+	"c#":         "csharp",
+	"cpp":        "c++",
+	"cc":         "c++",
+	"hpp":        "c++",
+	"h":          "c",
+	"md":         "markdown",
+	"ps1":        "powershell",
+	"dockerfile": "docker",
+}
+
+// NormalizeLanguage lowercases a language name or file extension and
+// resolves common aliases, so "golang", "Go" and ".go" are all "go".
+func NormalizeLanguage(name string) string {
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "."))
+	if alias, ok := languageAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// SelectCodeBlocks picks the blocks to write to path: the ones in language
+// (or in the language of the file extension), falling back to every block
+// when none is labelled that way.
+func SelectCodeBlocks(blocks []CodeBlock, language, path string) []CodeBlock {
+	wanted := map[string]bool{NormalizeLanguage(language): true}
+	if ext := filepath.Ext(path); ext != "" {
+		wanted[NormalizeLanguage(ext)] = true
+	}
+
+	var selected []CodeBlock
+	for _, b := range blocks {
+		if wanted[NormalizeLanguage(b.Language)] {
+			selected = append(selected, b)
+		}
+	}
+	if len(selected) == 0 {
+		return blocks
+	}
+	return selected
+}
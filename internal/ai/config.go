@@ -1,9 +1,187 @@
 package ai
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const DefaultEmbeddingModel = "text-embedding-3-small"
+
+const (
+	DefaultMaxAttempts    = 4
+	DefaultTimeoutSeconds = 30
+)
+
+// Config holds the AI settings read from ~/.snip/config.json. Every field is
+// optional; missing values fall back to the defaults above.
+type Config struct {
+	Model            string           `json:"model,omitempty"`
+	MaxAttempts      int              `json:"max_attempts,omitempty"`
+	TimeoutSeconds   int              `json:"timeout_seconds,omitempty"`
+	Prices           map[string]Price `json:"prices,omitempty"`
+	MonthlyBudgetUSD float64          `json:"monthly_budget_usd,omitempty"`
+	// AutoTagOnCreate runs the ai-tag suggestions after `snip create` when
+	// the note was created without tags.
+	AutoTagOnCreate bool `json:"auto_tag_on_create,omitempty"`
+	// Language selects the prompt templates and the language answers are
+	// requested in: "pt" (default) or "en".
+	Language string `json:"language,omitempty"`
+	// RedactPatterns are extra regular expressions, by name, for secrets to
+	// mask before anything is sent to the provider. When a pattern has a
+	// capture group only the group is masked.
+	RedactPatterns map[string]string `json:"redact_patterns,omitempty"`
+	// ExcludeTags keeps notes with any of these tags out of AI requests.
+	ExcludeTags []string `json:"exclude_tags,omitempty"`
+	// CacheTTLHours is how long identical requests are answered from the
+	// response cache. A negative value disables the cache.
+	CacheTTLHours int `json:"cache_ttl_hours,omitempty"`
+	// ContextBudgets caps, by model, how many prompt tokens a request may
+	// use. Notes sent as context are chunked, ranked and cut to fit.
+	ContextBudgets map[string]int `json:"context_budgets,omitempty"`
+}
+
+// Price is the cost in USD per million tokens for a model.
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// DefaultPrices are Groq's published on-demand prices at the time of writing.
+// Override or extend them with "prices" in the config file.
+var DefaultPrices = map[string]Price{
+	"openai/gpt-oss-120b":     {Input: 0.15, Output: 0.75},
+	"openai/gpt-oss-20b":      {Input: 0.10, Output: 0.50},
+	"llama-3.3-70b-versatile": {Input: 0.59, Output: 0.79},
+	"llama-3.1-8b-instant":    {Input: 0.05, Output: 0.08},
+}
+
+// DefaultContextBudget is the prompt budget, in tokens, for models without an
+// entry in DefaultContextBudgets or the config file.
+const DefaultContextBudget = 8000
+
+// DefaultContextBudgets stay well below each model's context window so the
+// answer fits too and requests stay inside Groq's per-minute token limits.
+var DefaultContextBudgets = map[string]int{
+	"openai/gpt-oss-120b":     24000,
+	"openai/gpt-oss-20b":      24000,
+	"llama-3.3-70b-versatile": 12000,
+	"llama-3.1-8b-instant":    6000,
+}
+
+func ConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".snip", "config.json"), nil
+}
+
+// LoadConfig reads the config file if present and applies environment
+// overrides (SNIP_AI_MODEL, SNIP_AI_MAX_ATTEMPTS, SNIP_AI_TIMEOUT,
+// SNIP_AI_LANGUAGE).
+func LoadConfig() (*Config, error) {
+	cfg := &Config{}
+
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+
+	if model := os.Getenv("SNIP_AI_MODEL"); model != "" {
+		cfg.Model = model
+	}
+	if v, err := strconv.Atoi(os.Getenv("SNIP_AI_MAX_ATTEMPTS")); err == nil {
+		cfg.MaxAttempts = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("SNIP_AI_TIMEOUT")); err == nil {
+		cfg.TimeoutSeconds = v
+	}
+	if lang := os.Getenv("SNIP_AI_LANGUAGE"); lang != "" {
+		cfg.Language = lang
+	}
+
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.TimeoutSeconds <= 0 {
+		cfg.TimeoutSeconds = DefaultTimeoutSeconds
+	}
+	if cfg.CacheTTLHours == 0 {
+		cfg.CacheTTLHours = DefaultCacheTTLHours
+	}
+	cfg.Language = strings.ToLower(strings.TrimSpace(cfg.Language))
+	if cfg.Language == "" {
+		cfg.Language = DefaultLanguage
+	}
+	if !isLanguage(cfg.Language) {
+		return nil, fmt.Errorf("invalid language %q (use %s)", cfg.Language, strings.Join(Languages, " or "))
+	}
+	if _, err := compileRedactPatterns(cfg.RedactPatterns); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) Timeout() time.Duration {
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// CacheTTL is zero when the response cache is disabled.
+func (c *Config) CacheTTL() time.Duration {
+	if c.CacheTTLHours <= 0 {
+		return 0
+	}
+	return time.Duration(c.CacheTTLHours) * time.Hour
+}
+
+// PriceFor returns the configured price for model, falling back to
+// DefaultPrices. Unknown models are reported as not found and cost nothing.
+func (c *Config) PriceFor(model string) (Price, bool) {
+	if p, ok := c.Prices[model]; ok {
+		return p, true
+	}
+	p, ok := DefaultPrices[model]
+	return p, ok
+}
+
+// ContextBudget returns the configured prompt budget for model, falling back
+// to DefaultContextBudgets and then DefaultContextBudget.
+func (c *Config) ContextBudget(model string) int {
+	if c != nil {
+		if b, ok := c.ContextBudgets[model]; ok && b > 0 {
+			return b
+		}
+	}
+	if b, ok := DefaultContextBudgets[model]; ok {
+		return b
+	}
+	return DefaultContextBudget
+}
+
+func (c *Config) Cost(model string, promptTokens, completionTokens int) float64 {
+	p, _ := c.PriceFor(model)
+	return (float64(promptTokens)*p.Input + float64(completionTokens)*p.Output) / 1_000_000
+}
+
 // GetAPIKey retrieves the Groq API key from environment variable or returns default
 func GetAPIKey() string {
 	apiKey := os.Getenv("GROQ_API_KEY")
@@ -13,3 +191,44 @@ func GetAPIKey() string {
 	}
 	return apiKey
 }
+
+// GetAPIURL returns the chat completions endpoint. GROQ_API_URL points the
+// client at another OpenAI-compatible server, such as a local fake in tests.
+func GetAPIURL() string {
+	if url := os.Getenv("GROQ_API_URL"); url != "" {
+		return url
+	}
+	return GroqAPIURL
+}
+
+// GetReplayDir returns SNIP_AI_REPLAY: when set, AI calls are answered from
+// recorded fixtures in that directory and no API key is needed.
+func GetReplayDir() string {
+	return os.Getenv("SNIP_AI_REPLAY")
+}
+
+// GetRecordDir returns SNIP_AI_RECORD: when set, every AI call is saved as a
+// fixture in that directory.
+func GetRecordDir() string {
+	return os.Getenv("SNIP_AI_RECORD")
+}
+
+// GetEmbeddingURL returns the OpenAI-compatible embeddings endpoint, if any.
+// When empty, semantic search uses the local hashed embedder.
+func GetEmbeddingURL() string {
+	return os.Getenv("SNIP_EMBEDDING_URL")
+}
+
+func GetEmbeddingModel() string {
+	if model := os.Getenv("SNIP_EMBEDDING_MODEL"); model != "" {
+		return model
+	}
+	return DefaultEmbeddingModel
+}
+
+func GetEmbeddingAPIKey() string {
+	if apiKey := os.Getenv("SNIP_EMBEDDING_API_KEY"); apiKey != "" {
+		return apiKey
+	}
+	return GetAPIKey()
+}
@@ -0,0 +1,154 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// contextChunkTokens is the size notes are split into before packing, so
+	// the relevant part of a long note can be sent without the rest.
+	contextChunkTokens = 400
+
+	// promptReserveTokens is left for the instructions of a prompt around
+	// the packed context.
+	promptReserveTokens = 600
+)
+
+// ContextDocument is a note offered as context for a prompt.
+type ContextDocument struct {
+	Title   string
+	Content string
+}
+
+type contextChunk struct {
+	doc   int
+	index int
+	text  string
+	score float64
+}
+
+// PackContext fits docs into budget tokens. Notes are split into chunks,
+// the chunks are ranked by how many of the query terms they contain (with
+// the note title counting for every chunk of the note, and earlier notes
+// winning ties) and the best ones are kept while they fit. Each kept note is
+// returned as "Title: text", with its chunks in their original order and
+// gaps marked with "[...]". The second result counts the notes left out.
+func PackContext(query string, docs []ContextDocument, budget int) ([]string, int) {
+	terms := queryTerms(query)
+
+	var chunks []*contextChunk
+	for i, doc := range docs {
+		titleScore := termScore(terms, doc.Title)
+		for j, text := range ChunkTokens(doc.Content, contextChunkTokens) {
+			chunks = append(chunks, &contextChunk{
+				doc:   i,
+				index: j,
+				text:  text,
+				score: termScore(terms, text) + 2*titleScore,
+			})
+		}
+		if strings.TrimSpace(doc.Content) == "" && doc.Title != "" {
+			chunks = append(chunks, &contextChunk{doc: i, score: 2 * titleScore})
+		}
+	}
+
+	ranked := append([]*contextChunk{}, chunks...)
+	sort.SliceStable(ranked, func(a, b int) bool {
+		if ranked[a].score != ranked[b].score {
+			return ranked[a].score > ranked[b].score
+		}
+		if ranked[a].doc != ranked[b].doc {
+			return ranked[a].doc < ranked[b].doc
+		}
+		return ranked[a].index < ranked[b].index
+	})
+
+	kept := make(map[*contextChunk]bool)
+	titled := make(map[int]bool)
+	used := 0
+	for _, c := range ranked {
+		// The separator and a possible "[...]" before the chunk.
+		cost := EstimateTokens(c.text) + 4
+		if !titled[c.doc] {
+			cost += EstimateTokens(docs[c.doc].Title) + 2
+		}
+		if used+cost > budget {
+			// Keep at least part of the best chunk rather than nothing.
+			if len(kept) == 0 && budget-used > contextChunkTokens/4 {
+				c.text = TruncateTokens(c.text, budget-used-EstimateTokens(docs[c.doc].Title)-10)
+				kept[c] = true
+				titled[c.doc] = true
+				used = budget
+			}
+			continue
+		}
+		kept[c] = true
+		titled[c.doc] = true
+		used += cost
+	}
+
+	var packed []string
+	for i, doc := range docs {
+		if !titled[i] {
+			continue
+		}
+		var parts []string
+		next, last := 0, -1
+		for _, c := range chunks {
+			if c.doc != i {
+				continue
+			}
+			last = c.index
+			if !kept[c] {
+				continue
+			}
+			if c.index != next {
+				parts = append(parts, "[...]")
+			}
+			parts = append(parts, c.text)
+			next = c.index + 1
+		}
+		if len(parts) > 0 && next <= last && !strings.HasSuffix(parts[len(parts)-1], "[...]") {
+			parts = append(parts, "[...]")
+		}
+		packed = append(packed, strings.TrimSpace(fmt.Sprintf("%s: %s", doc.Title, strings.Join(parts, "\n"))))
+	}
+	return packed, len(docs) - len(packed)
+}
+
+// queryTerms lowercases query into words of three or more characters.
+func queryTerms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) >= 3 && !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// termScore counts the query terms found in text, with diminishing returns
+// for repeated occurrences.
+func termScore(terms []string, text string) float64 {
+	if len(terms) == 0 || text == "" {
+		return 0
+	}
+	lower := strings.ToLower(text)
+	score := 0.0
+	for _, term := range terms {
+		switch n := strings.Count(lower, term); {
+		case n == 1:
+			score++
+		case n > 1:
+			score += 1.5
+		}
+	}
+	return score
+}
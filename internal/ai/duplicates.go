@@ -0,0 +1,55 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidDuplicateVerdict = errors.New("invalid duplicate verdict")
+
+// DuplicateVerdict is the model's opinion on whether two notes cover the
+// same thing, for `snip dedupe --ai`.
+type DuplicateVerdict struct {
+	Duplicate bool   `json:"duplicate"`
+	Reason    string `json:"reason"`
+}
+
+func ParseDuplicateVerdict(raw string) (*DuplicateVerdict, error) {
+	var verdict DuplicateVerdict
+	if err := decodeStrict(raw, &verdict); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDuplicateVerdict, err)
+	}
+	verdict.Reason = strings.TrimSpace(verdict.Reason)
+	if verdict.Reason == "" {
+		return nil, fmt.Errorf("%w: missing reason", ErrInvalidDuplicateVerdict)
+	}
+	return &verdict, nil
+}
+
+// CompareNotes asks whether a and b are duplicates. Each note gets half of
+// the context budget.
+func (g *GroqClient) CompareNotes(ctx context.Context, a, b ContextDocument) (*DuplicateVerdict, error) {
+	room := g.contextRoom(a.Title, b.Title) / 2
+	messages, err := g.renderPrompt("duplicates", map[string]any{
+		"TitleA":   a.Title,
+		"ContentA": TruncateTokens(a.Content, room),
+		"TitleB":   b.Title,
+		"ContentB": TruncateTokens(b.Content, room),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var verdict *DuplicateVerdict
+	err = g.chatJSONWithRepair(ctx, messages, 200, 0.1, func(result string) error {
+		var err error
+		verdict, err = ParseDuplicateVerdict(result)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return verdict, nil
+}
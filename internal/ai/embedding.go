@@ -0,0 +1,247 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	HashEmbeddingDims = 512
+	hashTrigramWeight = 0.5
+)
+
+// EmbeddingProvider turns texts into fixed-size vectors for semantic search.
+type EmbeddingProvider interface {
+	// Name identifies the provider and model; vectors from different
+	// providers are never compared with each other.
+	Name() string
+	Embed(texts []string) ([][]float32, error)
+}
+
+// NewEmbeddingProvider returns the remote provider when SNIP_EMBEDDING_URL is
+// set, falling back to the local hashed embedder so search works offline.
+func NewEmbeddingProvider() EmbeddingProvider {
+	if url := GetEmbeddingURL(); url != "" {
+		// The config only adds redact_patterns; the built-in rules apply
+		// without it.
+		cfg, _ := LoadConfig()
+		return &RemoteEmbedder{
+			apiKey:  GetEmbeddingAPIKey(),
+			model:   GetEmbeddingModel(),
+			baseURL: url,
+			config:  cfg,
+			client: &http.Client{
+				Timeout: 30 * time.Second,
+			},
+		}
+	}
+	return NewHashEmbedder(HashEmbeddingDims)
+}
+
+// HashEmbedder builds deterministic vectors from hashed term frequencies of
+// words and character trigrams, so "certs" and "certificates" still overlap.
+type HashEmbedder struct {
+	dims int
+}
+
+func NewHashEmbedder(dims int) *HashEmbedder {
+	return &HashEmbedder{dims: dims}
+}
+
+func (e *HashEmbedder) Name() string {
+	return fmt.Sprintf("local-hash-%d", e.dims)
+}
+
+func (e *HashEmbedder) Embed(texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		vectors = append(vectors, e.embed(text))
+	}
+	return vectors, nil
+}
+
+func (e *HashEmbedder) embed(text string) []float32 {
+	features := make(map[string]float64)
+	for _, token := range Tokenize(text) {
+		features["w:"+token]++
+		padded := []rune("#" + token + "#")
+		for i := 0; i+3 <= len(padded); i++ {
+			features["c:"+string(padded[i:i+3])] += hashTrigramWeight
+		}
+	}
+
+	vector := make([]float32, e.dims)
+	for feature, tf := range features {
+		h := fnv.New32a()
+		h.Write([]byte(feature))
+		sum := h.Sum32()
+
+		weight := 1 + math.Log(tf)
+		if tf < 1 {
+			weight = tf
+		}
+		if sum&(1<<31) != 0 {
+			weight = -weight
+		}
+		vector[int(sum%uint32(e.dims))] += float32(weight)
+	}
+
+	return normalize(vector)
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "do": true, "for": true, "from": true, "how": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "the": true, "to": true, "we": true,
+	"what": true, "with": true, "you": true, "i": true, "our": true, "this": true, "that": true,
+	"o": true, "os": true, "de": true, "da": true, "e": true, "em": true, "um": true,
+	"uma": true, "para": true, "com": true, "que": true, "no": true, "na": true, "por": true,
+	"se": true, "como": true,
+}
+
+// Tokenize lowercases text and splits it into words, dropping stop words.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if stopWords[field] {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+// CosineSimilarity assumes nothing about normalization and returns 0 for
+// vectors of different sizes.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func normalize(vector []float32) []float32 {
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return vector
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] = float32(float64(vector[i]) / norm)
+	}
+	return vector
+}
+
+// RemoteEmbedder calls an OpenAI-compatible /embeddings endpoint.
+type RemoteEmbedder struct {
+	apiKey  string
+	model   string
+	baseURL string
+	config  *Config
+	client  *http.Client
+}
+
+type embeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+func (e *RemoteEmbedder) Name() string {
+	return "remote-" + e.model
+}
+
+// ExcludesNote reports whether a note must not be sent to the provider: the
+// exclude_tags setting applies to embeddings as it does to chat requests.
+func (e *RemoteEmbedder) ExcludesNote(tags []string) bool {
+	return e.config.ExcludesNote(tags)
+}
+
+// Embed masks secrets in texts before sending them, like chat requests.
+func (e *RemoteEmbedder) Embed(texts []string) ([][]float32, error) {
+	redactor := newRedactor(e.config)
+	masked := make([]string, len(texts))
+	for i, text := range texts {
+		masked[i] = redactor.Redact(text)
+	}
+
+	jsonData, err := json.Marshal(embeddingRequest{Model: e.model, Input: masked})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", e.baseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embedding request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var embResp embeddingResponse
+	if err := json.Unmarshal(body, &embResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(embResp.Data) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embResp.Data))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range embResp.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding index out of range: %d", d.Index)
+		}
+		vectors[d.Index] = normalize(d.Embedding)
+	}
+
+	return vectors, nil
+}
@@ -0,0 +1,76 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRateLimited    = errors.New("rate limited by the AI provider")
+	ErrAuth           = errors.New("AI provider rejected the API key")
+	ErrContextTooLong = errors.New("request exceeds the model context window")
+	ErrUnavailable    = errors.New("AI provider is temporarily unavailable")
+	ErrBudgetExceeded = errors.New("monthly AI budget exceeded")
+
+	// ErrNoClient is returned by the AI commands when no client is configured,
+	// usually because GROQ_API_KEY is not set.
+	ErrNoClient = errors.New("AI client not available")
+)
+
+// APIError is a non-2xx response from the provider. It unwraps to one of the
+// sentinel errors above when the failure can be classified.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Type       string
+	Code       string
+	RetryAfter time.Duration
+	kind       error
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API request failed with status %d", e.StatusCode)
+	}
+	if e.Type == "" && e.Code == "" {
+		return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("groq API error: %s (type: %s, code: %s)", e.Message, e.Type, e.Code)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+func classifyAPIError(e *APIError) error {
+	message := strings.ToLower(e.Message)
+
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrAuth
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusRequestEntityTooLarge,
+		e.Code == "context_length_exceeded",
+		strings.Contains(message, "context length"),
+		strings.Contains(message, "context window"),
+		strings.Contains(message, "reduce the length"):
+		return ErrContextTooLong
+	case e.StatusCode >= 500:
+		return ErrUnavailable
+	}
+	return nil
+}
+
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -1,7 +1,9 @@
 package ai
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,15 +18,22 @@ const (
 )
 
 type GroqClient struct {
-	apiKey  string
-	model   string
-	baseURL string
-	client  *http.Client
+	apiKey       string
+	model        string
+	baseURL      string
+	maxAttempts  int
+	config       *Config
+	client       *http.Client
+	streamClient *http.Client
 }
 
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	// ToolCalls is set on assistant messages that ask for tools to run;
+	// ToolCallID ties a "tool" message to the call it answers.
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
 }
 
 type ChatRequest struct {
@@ -32,6 +41,14 @@ type ChatRequest struct {
 	Messages    []Message `json:"messages"`
 	MaxTokens   int       `json:"max_tokens,omitempty"`
 	Temperature float64   `json:"temperature,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+	// ResponseFormat enables JSON mode when set to {"type": "json_object"}.
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+	Tools          []Tool          `json:"tools,omitempty"`
+}
+
+type ResponseFormat struct {
+	Type string `json:"type"`
 }
 
 type ChatResponse struct {
@@ -44,11 +61,33 @@ type ChatResponse struct {
 		Message      Message `json:"message"`
 		FinishReason string  `json:"finish_reason"`
 	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
+	Usage TokenUsage `json:"usage"`
+}
+
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// ChatStreamChunk is a single server-sent event of a streamed completion.
+type ChatStreamChunk struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index int `json:"index"`
+		Delta struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	// Usage arrives on the final chunk: top-level on OpenAI-compatible
+	// servers, under x_groq on Groq.
+	Usage *TokenUsage `json:"usage,omitempty"`
+	XGroq *struct {
+		Usage *TokenUsage `json:"usage,omitempty"`
+	} `json:"x_groq,omitempty"`
 }
 
 type GroqError struct {
@@ -61,16 +100,46 @@ type GroqError struct {
 
 func NewGroqClient() (*GroqClient, error) {
 	apiKey := GetAPIKey()
+	replayDir := GetReplayDir()
 	if apiKey == "" {
-		return nil, fmt.Errorf("GROQ_API_KEY environment variable is not set")
+		if replayDir == "" {
+			return nil, fmt.Errorf("GROQ_API_KEY environment variable is not set")
+		}
+		apiKey = "replay"
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Streams may legitimately run for minutes, so only the wait for the
+	// response headers is bounded; cancellation comes from the context.
+	streamTransport := http.DefaultTransport.(*http.Transport).Clone()
+	streamTransport.ResponseHeaderTimeout = cfg.Timeout()
+
+	var transport, streamRoundTripper http.RoundTripper = http.DefaultTransport, streamTransport
+	switch {
+	case replayDir != "":
+		transport = NewReplayTransport(replayDir)
+		streamRoundTripper = transport
+	case GetRecordDir() != "":
+		transport = NewRecordingTransport(GetRecordDir(), transport)
+		streamRoundTripper = NewRecordingTransport(GetRecordDir(), streamRoundTripper)
 	}
 
 	return &GroqClient{
-		apiKey:  apiKey,
-		model:   DefaultModel,
-		baseURL: GroqAPIURL,
+		apiKey:      apiKey,
+		model:       cfg.Model,
+		baseURL:     GetAPIURL(),
+		maxAttempts: cfg.MaxAttempts,
+		config:      cfg,
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Transport: transport,
+			Timeout:   cfg.Timeout(),
+		},
+		streamClient: &http.Client{
+			Transport: streamRoundTripper,
 		},
 	}, nil
 }
@@ -79,214 +148,364 @@ func (g *GroqClient) SetModel(model string) {
 	g.model = model
 }
 
-func (g *GroqClient) Chat(messages []Message, maxTokens int, temperature float64) (string, error) {
-	reqBody := ChatRequest{
-		Model:    g.model,
-		Messages: messages,
-	}
+// ContextBudget is the most prompt tokens a request to the current model may
+// use, from "context_budgets" in the config file or the defaults.
+func (g *GroqClient) ContextBudget() int {
+	return g.config.ContextBudget(g.model)
+}
 
-	if maxTokens > 0 {
-		reqBody.MaxTokens = maxTokens
+// contextRoom is what is left of the context budget for notes or other
+// context once the prompt instructions and the given inputs are accounted for.
+func (g *GroqClient) contextRoom(inputs ...string) int {
+	room := g.ContextBudget() - promptReserveTokens
+	for _, in := range inputs {
+		room -= EstimateTokens(in)
 	}
+	return max(room, 0)
+}
 
-	if temperature > 0 {
-		reqBody.Temperature = temperature
-	}
+func (g *GroqClient) Chat(messages []Message, maxTokens int, temperature float64) (string, error) {
+	return g.ChatContext(context.Background(), messages, maxTokens, temperature)
+}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+func (g *GroqClient) ChatContext(ctx context.Context, messages []Message, maxTokens int, temperature float64) (string, error) {
+	return g.complete(ctx, g.buildRequest(messages, maxTokens, temperature, false))
+}
+
+// ChatJSON asks the model for a single JSON object. The caller still has to
+// validate the result; JSON mode only guarantees syntax.
+func (g *GroqClient) ChatJSON(ctx context.Context, messages []Message, maxTokens int, temperature float64) (string, error) {
+	reqBody := g.buildRequest(messages, maxTokens, temperature, false)
+	reqBody.ResponseFormat = &ResponseFormat{Type: "json_object"}
+	return g.complete(ctx, reqBody)
+}
+
+func (g *GroqClient) complete(ctx context.Context, reqBody ChatRequest) (string, error) {
+	message, err := g.completeMessage(ctx, reqBody)
+	return message.Content, err
+}
+
+func (g *GroqClient) completeMessage(ctx context.Context, reqBody ChatRequest) (Message, error) {
+	redactor := newRedactor(g.config)
+	reqBody.Messages = redactor.redactMessages(reqBody.Messages)
+
+	// The cache holds redacted answers, so secrets never reach the database.
+	if content, ok := g.cachedResponse(reqBody); ok {
+		return Message{Role: "assistant", Content: redactor.Restore(content)}, nil
 	}
 
-	req, err := http.NewRequest("POST", g.baseURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+	if err := g.checkBudget(); err != nil {
+		return Message{}, err
 	}
+	redactor.reportRedactions()
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+g.apiKey)
+	start := time.Now()
+	message, tokens, err := g.chat(ctx, reqBody)
+	g.recordUsage(start, tokens, redactor.Summary(), err, ctx.Err() != nil)
+	if err == nil && len(message.ToolCalls) == 0 {
+		g.storeResponse(reqBody, message.Content)
+	}
+	return redactor.restoreMessage(message), err
+}
 
-	resp, err := g.client.Do(req)
+func (g *GroqClient) chat(ctx context.Context, reqBody ChatRequest) (Message, TokenUsage, error) {
+	resp, err := g.send(ctx, g.client, reqBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return Message{}, TokenUsage{}, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var groqErr GroqError
-		if err := json.Unmarshal(body, &groqErr); err == nil {
-			return "", fmt.Errorf("groq API error: %s (type: %s, code: %s)",
-				groqErr.Error.Message, groqErr.Error.Type, groqErr.Error.Code)
-		}
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return Message{}, TokenUsage{}, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	var chatResp ChatResponse
 	if err := json.Unmarshal(body, &chatResp); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return Message{}, TokenUsage{}, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if len(chatResp.Choices) == 0 {
-		return "", fmt.Errorf("no choices in response")
+		return Message{}, chatResp.Usage, fmt.Errorf("no choices in response")
 	}
 
-	return chatResp.Choices[0].Message.Content, nil
+	return chatResp.Choices[0].Message, chatResp.Usage, nil
 }
 
-func (g *GroqClient) GenerateContent(prompt string, maxTokens int) (string, error) {
-	messages := []Message{
-		{
-			Role:    "user",
-			Content: prompt,
-		},
+// ChatStream requests a streamed completion, calling onToken for every piece of
+// content as it arrives, and returns the full text once the stream ends. When
+// ctx is cancelled the partial text is returned together with ctx.Err().
+func (g *GroqClient) ChatStream(ctx context.Context, messages []Message, maxTokens int, temperature float64, onToken func(string)) (string, error) {
+	// Streamed tokens are shown as they arrive, placeholders included; the
+	// returned text has the secrets restored.
+	redactor := newRedactor(g.config)
+	messages = redactor.redactMessages(messages)
+
+	reqBody := g.buildRequest(messages, maxTokens, temperature, true)
+	if content, ok := g.cachedResponse(reqBody); ok {
+		onToken(content)
+		return redactor.Restore(content), nil
 	}
 
-	return g.Chat(messages, maxTokens, 0.7)
+	if err := g.checkBudget(); err != nil {
+		return "", err
+	}
+	redactor.reportRedactions()
+
+	start := time.Now()
+	content, tokens, err := g.chatStream(ctx, reqBody, onToken)
+	if tokens == nil {
+		// The stream ended before the usage report; estimate from the text.
+		tokens = &TokenUsage{PromptTokens: EstimateMessagesTokens(messages), CompletionTokens: EstimateTokens(content)}
+	}
+	g.recordUsage(start, *tokens, redactor.Summary(), err, ctx.Err() != nil)
+	if err == nil {
+		g.storeResponse(reqBody, content)
+	}
+	return redactor.Restore(content), err
 }
 
-func (g *GroqClient) GenerateNoteContent(topic string, context string) (string, error) {
-	prompt := fmt.Sprintf(`Você é um assistente de anotações inteligente. Crie conteúdo útil e bem estruturado sobre o tópico: "%s"
+func (g *GroqClient) chatStream(ctx context.Context, reqBody ChatRequest, onToken func(string)) (string, *TokenUsage, error) {
+	resp, err := g.send(ctx, g.streamClient, reqBody)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
 
-%s
+	var full strings.Builder
+	var tokens *TokenUsage
+	err = readEventStream(resp.Body, func(data []byte) error {
+		var groqErr GroqError
+		if json.Unmarshal(data, &groqErr) == nil && groqErr.Error.Message != "" {
+			return parseAPIError(http.StatusOK, data)
+		}
 
-Por favor, crie um conteúdo detalhado, organizado e útil sobre este tópico. Use formatação markdown quando apropriado.`, topic, context)
+		var chunk ChatStreamChunk
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			return fmt.Errorf("failed to parse stream chunk: %w", err)
+		}
+		if chunk.Usage != nil {
+			tokens = chunk.Usage
+		} else if chunk.XGroq != nil && chunk.XGroq.Usage != nil {
+			tokens = chunk.XGroq.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			full.WriteString(choice.Delta.Content)
+			if onToken != nil {
+				onToken(choice.Delta.Content)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return full.String(), tokens, ctx.Err()
+		}
+		return full.String(), tokens, err
+	}
 
-	messages := []Message{
-		{
-			Role:    "system",
-			Content: "Você é um assistente especializado em criar anotações bem estruturadas e úteis.",
-		},
-		{
-			Role:    "user",
-			Content: prompt,
-		},
+	if full.Len() == 0 {
+		return "", tokens, fmt.Errorf("no content in response")
 	}
 
-	return g.Chat(messages, 2000, 0.7)
+	return full.String(), tokens, nil
 }
 
-func (g *GroqClient) ImproveSearchQuery(query string, notesContext []string) (string, error) {
-	contextStr := ""
-	if len(notesContext) > 0 {
-		contextStr = fmt.Sprintf("\n\nContexto das notas existentes:\n%s",
-			notesContext[0])
-		if len(notesContext) > 1 {
-			contextStr += fmt.Sprintf("\n... e mais %d notas relacionadas", len(notesContext)-1)
+// readEventStream parses a server-sent events body, invoking onData with the
+// payload of every "data:" field until the "[DONE]" sentinel or EOF.
+func readEventStream(body io.Reader, onData func([]byte) error) error {
+	reader := bufio.NewReader(body)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			line = strings.TrimRight(line, "\r\n")
+			if data, ok := strings.CutPrefix(line, "data:"); ok {
+				data = strings.TrimSpace(data)
+				if data == "[DONE]" {
+					return nil
+				}
+				if data != "" {
+					if cbErr := onData([]byte(data)); cbErr != nil {
+						return cbErr
+					}
+				}
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read stream: %w", err)
 		}
 	}
+}
 
-	prompt := fmt.Sprintf(`Melhore esta consulta de busca para encontrar notas relevantes: "%s"%s
+func (g *GroqClient) buildRequest(messages []Message, maxTokens int, temperature float64, stream bool) ChatRequest {
+	reqBody := ChatRequest{
+		Model:    g.model,
+		Messages: messages,
+		Stream:   stream,
+	}
 
-Retorne apenas a consulta melhorada, sem explicações adicionais.`, query, contextStr)
+	if maxTokens > 0 {
+		reqBody.MaxTokens = maxTokens
+	}
 
-	messages := []Message{
-		{
-			Role:    "system",
-			Content: "Você é um assistente especializado em melhorar consultas de busca para encontrar informações relevantes.",
-		},
-		{
-			Role:    "user",
-			Content: prompt,
-		},
+	if temperature > 0 {
+		reqBody.Temperature = temperature
 	}
 
-	return g.Chat(messages, 100, 0.3)
+	return reqBody
 }
 
-func (g *GroqClient) AnswerQuestion(question string, notesContext []string) (string, error) {
-	contextStr := ""
-	if len(notesContext) > 0 {
-		contextStr = "\n\nInformações das suas notas:\n"
-		for i, note := range notesContext {
-			if i >= 3 {
-				contextStr += fmt.Sprintf("\n... e mais %d notas", len(notesContext)-3)
-				break
-			}
-			contextStr += note + "\n\n"
-		}
+func (g *GroqClient) newRequest(ctx context.Context, reqBody ChatRequest) (*http.Request, error) {
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	prompt := fmt.Sprintf(`Responda a seguinte pergunta com base nas informações disponíveis:%s
+	req, err := http.NewRequestWithContext(ctx, "POST", g.baseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 
-Pergunta: %s
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+g.apiKey)
 
-Se a resposta não estiver nas notas fornecidas, você pode usar seu conhecimento geral, mas mencione isso.`, contextStr, question)
+	return req, nil
+}
+
+func parseAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+
+	var groqErr GroqError
+	if err := json.Unmarshal(body, &groqErr); err == nil && groqErr.Error.Message != "" {
+		apiErr.Message = groqErr.Error.Message
+		apiErr.Type = groqErr.Error.Type
+		apiErr.Code = groqErr.Error.Code
+	} else if len(body) > 0 {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+
+	apiErr.kind = classifyAPIError(apiErr)
+	return apiErr
+}
 
+func (g *GroqClient) GenerateContent(prompt string, maxTokens int) (string, error) {
 	messages := []Message{
-		{
-			Role:    "system",
-			Content: "Você é um assistente inteligente que responde perguntas com base nas anotações do usuário e conhecimento geral.",
-		},
 		{
 			Role:    "user",
 			Content: prompt,
 		},
 	}
 
-	return g.Chat(messages, 1500, 0.7)
+	return g.Chat(messages, maxTokens, 0.7)
 }
 
-func (g *GroqClient) GenerateCode(language string, description string, context string) (string, error) {
-	prompt := fmt.Sprintf(`Gere código %s para: %s
+func (g *GroqClient) GenerateNoteContent(topic string, context string) (string, error) {
+	messages, err := g.noteContentMessages(topic, context)
+	if err != nil {
+		return "", err
+	}
+	return g.Chat(messages, 2000, 0.7)
+}
 
-%s
+func (g *GroqClient) GenerateNoteContentStream(ctx context.Context, topic string, context string, onToken func(string)) (string, error) {
+	messages, err := g.noteContentMessages(topic, context)
+	if err != nil {
+		return "", err
+	}
+	return g.ChatStream(ctx, messages, 2000, 0.7, onToken)
+}
 
-Por favor, forneça código completo, bem comentado e seguindo as melhores práticas.`, language, description, context)
+func (g *GroqClient) noteContentMessages(topic string, context string) ([]Message, error) {
+	return g.renderPrompt("note_content", map[string]any{
+		"Topic":   topic,
+		"Context": TruncateTokens(context, g.contextRoom(topic)),
+	})
+}
 
-	messages := []Message{
-		{
-			Role:    "system",
-			Content: "Você é um programador experiente que gera código limpo, bem documentado e seguindo as melhores práticas.",
-		},
-		{
-			Role:    "user",
-			Content: prompt,
-		},
+// searchContextTokens caps the notes sent to improve a search query; the
+// answer is a single line, so a sample of the notes is enough.
+const searchContextTokens = 1500
+
+func (g *GroqClient) ImproveSearchQuery(query string, notes []ContextDocument) (string, error) {
+	budget := min(searchContextTokens, g.contextRoom(query))
+	packed, more := PackContext(query, notes, budget)
+
+	messages, err := g.renderPrompt("search_query", map[string]any{
+		"Query": query,
+		"Notes": packed,
+		"More":  more,
+	})
+	if err != nil {
+		return "", err
 	}
 
-	return g.Chat(messages, 2000, 0.3)
+	return g.Chat(messages, 100, 0.3)
 }
 
-func (g *GroqClient) GenerateTips(topic string) (string, error) {
-	prompt := fmt.Sprintf(`Forneça dicas úteis e práticas sobre: %s
+// AnswerQuestion answers from the notes most relevant to question that fit
+// in the model's context budget.
+func (g *GroqClient) AnswerQuestion(question string, notes []ContextDocument) (string, error) {
+	packed, more := PackContext(question, notes, g.contextRoom(question))
 
-Formate as dicas de forma clara e organizada, usando markdown.`, topic)
+	messages, err := g.renderPrompt("answer", map[string]any{
+		"Question": question,
+		"Notes":    packed,
+		"More":     more,
+	})
+	if err != nil {
+		return "", err
+	}
 
-	messages := []Message{
-		{
-			Role:    "system",
-			Content: "Você é um assistente que fornece dicas práticas e úteis sobre diversos tópicos.",
-		},
-		{
-			Role:    "user",
-			Content: prompt,
-		},
+	return g.Chat(messages, 1500, 0.7)
+}
+
+func (g *GroqClient) GenerateCode(language string, description string, context string) (string, error) {
+	messages, err := g.codeMessages(language, description, context)
+	if err != nil {
+		return "", err
 	}
+	return g.Chat(messages, 2000, 0.3)
+}
 
-	return g.Chat(messages, 1000, 0.7)
+func (g *GroqClient) GenerateCodeStream(ctx context.Context, language string, description string, context string, onToken func(string)) (string, error) {
+	messages, err := g.codeMessages(language, description, context)
+	if err != nil {
+		return "", err
+	}
+	return g.ChatStream(ctx, messages, 2000, 0.3, onToken)
 }
 
-func (g *GroqClient) GenerateChecklist(topic string, context string, numItems int) ([]string, error) {
-	prompt := fmt.Sprintf(`Crie uma lista de verificação (checklist) com %d itens sobre: "%s"
+func (g *GroqClient) codeMessages(language string, description string, context string) ([]Message, error) {
+	return g.renderPrompt("code", map[string]any{
+		"Language":    language,
+		"Description": description,
+		"Context":     TruncateTokens(context, g.contextRoom(description)),
+	})
+}
 
-%s
+func (g *GroqClient) GenerateTips(topic string) (string, error) {
+	messages, err := g.renderPrompt("tips", map[string]any{"Topic": topic})
+	if err != nil {
+		return "", err
+	}
 
-Retorne APENAS os itens da checklist, um por linha, sem numeração, sem marcadores, sem explicações adicionais. Cada linha deve ser um item claro e específico.`, numItems, topic, context)
+	return g.Chat(messages, 1000, 0.7)
+}
 
-	messages := []Message{
-		{
-			Role:    "system",
-			Content: "Você é um assistente especializado em criar listas de verificação práticas e bem estruturadas.",
-		},
-		{
-			Role:    "user",
-			Content: prompt,
-		},
+func (g *GroqClient) GenerateChecklist(topic string, context string, numItems int) ([]string, error) {
+	messages, err := g.renderPrompt("checklist", map[string]any{
+		"Count":   numItems,
+		"Topic":   topic,
+		"Context": TruncateTokens(context, g.contextRoom(topic)),
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	result, err := g.Chat(messages, 500, 0.5)
@@ -319,30 +538,3 @@ Retorne APENAS os itens da checklist, um por linha, sem numeração, sem marcado
 
 	return items, nil
 }
-
-func (g *GroqClient) GenerateProjectPlan(projectName string, description string) (string, error) {
-	prompt := fmt.Sprintf(`Crie um plano de projeto detalhado para: "%s"
-
-Descrição: %s
-
-O plano deve incluir:
-1. Objetivos principais
-2. Tarefas principais organizadas por fase
-3. Prioridades sugeridas
-4. Marcos importantes
-
-Formate o resultado em markdown.`, projectName, description)
-
-	messages := []Message{
-		{
-			Role:    "system",
-			Content: "Você é um gerente de projetos experiente que cria planos detalhados e práticos.",
-		},
-		{
-			Role:    "user",
-			Content: prompt,
-		},
-	}
-
-	return g.Chat(messages, 2000, 0.7)
-}
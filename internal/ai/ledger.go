@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/snip/internal/usage"
+)
+
+// UsageLedger persists one row per provider call. It is satisfied by
+// repository.UsageRepository and registered once at startup.
+type UsageLedger interface {
+	Create(u *usage.Usage) error
+	Summarize(since time.Time, groupBy string) ([]*usage.Summary, error)
+}
+
+var (
+	ledger         UsageLedger
+	currentCommand = "unknown"
+)
+
+// SetUsageLedger enables usage recording and budget enforcement for every
+// client. Passing nil disables both.
+func SetUsageLedger(l UsageLedger) {
+	ledger = l
+}
+
+// SetCommand records which CLI command the following calls belong to.
+func SetCommand(name string) {
+	if name != "" {
+		currentCommand = name
+	}
+}
+
+func MonthStart(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+}
+
+// EstimateCost prices a set of summaries with the configured price table.
+func EstimateCost(cfg *Config, summaries []*usage.Summary) float64 {
+	total := 0.0
+	for _, s := range summaries {
+		total += cfg.Cost(s.Model, s.PromptTokens, s.CompletionTokens)
+	}
+	return total
+}
+
+// MonthlySpend returns the estimated cost of all calls since the start of
+// the current month.
+func MonthlySpend(l UsageLedger, cfg *Config) (float64, error) {
+	summaries, err := l.Summarize(MonthStart(time.Now()), "model")
+	if err != nil {
+		return 0, err
+	}
+	return EstimateCost(cfg, summaries), nil
+}
+
+// checkBudget blocks the call once the month's estimated spend reaches the
+// configured budget. Without a ledger or budget there is nothing to check.
+func (g *GroqClient) checkBudget() error {
+	if ledger == nil || g.config.MonthlyBudgetUSD <= 0 {
+		return nil
+	}
+
+	spent, err := MonthlySpend(ledger, g.config)
+	if err != nil {
+		return fmt.Errorf("failed to check AI budget: %w", err)
+	}
+	if spent >= g.config.MonthlyBudgetUSD {
+		return fmt.Errorf("%w: $%.4f of $%.2f spent this month", ErrBudgetExceeded, spent, g.config.MonthlyBudgetUSD)
+	}
+	return nil
+}
+
+// recordUsage writes the outcome of a call to the ledger, including what was
+// redacted from the request. A failing ledger never fails the call itself.
+func (g *GroqClient) recordUsage(start time.Time, tokens TokenUsage, redactions string, callErr error, cancelled bool) {
+	if ledger == nil {
+		return
+	}
+
+	u := usage.NewUsage(currentCommand, g.model)
+	u.PromptTokens = tokens.PromptTokens
+	u.CompletionTokens = tokens.CompletionTokens
+	u.LatencyMs = time.Since(start).Milliseconds()
+	u.Redactions = redactions
+	switch {
+	case cancelled:
+		u.Status = usage.StatusCancelled
+	case callErr != nil:
+		u.Status = usage.StatusError
+		u.Error = callErr.Error()
+	}
+
+	if err := ledger.Create(u); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record AI usage: %v\n", err)
+	}
+}
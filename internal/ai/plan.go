@@ -0,0 +1,121 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidPlan = errors.New("invalid project plan")
+
+const (
+	maxPlanPhases  = 12
+	maxPlanTasks   = 60
+	maxPlanDueDays = 3650
+)
+
+// ProjectPlan is the structured plan the model must return for
+// `project ai-create`. Due dates are relative to the day the plan is applied.
+type ProjectPlan struct {
+	Summary string      `json:"summary"`
+	Phases  []PlanPhase `json:"phases"`
+}
+
+type PlanPhase struct {
+	Name  string     `json:"name"`
+	Tasks []PlanTask `json:"tasks"`
+}
+
+type PlanTask struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Priority    string   `json:"priority"`
+	DueInDays   *int     `json:"due_in_days"`
+	Checklist   []string `json:"checklist"`
+}
+
+// ParseProjectPlan extracts the JSON object from a model response, decodes it
+// strictly and validates it. Priorities are normalized in place.
+func ParseProjectPlan(raw string) (*ProjectPlan, error) {
+	var plan ProjectPlan
+	if err := decodeStrict(raw, &plan); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPlan, err)
+	}
+
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (p *ProjectPlan) Validate() error {
+	if len(p.Phases) == 0 {
+		return fmt.Errorf("%w: no phases", ErrInvalidPlan)
+	}
+	if len(p.Phases) > maxPlanPhases {
+		return fmt.Errorf("%w: too many phases (%d, max %d)", ErrInvalidPlan, len(p.Phases), maxPlanPhases)
+	}
+
+	total := 0
+	for i := range p.Phases {
+		phase := &p.Phases[i]
+		phase.Name = strings.TrimSpace(phase.Name)
+		if phase.Name == "" {
+			return fmt.Errorf("%w: phase %d has no name", ErrInvalidPlan, i+1)
+		}
+		if len(phase.Tasks) == 0 {
+			return fmt.Errorf("%w: phase %q has no tasks", ErrInvalidPlan, phase.Name)
+		}
+
+		for j := range phase.Tasks {
+			t := &phase.Tasks[j]
+			t.Title = strings.TrimSpace(t.Title)
+			if t.Title == "" {
+				return fmt.Errorf("%w: task %d of phase %q has no title", ErrInvalidPlan, j+1, phase.Name)
+			}
+
+			priority, ok := normalizePriority(t.Priority)
+			if !ok {
+				return fmt.Errorf("%w: task %q has invalid priority %q", ErrInvalidPlan, t.Title, t.Priority)
+			}
+			t.Priority = priority
+
+			if t.DueInDays != nil && (*t.DueInDays < 0 || *t.DueInDays > maxPlanDueDays) {
+				return fmt.Errorf("%w: task %q has invalid due_in_days %d", ErrInvalidPlan, t.Title, *t.DueInDays)
+			}
+
+			t.Checklist = compactStrings(t.Checklist)
+		}
+
+		total += len(phase.Tasks)
+	}
+
+	if total > maxPlanTasks {
+		return fmt.Errorf("%w: too many tasks (%d, max %d)", ErrInvalidPlan, total, maxPlanTasks)
+	}
+	return nil
+}
+
+// GenerateProjectPlan asks for a plan in JSON mode. When the answer does not
+// validate, the model gets the error back and one chance to fix it.
+func (g *GroqClient) GenerateProjectPlan(ctx context.Context, projectName string, description string) (*ProjectPlan, error) {
+	messages, err := g.renderPrompt("project_plan", map[string]any{
+		"Name":        projectName,
+		"Description": description,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var plan *ProjectPlan
+	err = g.chatJSONWithRepair(ctx, messages, 4000, 0.4, func(result string) error {
+		var err error
+		plan, err = ParseProjectPlan(result)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
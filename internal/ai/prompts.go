@@ -0,0 +1,241 @@
+package ai
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Prompt templates define a "system" block, a "user" block or both.
+// The defaults are embedded per language; a file with the same name in
+// ~/.snip/prompts/<language>/ or ~/.snip/prompts/ replaces the default.
+//
+//go:embed prompts
+var defaultPrompts embed.FS
+
+const DefaultLanguage = "pt"
+
+// Languages are the prompt locales shipped with snip.
+var Languages = []string{"en", "pt"}
+
+var ErrUnknownPrompt = errors.New("unknown prompt")
+
+// Prompt sources reported by PromptInfo.
+const (
+	PromptSourceDefault  = "default"
+	PromptSourceOverride = "override"
+)
+
+type PromptInfo struct {
+	Name   string
+	Source string
+	Path   string // override file, empty for defaults
+	Err    error  // set when the override does not parse
+}
+
+func isLanguage(lang string) bool {
+	for _, l := range Languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// PromptsDir is where prompt overrides are read from.
+func PromptsDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".snip", "prompts"), nil
+}
+
+// PromptNames lists the prompts shipped for lang.
+func PromptNames(lang string) ([]string, error) {
+	entries, err := defaultPrompts.ReadDir(path.Join("prompts", lang))
+	if err != nil {
+		return nil, fmt.Errorf("no prompts for language %q", lang)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".tmpl"); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DescribePrompts reports, for every prompt of lang, whether the default or
+// an override is used and whether the override parses.
+func DescribePrompts(lang string) ([]PromptInfo, error) {
+	names, err := PromptNames(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]PromptInfo, 0, len(names))
+	for _, name := range names {
+		info := PromptInfo{Name: name, Source: PromptSourceDefault}
+		if p := overridePath(lang, name); p != "" {
+			info.Source = PromptSourceOverride
+			info.Path = p
+			_, info.Err = loadPrompt(lang, name)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// ExportPrompts copies the default prompts of lang into
+// ~/.snip/prompts/<lang>/ so they can be edited. Existing files are kept and
+// reported as skipped.
+func ExportPrompts(lang string) (written []string, skipped []string, err error) {
+	names, err := PromptNames(lang)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dir, err := PromptsDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	dir = filepath.Join(dir, lang)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	for _, name := range names {
+		target := filepath.Join(dir, name+".tmpl")
+		if _, err := os.Stat(target); err == nil {
+			skipped = append(skipped, target)
+			continue
+		}
+
+		data, err := defaultPrompts.ReadFile(path.Join("prompts", lang, name+".tmpl"))
+		if err != nil {
+			return written, skipped, err
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return written, skipped, fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		written = append(written, target)
+	}
+	return written, skipped, nil
+}
+
+// overridePath returns the user's template for name, preferring the
+// language directory, or "" when the default applies.
+func overridePath(lang, name string) string {
+	dir, err := PromptsDir()
+	if err != nil {
+		return ""
+	}
+	for _, p := range []string{
+		filepath.Join(dir, lang, name+".tmpl"),
+		filepath.Join(dir, name+".tmpl"),
+	} {
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p
+		}
+	}
+	return ""
+}
+
+func loadPrompt(lang, name string) (*template.Template, error) {
+	var (
+		text   []byte
+		source string
+		err    error
+	)
+	if p := overridePath(lang, name); p != "" {
+		text, err = os.ReadFile(p)
+		source = p
+	} else {
+		source = path.Join("prompts", lang, name+".tmpl")
+		text, err = defaultPrompts.ReadFile(source)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPrompt, name)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt %s: %w", source, err)
+	}
+
+	tmpl, err := template.New(name).Funcs(promptFuncs(lang)).Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template %s: %w", source, err)
+	}
+	if tmpl.Lookup("system") == nil && tmpl.Lookup("user") == nil {
+		return nil, fmt.Errorf("invalid prompt template %s: no \"system\" or \"user\" block", source)
+	}
+	return tmpl, nil
+}
+
+func promptFuncs(lang string) template.FuncMap {
+	return template.FuncMap{
+		"join": strings.Join,
+		"date": func(t time.Time) string { return t.Format("2006-01-02") },
+		"weekday": func(t time.Time) string {
+			if lang == "pt" {
+				return weekdaysPT[t.Weekday()]
+			}
+			return t.Weekday().String()
+		},
+	}
+}
+
+var weekdaysPT = [...]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"}
+
+// renderPrompt executes the named template in the configured language and
+// returns its system and user messages, in that order.
+func (g *GroqClient) renderPrompt(name string, data map[string]any) ([]Message, error) {
+	tmpl, err := loadPrompt(g.language(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []Message
+	for _, role := range []string{"system", "user"} {
+		if tmpl.Lookup(role) == nil {
+			continue
+		}
+		var b bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&b, role, data); err != nil {
+			return nil, fmt.Errorf("failed to render prompt %s: %w", name, err)
+		}
+		messages = append(messages, Message{Role: role, Content: strings.TrimSpace(b.String())})
+	}
+
+	// Callers fit their context to the budget; this catches what is left,
+	// such as a huge topic or an edited template, before it costs a request.
+	if tokens, budget := EstimateMessagesTokens(messages), g.ContextBudget(); tokens > budget {
+		return nil, fmt.Errorf("%w: prompt %s uses ~%d tokens, the budget for %s is %d (context_budgets in the config file)",
+			ErrContextTooLong, name, tokens, g.model, budget)
+	}
+	return messages, nil
+}
+
+func (g *GroqClient) language() string {
+	if g.config == nil || g.config.Language == "" {
+		return DefaultLanguage
+	}
+	return g.config.Language
+}
+
+// ChatSystemMessages returns the system prompt for `ai chat`, including the
+// instructions for tools when withTools is set.
+func (g *GroqClient) ChatSystemMessages(withTools bool) ([]Message, error) {
+	return g.renderPrompt("chat", map[string]any{"Tools": withTools})
+}
@@ -0,0 +1,214 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// redactionRule masks the whole match, or only the first capture group when
+// the pattern has one (the password in "user:password@host").
+type redactionRule struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+var builtinRedactionRules = []redactionRule{
+	{"private_key", regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY(?: BLOCK)?-----[\s\S]*?-----END [A-Z0-9 ]*PRIVATE KEY(?: BLOCK)?-----`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+	{"aws_access_key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"github_token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"slack_token", regexp.MustCompile(`\bxox[abpors]-[A-Za-z0-9-]{10,}`)},
+	{"google_api_key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"stripe_key", regexp.MustCompile(`\b[rsp]k_(?:live|test)_[0-9A-Za-z]{16,}\b`)},
+	{"api_key", regexp.MustCompile(`\b(?:sk-(?:proj-|ant-)?[A-Za-z0-9_-]{20,}|gsk_[A-Za-z0-9]{20,})`)},
+	{"password_in_url", regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^\s:/@]+:([^\s@/]+)@`)},
+	{"password", regexp.MustCompile(`(?i)\b(?:password|passwd|pwd|senha|secret|token|api[_-]?key|access[_-]?token|auth[_-]?token)\s*[:=]\s*["']?([^\s"',;]{4,})`)},
+}
+
+// compileRedactPatterns validates the custom patterns from the config.
+func compileRedactPatterns(patterns map[string]string) ([]redactionRule, error) {
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rules := make([]redactionRule, 0, len(names))
+	for _, name := range names {
+		re, err := regexp.Compile(patterns[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", name, err)
+		}
+		rules = append(rules, redactionRule{kind: name, re: re})
+	}
+	return rules, nil
+}
+
+// Redactor replaces secrets in text sent to the provider with placeholders
+// such as [REDACTED:jwt:1] and puts the originals back in the answer, so a
+// rewritten note keeps its secrets without the provider ever seeing them.
+// A Redactor lives for one call.
+type Redactor struct {
+	rules        []redactionRule
+	placeholders map[string]string // secret -> placeholder
+	secrets      map[string]string // placeholder -> secret
+	counts       map[string]int
+}
+
+func newRedactor(cfg *Config) *Redactor {
+	rules := append([]redactionRule{}, builtinRedactionRules...)
+	if cfg != nil {
+		// Patterns were validated by LoadConfig.
+		custom, _ := compileRedactPatterns(cfg.RedactPatterns)
+		rules = append(rules, custom...)
+	}
+	return &Redactor{
+		rules:        rules,
+		placeholders: make(map[string]string),
+		secrets:      make(map[string]string),
+		counts:       make(map[string]int),
+	}
+}
+
+// Redact masks every secret found in text.
+func (r *Redactor) Redact(text string) string {
+	for _, rule := range r.rules {
+		matches := rule.re.FindAllStringSubmatchIndex(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			start, end := m[0], m[1]
+			if len(m) >= 4 && m[2] >= 0 {
+				start, end = m[2], m[3]
+			}
+			if start < last || start == end {
+				continue
+			}
+			b.WriteString(text[last:start])
+			b.WriteString(r.placeholder(rule.kind, text[start:end]))
+			last = end
+		}
+		b.WriteString(text[last:])
+		text = b.String()
+	}
+	return text
+}
+
+func (r *Redactor) placeholder(kind, secret string) string {
+	if strings.HasPrefix(secret, "[REDACTED:") {
+		return secret
+	}
+	if p, ok := r.placeholders[secret]; ok {
+		return p
+	}
+	r.counts[kind]++
+	p := fmt.Sprintf("[REDACTED:%s:%d]", kind, r.counts[kind])
+	r.placeholders[secret] = p
+	r.secrets[p] = secret
+	return p
+}
+
+// Restore puts the original secrets back in an answer.
+func (r *Redactor) Restore(text string) string {
+	for p, secret := range r.secrets {
+		text = strings.ReplaceAll(text, p, secret)
+	}
+	return text
+}
+
+// restoreJSON is Restore for text that is a JSON document, such as tool call
+// arguments, escaping the secrets as JSON string content.
+func (r *Redactor) restoreJSON(text string) string {
+	for p, secret := range r.secrets {
+		quoted, _ := json.Marshal(secret)
+		text = strings.ReplaceAll(text, p, string(quoted[1:len(quoted)-1]))
+	}
+	return text
+}
+
+// Summary lists what was masked as "kind=count", sorted by kind, e.g.
+// "jwt=1, password=2". It is empty when nothing was found.
+func (r *Redactor) Summary() string {
+	kinds := make([]string, 0, len(r.counts))
+	for kind := range r.counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%s=%d", kind, r.counts[kind]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RedactText masks secrets in text the way requests are masked and returns
+// the masked text with its Summary.
+func RedactText(cfg *Config, text string) (string, string) {
+	r := newRedactor(cfg)
+	return r.Redact(text), r.Summary()
+}
+
+// redactMessages returns a copy of messages with secrets masked.
+func (r *Redactor) redactMessages(messages []Message) []Message {
+	redacted := make([]Message, len(messages))
+	for i, m := range messages {
+		m.Content = r.Redact(m.Content)
+		if len(m.ToolCalls) > 0 {
+			calls := make([]ToolCall, len(m.ToolCalls))
+			for j, call := range m.ToolCalls {
+				call.Function.Arguments = r.Redact(call.Function.Arguments)
+				calls[j] = call
+			}
+			m.ToolCalls = calls
+		}
+		redacted[i] = m
+	}
+	return redacted
+}
+
+// restoreMessage puts the secrets back in an answer, including the
+// arguments of any tool calls.
+func (r *Redactor) restoreMessage(m Message) Message {
+	m.Content = r.Restore(m.Content)
+	for i := range m.ToolCalls {
+		m.ToolCalls[i].Function.Arguments = r.restoreJSON(m.ToolCalls[i].Function.Arguments)
+	}
+	return m
+}
+
+// reportRedactions tells the user, on stderr, that secrets were masked.
+func (r *Redactor) reportRedactions() {
+	if summary := r.Summary(); summary != "" {
+		fmt.Fprintf(os.Stderr, "🔒 Masked secrets before sending to AI: %s\n", summary)
+	}
+}
+
+// ExcludesNote reports whether a note with these tags must be kept out of
+// AI requests (the "exclude_tags" setting).
+func (c *Config) ExcludesNote(tags []string) bool {
+	if c == nil {
+		return false
+	}
+	for _, t := range tags {
+		for _, excluded := range c.ExcludeTags {
+			if strings.EqualFold(t, excluded) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ExcludesNote is Config.ExcludesNote for the client's configuration.
+func (g *GroqClient) ExcludesNote(tags []string) bool {
+	return g != nil && g.config.ExcludesNote(tags)
+}
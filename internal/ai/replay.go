@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Fixture is one recorded exchange with the provider, stored as
+// <key>.json where key is FixtureKey of the request body.
+type Fixture struct {
+	Request json.RawMessage `json:"request"`
+	Status  int             `json:"status"`
+	// Response is the raw body: a JSON completion, or the event stream for
+	// streamed requests.
+	Response string `json:"response"`
+}
+
+// FixtureKey hashes a request body. Bodies are produced by json.Marshal, so
+// the same call always yields the same key.
+func FixtureKey(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])[:32]
+}
+
+// replayTransport answers requests from fixtures in dir without touching the
+// network. With next set it records instead: requests go to next and every
+// answer is saved as a fixture.
+type replayTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+// NewReplayTransport serves recorded fixtures from dir. A request without a
+// fixture fails with a 404 naming the missing file.
+func NewReplayTransport(dir string) http.RoundTripper {
+	return &replayTransport{dir: dir}
+}
+
+// NewRecordingTransport sends requests through next and saves each exchange
+// in dir for NewReplayTransport.
+func NewRecordingTransport(dir string, next http.RoundTripper) http.RoundTripper {
+	return &replayTransport{dir: dir, next: next}
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(t.dir, FixtureKey(body)+".json")
+
+	if t.next != nil {
+		return t.record(req, body, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		msg, _ := json.Marshal(fmt.Sprintf("no recorded response for this request (%s)", path))
+		return fixtureResponse(req, http.StatusNotFound, `{"error": {"message": `+string(msg)+`}}`), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	return fixtureResponse(req, f.Status, f.Response), nil
+}
+
+func (t *replayTransport) record(req *http.Request, body []byte, path string) (*http.Response, error) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	f := Fixture{Request: json.RawMessage(body), Status: resp.StatusCode, Response: string(respBody)}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write fixture: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+func fixtureResponse(req *http.Request, status int, body string) *http.Response {
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	if req.Header.Get("Accept") == "text/event-stream" && status == http.StatusOK {
+		header.Set("Content-Type", "text/event-stream")
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,24 @@
+package ai
+
+import (
+	"context"
+	"strings"
+)
+
+// WriteReport turns a markdown activity report (a standup or a weekly
+// summary) into short prose, keeping task numbers so it can be traced back.
+func (g *GroqClient) WriteReport(ctx context.Context, kind string, report string) (string, error) {
+	messages, err := g.renderPrompt("report", map[string]any{
+		"Kind":   kind,
+		"Report": TruncateTokens(report, g.contextRoom()),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	result, err := g.ChatContext(ctx, messages, 1200, 0.4)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(result), nil
+}
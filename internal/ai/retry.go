@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	baseBackoff = 500 * time.Millisecond
+	maxBackoff  = 20 * time.Second
+	// maxRetryWait is the longest server-requested wait we are willing to
+	// sleep through; beyond it the rate-limit error is returned instead.
+	maxRetryWait = 60 * time.Second
+)
+
+// send performs the request, retrying network failures, 429s and 5xx
+// responses with exponential backoff. The caller owns the returned body.
+func (g *GroqClient) send(ctx context.Context, client *http.Client, reqBody ChatRequest) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := g.newRequest(ctx, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		if reqBody.Stream {
+			req.Header.Set("Accept", "text/event-stream")
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if attempt >= g.maxAttempts {
+				return nil, fmt.Errorf("failed to send request: %w", err)
+			}
+			delay := backoff(attempt)
+			notifyRetry(attempt, g.maxAttempts, delay, err)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		apiErr := parseAPIError(resp.StatusCode, body)
+		apiErr.RetryAfter = retryAfter(resp.Header)
+
+		if !isRetryableStatus(resp.StatusCode) || attempt >= g.maxAttempts {
+			return nil, apiErr
+		}
+
+		delay := backoff(attempt)
+		if apiErr.RetryAfter > 0 {
+			if apiErr.RetryAfter > maxRetryWait {
+				return nil, apiErr
+			}
+			delay = apiErr.RetryAfter
+		}
+
+		notifyRetry(attempt, g.maxAttempts, delay, apiErr)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff returns an exponential delay with jitter in [d/2, d].
+func backoff(attempt int) time.Duration {
+	d := baseBackoff << (attempt - 1)
+	if d > maxBackoff || d <= 0 {
+		d = maxBackoff
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+// retryAfter reads Retry-After (seconds or HTTP date) and falls back to
+// Groq's x-ratelimit-reset-* headers for whichever budget is exhausted.
+func retryAfter(header http.Header) time.Duration {
+	if v := header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+			return time.Duration(seconds * float64(time.Second))
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return time.Until(t)
+		}
+	}
+
+	var wait time.Duration
+	for _, kind := range []string{"requests", "tokens"} {
+		if header.Get("x-ratelimit-remaining-"+kind) != "0" {
+			continue
+		}
+		if d, err := time.ParseDuration(strings.TrimSpace(header.Get("x-ratelimit-reset-" + kind))); err == nil && d > wait {
+			wait = d
+		}
+	}
+	return wait
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func notifyRetry(attempt, maxAttempts int, delay time.Duration, err error) {
+	fmt.Fprintf(os.Stderr, "AI request failed (%v), retrying in %s (attempt %d/%d)...\n",
+		err, delay.Round(100*time.Millisecond), attempt+1, maxAttempts)
+}
@@ -0,0 +1,52 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MaxRewriteTokens bounds the note content sent for a rewrite so the
+// rewritten note still fits in the answer.
+const MaxRewriteTokens = 3500
+
+// RewriteNote applies instruction to a note's content and returns the full
+// rewritten content.
+func (g *GroqClient) RewriteNote(ctx context.Context, title, content, instruction string) (string, error) {
+	limit := min(MaxRewriteTokens, g.contextRoom(title, instruction))
+	if tokens := EstimateTokens(content); tokens > limit {
+		return "", fmt.Errorf("note is too long to rewrite (~%d tokens, limit %d)", tokens, limit)
+	}
+
+	messages, err := g.renderPrompt("rewrite", map[string]any{
+		"Instruction": instruction,
+		"Title":       title,
+		"Content":     content,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	result, err := g.ChatContext(ctx, messages, 4000, 0.3)
+	if err != nil {
+		return "", err
+	}
+	return unwrapFence(strings.TrimSpace(result)), nil
+}
+
+// unwrapFence removes a markdown fence the model sometimes wraps the whole
+// answer in. Fences inside the content are left alone.
+func unwrapFence(s string) string {
+	if !strings.HasPrefix(s, "```") || !strings.HasSuffix(s, "```") {
+		return s
+	}
+	newline := strings.Index(s, "\n")
+	if newline < 0 {
+		return s
+	}
+	inner := strings.TrimSpace(s[newline+1 : len(s)-3])
+	if strings.Contains(inner, "```") {
+		return s
+	}
+	return inner
+}
@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// jsonRepairTries is how many times a model gets its validation error back
+// before a structured request fails.
+const jsonRepairTries = 1
+
+// chatJSONWithRepair runs a JSON-mode request and hands the answer to parse.
+// When parse rejects it, the error is sent back so the model can fix its
+// output, up to jsonRepairTries times.
+func (g *GroqClient) chatJSONWithRepair(ctx context.Context, messages []Message, maxTokens int, temperature float64, parse func(string) error) error {
+	var lastErr error
+	for attempt := 0; attempt <= jsonRepairTries; attempt++ {
+		result, err := g.ChatJSON(ctx, messages, maxTokens, temperature)
+		if err != nil {
+			return err
+		}
+
+		if lastErr = parse(result); lastErr == nil {
+			return nil
+		}
+
+		repair, err := g.renderPrompt("json_repair", map[string]any{"Error": lastErr.Error()})
+		if err != nil {
+			return err
+		}
+		messages = append(messages, Message{Role: "assistant", Content: result})
+		messages = append(messages, repair...)
+	}
+
+	return lastErr
+}
+
+// normalizePriority lowercases p and defaults it to medium. It reports false
+// for values outside low, medium and high.
+func normalizePriority(p string) (string, bool) {
+	p = strings.ToLower(strings.TrimSpace(p))
+	switch p {
+	case "":
+		return "medium", true
+	case "low", "medium", "high":
+		return p, true
+	}
+	return p, false
+}
+
+func compactStrings(values []string) []string {
+	result := values[:0]
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// decodeStrict decodes the JSON object found in raw into v, rejecting
+// fields the schema does not define.
+func decodeStrict(raw string, v any) error {
+	data := extractJSON(raw)
+	if data == "" {
+		return errors.New("response contains no JSON object")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// extractJSON returns the outermost JSON object in s, tolerating markdown
+// fences and prose around it.
+func extractJSON(s string) string {
+	start := strings.Index(s, "{")
+	end := strings.LastIndex(s, "}")
+	if start < 0 || end < start {
+		return ""
+	}
+	return s[start : end+1]
+}
@@ -0,0 +1,116 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SummaryChunkTokens bounds the text sent in a single summarization request,
+// leaving room for the prompt and the answer. Small context budgets lower it.
+const SummaryChunkTokens = 3000
+
+// SummaryDocument is one source for a summary: a note, or a project rendered
+// as text.
+type SummaryDocument struct {
+	Title   string
+	Content string
+}
+
+// ChunkText splits text into pieces of at most maxChars, preferring
+// paragraph breaks, then line breaks, then spaces.
+func ChunkText(text string, maxChars int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if maxChars <= 0 || len(text) <= maxChars {
+		return []string{text}
+	}
+
+	var chunks []string
+	for len(text) > maxChars {
+		cut := -1
+		for _, sep := range []string{"\n\n", "\n", " "} {
+			if i := strings.LastIndex(text[:maxChars], sep); i > maxChars/2 {
+				cut = i
+				break
+			}
+		}
+		if cut < 0 {
+			cut = maxChars
+			// Do not split a multi-byte rune.
+			for cut > 1 && !isRuneStart(text[cut]) {
+				cut--
+			}
+		}
+
+		chunks = append(chunks, strings.TrimSpace(text[:cut]))
+		text = strings.TrimSpace(text[cut:])
+	}
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// Summarize produces a concise markdown summary of docs. Input that does not
+// fit in one request is summarized chunk by chunk (map) and the partial
+// summaries are then combined (reduce), repeating until they fit.
+// onProgress, when set, is called before every request.
+func (g *GroqClient) Summarize(ctx context.Context, subject string, docs []SummaryDocument, onProgress func(step string)) (string, error) {
+	var b strings.Builder
+	for _, doc := range docs {
+		fmt.Fprintf(&b, "## %s\n\n%s\n\n", doc.Title, strings.TrimSpace(doc.Content))
+	}
+
+	chunkTokens := min(SummaryChunkTokens, g.contextRoom(subject))
+	chunks := ChunkTokens(b.String(), chunkTokens)
+	if len(chunks) == 0 {
+		return "", fmt.Errorf("nothing to summarize")
+	}
+
+	for len(chunks) > 1 {
+		partials := make([]string, 0, len(chunks))
+		for i, chunk := range chunks {
+			if onProgress != nil {
+				onProgress(fmt.Sprintf("Summarizing part %d/%d", i+1, len(chunks)))
+			}
+			messages, err := g.summaryMessages(subject, chunk, true)
+			if err != nil {
+				return "", err
+			}
+			partial, err := g.ChatContext(ctx, messages, 800, 0.3)
+			if err != nil {
+				return "", err
+			}
+			partials = append(partials, strings.TrimSpace(partial))
+		}
+		chunks = ChunkTokens(strings.Join(partials, "\n\n"), chunkTokens)
+	}
+
+	if onProgress != nil {
+		onProgress("Writing summary")
+	}
+	messages, err := g.summaryMessages(subject, chunks[0], false)
+	if err != nil {
+		return "", err
+	}
+	summary, err := g.ChatContext(ctx, messages, 1200, 0.3)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(summary), nil
+}
+
+func (g *GroqClient) summaryMessages(subject, text string, partial bool) ([]Message, error) {
+	return g.renderPrompt("summary", map[string]any{
+		"Subject": subject,
+		"Text":    text,
+		"Partial": partial,
+	})
+}
@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidTagSuggestion = errors.New("invalid tag suggestion")
+
+const (
+	DefaultMaxTags     = 4
+	maxSuggestedTitle  = 100
+	maxTaggingTokens   = 1500
+	maxExistingTagList = 200
+)
+
+// TagSuggestion is the model's proposal for a note: tags to add and an
+// optional better title (empty when the current one is fine).
+type TagSuggestion struct {
+	Tags  []string `json:"tags"`
+	Title string   `json:"title"`
+}
+
+// NormalizeTag turns a free-form label into the form used by snip tags:
+// lowercase, no leading '#', words joined by '-'.
+func NormalizeTag(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimLeft(name, "#")
+
+	var b strings.Builder
+	lastDash := false
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '+':
+			b.WriteRune(r)
+			lastDash = false
+		case !lastDash && b.Len() > 0:
+			b.WriteRune('-')
+			lastDash = true
+		}
+	}
+	return strings.TrimRight(b.String(), "-")
+}
+
+// ParseTagSuggestion decodes a suggestion, normalizing and de-duplicating
+// tags and keeping at most maxTags of them.
+func ParseTagSuggestion(raw string, maxTags int) (*TagSuggestion, error) {
+	var suggestion TagSuggestion
+	if err := decodeStrict(raw, &suggestion); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTagSuggestion, err)
+	}
+
+	seen := make(map[string]bool)
+	tags := suggestion.Tags[:0]
+	for _, t := range suggestion.Tags {
+		t = NormalizeTag(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	if maxTags > 0 && len(tags) > maxTags {
+		tags = tags[:maxTags]
+	}
+	suggestion.Tags = tags
+
+	suggestion.Title = strings.Trim(strings.TrimSpace(suggestion.Title), `"`)
+	if len(suggestion.Title) > maxSuggestedTitle {
+		return nil, fmt.Errorf("%w: title is longer than %d characters", ErrInvalidTagSuggestion, maxSuggestedTitle)
+	}
+
+	if len(suggestion.Tags) == 0 && suggestion.Title == "" {
+		return nil, fmt.Errorf("%w: no tags or title", ErrInvalidTagSuggestion)
+	}
+	return &suggestion, nil
+}
+
+// SuggestTags proposes tags and a title for a note. existingTags are offered
+// to the model first so the tag set does not fragment into synonyms.
+func (g *GroqClient) SuggestTags(ctx context.Context, title, content string, existingTags []string, maxTags int) (*TagSuggestion, error) {
+	if maxTags <= 0 {
+		maxTags = DefaultMaxTags
+	}
+	content = TruncateTokens(content, maxTaggingTokens)
+	if len(existingTags) > maxExistingTagList {
+		existingTags = existingTags[:maxExistingTagList]
+	}
+
+	messages, err := g.renderPrompt("tags", map[string]any{
+		"MaxTags":      maxTags,
+		"ExistingTags": existingTags,
+		"Title":        title,
+		"Content":      content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestion *TagSuggestion
+	err = g.chatJSONWithRepair(ctx, messages, 300, 0.2, func(result string) error {
+		var err error
+		suggestion, err = ParseTagSuggestion(result, maxTags)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return suggestion, nil
+}
@@ -0,0 +1,97 @@
+package ai
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CharsPerToken is the usual ratio of characters to tokens for English and
+// Portuguese prose with the tokenizers of the supported models.
+const CharsPerToken = 4
+
+// EstimateTokens approximates how many tokens text uses without a real
+// tokenizer: every run of letters and digits costs one token per
+// CharsPerToken characters (at least one), and every other visible rune, such
+// as punctuation or a symbol, costs one. It errs slightly on the high side,
+// which is the safe side for budgeting.
+func EstimateTokens(text string) int {
+	tokens, word := 0, 0
+	flush := func() {
+		if word > 0 {
+			tokens += (word + CharsPerToken - 1) / CharsPerToken
+			word = 0
+		}
+	}
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word++
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			flush()
+			tokens++
+		}
+	}
+	flush()
+	return tokens
+}
+
+// EstimateMessagesTokens adds a few tokens per message for the role markers.
+func EstimateMessagesTokens(messages []Message) int {
+	total := 0
+	for _, m := range messages {
+		total += 4 + EstimateTokens(m.Content)
+		for _, call := range m.ToolCalls {
+			total += EstimateTokens(call.Function.Name) + EstimateTokens(call.Function.Arguments)
+		}
+	}
+	return total
+}
+
+// ChunkTokens splits text like ChunkText into pieces of about maxTokens
+// each. The piece size in characters follows the density of the text, and
+// pieces that still go over are split again.
+func ChunkTokens(text string, maxTokens int) []string {
+	maxTokens = max(maxTokens, 1)
+	maxChars := maxTokens * CharsPerToken
+	if tokens := EstimateTokens(text); tokens > 0 {
+		maxChars = max(len(text)*maxTokens/tokens*9/10, 1)
+	}
+
+	var chunks []string
+	for _, chunk := range ChunkText(text, maxChars) {
+		if EstimateTokens(chunk) > maxTokens && len(chunk) > 1 {
+			chunks = append(chunks, ChunkTokens(chunk, maxTokens*3/4)...)
+			continue
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// TruncateTokens shortens text to about maxTokens, cutting at a paragraph,
+// line or word break and marking the cut with "[...]".
+func TruncateTokens(text string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+	if EstimateTokens(text) <= maxTokens {
+		return text
+	}
+
+	const marker = "\n[...]"
+	maxChars := maxTokens * CharsPerToken
+	for maxChars > 0 {
+		chunks := ChunkText(text, maxChars)
+		if len(chunks) == 0 {
+			return ""
+		}
+		if cut := chunks[0]; EstimateTokens(cut)+EstimateTokens(marker) <= maxTokens {
+			return cut + marker
+		}
+		// Dense text (code, symbols) uses more tokens per character.
+		maxChars = maxChars * 3 / 4
+	}
+	return strings.TrimSpace(marker)
+}
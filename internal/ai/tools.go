@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Tool describes a function the model may ask to call, in the OpenAI
+// function-calling format.
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+type ToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+// ToolCall is the model's request to run a tool. Arguments is a JSON object
+// encoded as a string.
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+type ToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// NewTool builds a function tool; parameters is its JSON Schema.
+func NewTool(name, description, parameters string) Tool {
+	return Tool{
+		Type: "function",
+		Function: ToolFunction{
+			Name:        name,
+			Description: description,
+			Parameters:  json.RawMessage(parameters),
+		},
+	}
+}
+
+// ChatWithTools sends messages with the given tools available and returns
+// the assistant message, which either has content or ToolCalls to run. The
+// caller runs the tools, appends the assistant message and one "tool"
+// message per call, and asks again.
+func (g *GroqClient) ChatWithTools(ctx context.Context, messages []Message, tools []Tool, maxTokens int, temperature float64) (Message, error) {
+	reqBody := g.buildRequest(messages, maxTokens, temperature, false)
+	reqBody.Tools = tools
+	return g.completeMessage(ctx, reqBody)
+}
@@ -0,0 +1,143 @@
+// Package apperr classifies the errors commands return so the CLI can exit
+// with a code scripts can branch on and, with --output json or yaml, report
+// the failure in a structured form.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/repository"
+	"github.com/snip/internal/validation"
+)
+
+type Kind string
+
+const (
+	KindInternal      Kind = "internal"
+	KindUsage         Kind = "usage"
+	KindValidation    Kind = "validation"
+	KindNotFound      Kind = "not_found"
+	KindAIUnavailable Kind = "ai_unavailable"
+	KindDatabase      Kind = "database"
+)
+
+var exitCodes = map[Kind]int{
+	KindInternal:      1,
+	KindUsage:         2,
+	KindValidation:    3,
+	KindNotFound:      4,
+	KindAIUnavailable: 5,
+	KindDatabase:      6,
+}
+
+// ExitCode is the process exit code for errors of this kind.
+func (k Kind) ExitCode() int {
+	if code, ok := exitCodes[k]; ok {
+		return code
+	}
+	return exitCodes[KindInternal]
+}
+
+// Error tags an error with its kind when the kind cannot be inferred from
+// the error chain, such as an argument that is not a number.
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New tags err with kind. A nil err stays nil.
+func New(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// Invalid returns a validation error with a formatted message.
+func Invalid(format string, args ...any) error {
+	return New(KindValidation, fmt.Errorf(format, args...))
+}
+
+var notFound = []error{
+	repository.ErrNoteNotFound,
+	repository.ErrProjectNotFound,
+	repository.ErrTaskNotFound,
+	repository.ErrTagNotFound,
+	repository.ErrChecklistNotFound,
+	repository.ErrChecklistItemNotFound,
+	repository.ErrSessionNotFound,
+}
+
+var aiUnavailable = []error{
+	ai.ErrNoClient,
+	ai.ErrAuth,
+	ai.ErrRateLimited,
+	ai.ErrUnavailable,
+	ai.ErrBudgetExceeded,
+}
+
+// KindOf classifies err. An explicit Error in the chain wins; otherwise the
+// repository, validation, AI and SQLite errors are recognised, and anything
+// else is internal.
+func KindOf(err error) Kind {
+	var tagged *Error
+	if errors.As(err, &tagged) {
+		return tagged.Kind
+	}
+	for _, target := range notFound {
+		if errors.Is(err, target) {
+			return KindNotFound
+		}
+	}
+	var invalid *validation.Validator
+	if errors.As(err, &invalid) || errors.Is(err, ai.ErrContextTooLong) {
+		return KindValidation
+	}
+	for _, target := range aiUnavailable {
+		if errors.Is(err, target) {
+			return KindAIUnavailable
+		}
+	}
+	var apiErr *ai.APIError
+	if errors.As(err, &apiErr) {
+		return KindAIUnavailable
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return KindDatabase
+	}
+	return KindInternal
+}
+
+// ExitCode returns 0 for a nil error and the code of its kind otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return KindOf(err).ExitCode()
+}
+
+// Report is the structured form of an error printed with --output json or
+// yaml.
+type Report struct {
+	Error    string `json:"error"`
+	Kind     Kind   `json:"kind"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// Describe builds the Report for err.
+func Describe(err error) Report {
+	kind := KindOf(err)
+	return Report{Error: err.Error(), Kind: kind, ExitCode: kind.ExitCode()}
+}
@@ -0,0 +1,34 @@
+package cache
+
+import "time"
+
+// Entry is a provider answer stored under the hash of the request that
+// produced it.
+type Entry struct {
+	Key       string     `json:"key"`
+	Model     string     `json:"model"`
+	Response  string     `json:"response"`
+	Hits      int        `json:"hits"`
+	CreatedAt time.Time  `json:"created_at"`
+	LastHitAt *time.Time `json:"last_hit_at,omitempty"`
+}
+
+// Stats describes the cache contents; Fresh counts the entries still within
+// the TTL.
+type Stats struct {
+	Entries int        `json:"entries"`
+	Fresh   int        `json:"fresh"`
+	Hits    int        `json:"hits"`
+	Bytes   int64      `json:"bytes"`
+	Oldest  *time.Time `json:"oldest,omitempty"`
+	Newest  *time.Time `json:"newest,omitempty"`
+}
+
+func NewEntry(key, model, response string) *Entry {
+	return &Entry{
+		Key:       key,
+		Model:     model,
+		Response:  response,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,48 @@
+package chat
+
+import "time"
+
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleContext   = "context" // a note pulled into the conversation
+)
+
+// Session is a stored multi-turn conversation with the AI.
+type Session struct {
+	ID           int       `json:"id"`
+	Title        string    `json:"title"`
+	MessageCount int       `json:"message_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Message is one entry of a session. Context messages hold the notes added
+// with /add or /search; they are sent to the model but are not part of the
+// transcript.
+type Message struct {
+	ID        int       `json:"id"`
+	SessionID int       `json:"session_id"`
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	NoteID    *int      `json:"note_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewSession(title string) *Session {
+	now := time.Now()
+	return &Session{
+		Title:     title,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func NewMessage(sessionID int, role, content string) *Message {
+	return &Message{
+		SessionID: sessionID,
+		Role:      role,
+		Content:   content,
+		CreatedAt: time.Now(),
+	}
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -37,6 +38,10 @@ func Connect() (*sql.DB, error) {
 		return nil, err
 	}
 
+	if err := migrateDatabase(db); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
 
@@ -140,7 +145,124 @@ func ensureDatabase(db *sql.DB) error {
         FOREIGN KEY (checklist_id) REFERENCES checklists(id) ON DELETE CASCADE
     );
 
+    -- Note Embeddings Table (semantic search)
+    CREATE TABLE IF NOT EXISTS note_embeddings (
+        note_id INTEGER PRIMARY KEY,
+        provider TEXT NOT NULL,
+        content_hash TEXT NOT NULL,
+        vector BLOB NOT NULL,
+        updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (note_id) REFERENCES notes(id) ON DELETE CASCADE
+    );
+
+    CREATE TRIGGER IF NOT EXISTS note_embeddings_ad AFTER DELETE ON notes BEGIN
+        DELETE FROM note_embeddings WHERE note_id = old.id;
+    END;
+
+    -- AI Usage Ledger
+    CREATE TABLE IF NOT EXISTS ai_usage (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        command TEXT NOT NULL,
+        model TEXT NOT NULL,
+        prompt_tokens INTEGER DEFAULT 0,
+        completion_tokens INTEGER DEFAULT 0,
+        latency_ms INTEGER DEFAULT 0,
+        status TEXT NOT NULL,
+        error TEXT,
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+
+    -- AI Response Cache, keyed by a hash of the request
+    CREATE TABLE IF NOT EXISTS ai_cache (
+        key TEXT PRIMARY KEY,
+        model TEXT NOT NULL,
+        response TEXT NOT NULL,
+        hits INTEGER DEFAULT 0,
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        last_hit_at DATETIME
+    );
+
+    -- Note Links (e.g. a summary note and the notes it was built from)
+    CREATE TABLE IF NOT EXISTS note_links (
+        note_id INTEGER NOT NULL,
+        target_type TEXT NOT NULL,
+        target_id INTEGER NOT NULL,
+        relation TEXT NOT NULL DEFAULT 'source',
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (note_id, target_type, target_id),
+        FOREIGN KEY (note_id) REFERENCES notes(id) ON DELETE CASCADE
+    );
+
+    CREATE TRIGGER IF NOT EXISTS note_links_ad AFTER DELETE ON notes BEGIN
+        DELETE FROM note_links WHERE note_id = old.id OR (target_type = 'note' AND target_id = old.id);
+    END;
+
+    -- AI Chat Sessions
+    CREATE TABLE IF NOT EXISTS ai_sessions (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        title TEXT NOT NULL DEFAULT '',
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE TABLE IF NOT EXISTS ai_messages (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        session_id INTEGER NOT NULL,
+        role TEXT NOT NULL,
+        content TEXT NOT NULL,
+        note_id INTEGER,
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (session_id) REFERENCES ai_sessions(id) ON DELETE CASCADE
+    );
+
+    -- Tool calls made by the AI
+    CREATE TABLE IF NOT EXISTS ai_actions (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        session_id INTEGER,
+        tool TEXT NOT NULL,
+        arguments TEXT NOT NULL,
+        status TEXT NOT NULL,
+        result TEXT NOT NULL DEFAULT '',
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+
+    -- Task status transitions, for standup and weekly reports
+    CREATE TABLE IF NOT EXISTS task_activity (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        task_id INTEGER NOT NULL,
+        project_id INTEGER NOT NULL,
+        task_title TEXT NOT NULL,
+        from_status TEXT,
+        to_status TEXT NOT NULL,
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE TRIGGER IF NOT EXISTS tasks_status_au AFTER UPDATE OF status ON tasks
+    WHEN old.status IS NOT new.status BEGIN
+        INSERT INTO task_activity (task_id, project_id, task_title, from_status, to_status)
+        VALUES (new.id, new.project_id, new.title, old.status, new.status);
+    END;
+
+    -- Note pairs marked as not duplicates by snip dedupe (note_a < note_b)
+    CREATE TABLE IF NOT EXISTS dedupe_ignored (
+        note_a INTEGER NOT NULL,
+        note_b INTEGER NOT NULL,
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (note_a, note_b),
+        FOREIGN KEY (note_a) REFERENCES notes(id) ON DELETE CASCADE,
+        FOREIGN KEY (note_b) REFERENCES notes(id) ON DELETE CASCADE
+    );
+
+    CREATE TRIGGER IF NOT EXISTS dedupe_ignored_ad AFTER DELETE ON notes BEGIN
+        DELETE FROM dedupe_ignored WHERE note_a = old.id OR note_b = old.id;
+    END;
+
     -- Indexes
+    CREATE INDEX IF NOT EXISTS idx_task_activity_created_at ON task_activity(created_at);
+    CREATE INDEX IF NOT EXISTS idx_ai_messages_session_id ON ai_messages(session_id);
+    CREATE INDEX IF NOT EXISTS idx_note_links_target ON note_links(target_type, target_id);
+    CREATE INDEX IF NOT EXISTS idx_ai_usage_created_at ON ai_usage(created_at);
+    CREATE INDEX IF NOT EXISTS idx_ai_cache_created_at ON ai_cache(created_at);
     CREATE INDEX IF NOT EXISTS idx_projects_status ON projects(status);
     CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks(project_id);
     CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
@@ -152,3 +274,55 @@ func ensureDatabase(db *sql.DB) error {
 	_, err := db.Exec(query)
 	return err
 }
+
+// migrateDatabase applies schema changes to tables that already exist in
+// older databases. Every step must be safe to run on each start.
+func migrateDatabase(db *sql.DB) error {
+	if err := addColumnIfMissing(db, "tasks", "parent_id", "INTEGER REFERENCES tasks(id) ON DELETE SET NULL"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "tasks", "source_note_id", "INTEGER REFERENCES notes(id) ON DELETE SET NULL"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "ai_usage", "redactions", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "notes", "language", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	_, err := db.Exec(`
+    CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id);
+    CREATE INDEX IF NOT EXISTS idx_tasks_source_note_id ON tasks(source_note_id);
+    `)
+	return err
+}
+
+func addColumnIfMissing(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid          int
+			name, ctype  string
+			notNull, pk  int
+			defaultValue sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &ctype, &notNull, &defaultValue, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
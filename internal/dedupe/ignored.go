@@ -0,0 +1,22 @@
+package dedupe
+
+import "time"
+
+// IgnoredPair is a pair of notes the user marked as not duplicates, stored
+// with NoteA < NoteB so it is found in either order.
+type IgnoredPair struct {
+	NoteA     int       `json:"note_a"`
+	NoteB     int       `json:"note_b"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewIgnoredPair(a, b int) *IgnoredPair {
+	if a > b {
+		a, b = b, a
+	}
+	return &IgnoredPair{
+		NoteA:     a,
+		NoteB:     b,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,224 @@
+// Package dedupe finds near-duplicate texts with word shingles and MinHash,
+// without any network access.
+package dedupe
+
+import (
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// ShingleSize is the number of consecutive words in a shingle.
+	ShingleSize = 3
+
+	// NumHashes is the MinHash signature length, split into bands of rows
+	// by banding.
+	NumHashes = 128
+
+	// minRecall is the chance a pair exactly at the threshold must have of
+	// sharing a band, and so of being compared.
+	minRecall = 0.99
+
+	// DefaultThreshold is the shingle overlap (Jaccard similarity) from
+	// which two notes are reported.
+	DefaultThreshold = 0.5
+)
+
+// Document is a text to compare, identified by ID (a note ID).
+type Document struct {
+	ID   int
+	Text string
+}
+
+// Pair is two documents whose similarity reached the threshold, with A < B.
+type Pair struct {
+	A          int
+	B          int
+	Similarity float64
+}
+
+// Shingles returns the hashed set of ShingleSize-word shingles of text,
+// ignoring case and punctuation. Texts shorter than a shingle give a single
+// shingle with all their words.
+func Shingles(text string) map[uint64]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	set := make(map[uint64]struct{})
+	if len(words) == 0 {
+		return set
+	}
+	if len(words) < ShingleSize {
+		set[hashString(strings.Join(words, " "))] = struct{}{}
+		return set
+	}
+	for i := 0; i+ShingleSize <= len(words); i++ {
+		set[hashString(strings.Join(words[i:i+ShingleSize], " "))] = struct{}{}
+	}
+	return set
+}
+
+// Signature is the MinHash of a shingle set: for each of NumHashes hash
+// functions, the smallest hash of any shingle. Two signatures agree in about
+// the same fraction of positions as the Jaccard similarity of their sets,
+// which is what makes the banding in FindPairs work.
+type Signature [NumHashes]uint64
+
+var seeds = func() [NumHashes]uint64 {
+	var s [NumHashes]uint64
+	x := uint64(0x5eed)
+	for i := range s {
+		x = splitmix64(x)
+		s[i] = x
+	}
+	return s
+}()
+
+func NewSignature(shingles map[uint64]struct{}) Signature {
+	var sig Signature
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+	for sh := range shingles {
+		for i, seed := range seeds {
+			if h := splitmix64(sh ^ seed); h < sig[i] {
+				sig[i] = h
+			}
+		}
+	}
+	return sig
+}
+
+// Jaccard is the exact similarity of two shingle sets.
+func Jaccard(a, b map[uint64]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for sh := range a {
+		if _, ok := b[sh]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// FindPairs returns the pairs of docs with a Jaccard similarity of at least
+// threshold, most similar first. Candidates come from locality-sensitive
+// hashing over the signature bands, sized for threshold by banding, so not
+// every pair is compared; each candidate is then checked against its exact
+// shingle overlap. Thresholds too low for banding compare every pair.
+func FindPairs(docs []Document, threshold float64) []Pair {
+	shingles := make([]map[uint64]struct{}, len(docs))
+	for i, doc := range docs {
+		shingles[i] = Shingles(doc.Text)
+	}
+
+	var pairs []Pair
+	compare := func(i, j int) {
+		similarity := Jaccard(shingles[i], shingles[j])
+		if similarity < threshold {
+			return
+		}
+		a, b := docs[i].ID, docs[j].ID
+		if a > b {
+			a, b = b, a
+		}
+		pairs = append(pairs, Pair{A: a, B: b, Similarity: similarity})
+	}
+
+	if rows, ok := banding(threshold); ok {
+		seen := make(map[[2]int]bool)
+		for _, members := range buckets(shingles, rows) {
+			for x := 0; x < len(members); x++ {
+				for y := x + 1; y < len(members); y++ {
+					i, j := members[x], members[y]
+					if seen[[2]int{i, j}] {
+						continue
+					}
+					seen[[2]int{i, j}] = true
+					compare(i, j)
+				}
+			}
+		}
+	} else {
+		for i := range docs {
+			for j := i + 1; j < len(docs); j++ {
+				compare(i, j)
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Similarity != pairs[j].Similarity {
+			return pairs[i].Similarity > pairs[j].Similarity
+		}
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+	return pairs
+}
+
+// banding picks how many signature rows go in each band. Two documents with
+// similarity s share a band with probability 1-(1-s^rows)^bands: more rows
+// mean fewer false candidates but a curve that rises later. It returns the
+// most rows for which a pair at threshold still reaches minRecall, and false
+// when even single-row bands do not.
+func banding(threshold float64) (rows int, ok bool) {
+	for rows = NumHashes; rows >= 1; rows /= 2 {
+		bands := NumHashes / rows
+		if 1-math.Pow(1-math.Pow(threshold, float64(rows)), float64(bands)) >= minRecall {
+			return rows, true
+		}
+	}
+	return 0, false
+}
+
+// buckets hashes every band of each non-empty document's signature; the
+// documents sharing a bucket are the candidate pairs.
+func buckets(shingles []map[uint64]struct{}, rows int) map[[2]uint64][]int {
+	buckets := make(map[[2]uint64][]int)
+	for i, set := range shingles {
+		if len(set) == 0 {
+			continue
+		}
+		sig := NewSignature(set)
+		for band := 0; band < NumHashes/rows; band++ {
+			key := [2]uint64{uint64(band), hashBand(sig[band*rows : (band+1)*rows])}
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+	return buckets
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+func hashBand(values []uint64) uint64 {
+	h := uint64(0)
+	for _, v := range values {
+		h = splitmix64(h ^ v)
+	}
+	return h
+}
+
+// splitmix64 is a fast 64-bit mixer; xored with a seed it gives a family of
+// independent-enough hash functions for MinHash.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,214 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of change a diff line represents.
+type Op int
+
+const (
+	Equal Op = iota
+	Insert
+	Delete
+)
+
+// maxLCSCells bounds the LCS table; past it the changed region is reported
+// as a plain delete-then-insert instead of a minimal diff.
+const maxLCSCells = 4_000_000
+
+// Line is one line of a line diff.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Hunk is a group of changes with surrounding context, as in a unified diff.
+// Start lines are 1-based.
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []Line
+}
+
+// Header returns the "@@ -a,b +c,d @@" line of the hunk.
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// An empty range points at the line before it.
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// SplitLines splits text into lines without their line endings.
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// Lines computes a line diff of a and b using the longest common
+// subsequence.
+func Lines(a, b []string) []Line {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]Line, 0, len(a)+len(b))
+	for _, s := range a[:prefix] {
+		result = append(result, Line{Equal, s})
+	}
+	result = append(result, lcs(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, s := range a[len(a)-suffix:] {
+		result = append(result, Line{Equal, s})
+	}
+	return result
+}
+
+func lcs(a, b []string) []Line {
+	n, m := len(a), len(b)
+	if n*m > maxLCSCells {
+		result := make([]Line, 0, n+m)
+		for _, s := range a {
+			result = append(result, Line{Delete, s})
+		}
+		for _, s := range b {
+			result = append(result, Line{Insert, s})
+		}
+		return result
+	}
+
+	// table[i][j] is the LCS length of a[i:] and b[j:].
+	table := make([][]int, n+1)
+	for i := range table {
+		table[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else {
+				table[i][j] = max(table[i+1][j], table[i][j+1])
+			}
+		}
+	}
+
+	result := make([]Line, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, Line{Equal, a[i]})
+			i++
+			j++
+		case table[i+1][j] >= table[i][j+1]:
+			result = append(result, Line{Delete, a[i]})
+			i++
+		default:
+			result = append(result, Line{Insert, b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		result = append(result, Line{Delete, a[i]})
+	}
+	for ; j < m; j++ {
+		result = append(result, Line{Insert, b[j]})
+	}
+	return result
+}
+
+// Unified diffs two texts and groups the changes into hunks with up to
+// context unchanged lines around them. It returns nil when the texts have
+// the same lines.
+func Unified(oldText, newText string, context int) []Hunk {
+	lines := Lines(SplitLines(oldText), SplitLines(newText))
+
+	var hunks []Hunk
+	var current *Hunk
+	oldLine, newLine := 1, 1
+	lastChange := -1
+
+	for i, l := range lines {
+		if l.Op != Equal {
+			if current == nil || i-lastChange > 2*context {
+				if current != nil {
+					current.Lines = append(current.Lines, lines[lastChange+1:lastChange+1+context]...)
+					hunks = append(hunks, countHunk(*current))
+				}
+				start := max(i-context, 0)
+				current = &Hunk{
+					OldStart: oldLine - (i - start),
+					NewStart: newLine - (i - start),
+				}
+				current.Lines = append(current.Lines, lines[start:i]...)
+			} else {
+				current.Lines = append(current.Lines, lines[lastChange+1:i]...)
+			}
+			current.Lines = append(current.Lines, l)
+			lastChange = i
+		}
+
+		switch l.Op {
+		case Equal:
+			oldLine++
+			newLine++
+		case Delete:
+			oldLine++
+		case Insert:
+			newLine++
+		}
+	}
+
+	if current != nil {
+		end := min(lastChange+1+context, len(lines))
+		current.Lines = append(current.Lines, lines[lastChange+1:end]...)
+		hunks = append(hunks, countHunk(*current))
+	}
+	return hunks
+}
+
+// countHunk fills in the number of old and new lines the hunk covers.
+func countHunk(h Hunk) Hunk {
+	for _, l := range h.Lines {
+		if l.Op != Insert {
+			h.OldLines++
+		}
+		if l.Op != Delete {
+			h.NewLines++
+		}
+	}
+	return h
+}
+
+// Stats counts the inserted and deleted lines in hunks.
+func Stats(hunks []Hunk) (added, removed int) {
+	for _, h := range hunks {
+		for _, l := range h.Lines {
+			switch l.Op {
+			case Insert:
+				added++
+			case Delete:
+				removed++
+			}
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,21 @@
+package embedding
+
+import "time"
+
+type Embedding struct {
+	NoteID      int       `json:"note_id"`
+	Provider    string    `json:"provider"`
+	ContentHash string    `json:"content_hash"`
+	Vector      []float32 `json:"vector"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func NewEmbedding(noteID int, provider, contentHash string, vector []float32) *Embedding {
+	return &Embedding{
+		NoteID:      noteID,
+		Provider:    provider,
+		ContentHash: contentHash,
+		Vector:      vector,
+		UpdatedAt:   time.Now(),
+	}
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/repository"
+	"github.com/snip/internal/task"
+)
+
+type ActionItemHandler interface {
+	ExtractActionItems(idStr string, projectID int, yes bool) error
+}
+
+type actionItemHandler struct {
+	noteRepo    repository.NoteRepository
+	projectRepo repository.ProjectRepository
+	taskRepo    repository.TaskRepository
+	groqClient  *ai.GroqClient
+	groqErr     error
+}
+
+func NewActionItemHandler(noteRepo repository.NoteRepository, projectRepo repository.ProjectRepository, taskRepo repository.TaskRepository) ActionItemHandler {
+	groqClient, groqErr := ai.NewGroqClient()
+	return &actionItemHandler{
+		noteRepo:    noteRepo,
+		projectRepo: projectRepo,
+		taskRepo:    taskRepo,
+		groqClient:  groqClient,
+		groqErr:     groqErr,
+	}
+}
+
+// ExtractActionItems asks AI for the action items in a note, lets the user
+// pick which to keep and creates them as tasks in the project, each pointing
+// back to the note. Items already created from the same note are skipped.
+func (h *actionItemHandler) ExtractActionItems(idStr string, projectID int, yes bool) error {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return apperr.Invalid("invalid note ID: %s", idStr)
+	}
+	if projectID <= 0 {
+		return apperr.Invalid("--project is required")
+	}
+
+	if h.groqClient == nil {
+		return noAIClient(h.groqErr)
+	}
+
+	n, err := h.noteRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch note: %w", err)
+	}
+	if h.groqClient.ExcludesNote(n.Tags) {
+		return errExcludedNote(n.ID)
+	}
+	p, err := h.projectRepo.GetByID(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch project: %w", err)
+	}
+
+	existing, err := h.existingActionTasks(projectID, n.ID)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	fmt.Printf("Extracting action items from note #%d with AI...\n", n.ID)
+	extracted, err := h.groqClient.ExtractActionItems(ctx, n.Title, n.Content, time.Now())
+	if err != nil {
+		if ctx.Err() != nil {
+			fmt.Println("Extraction cancelled, no tasks were created.")
+			return nil
+		}
+		return wrapAIError("failed to extract action items", err)
+	}
+
+	if len(extracted.Items) == 0 {
+		fmt.Println("No action items found in the note.")
+		return nil
+	}
+
+	fmt.Printf("\nAction items in \"%s\":\n", n.Title)
+	var candidates []int
+	for i, item := range extracted.Items {
+		fmt.Printf("  %d. %s%s\n", i+1, item.Title, actionItemDetails(item))
+		if taskID, ok := existing[strings.ToLower(item.Title)]; ok {
+			fmt.Printf("     └── already task #%d\n", taskID)
+			continue
+		}
+		candidates = append(candidates, i+1)
+	}
+	fmt.Println()
+
+	if len(candidates) == 0 {
+		fmt.Println("Every action item is already a task, nothing to create.")
+		return nil
+	}
+
+	selected := candidates
+	if !yes {
+		var ok bool
+		selected, ok = selectActionItems(len(extracted.Items), candidates, p.Name)
+		if !ok || len(selected) == 0 {
+			fmt.Println("Cancelled, no tasks were created.")
+			return nil
+		}
+	}
+
+	for _, number := range selected {
+		item := extracted.Items[number-1]
+
+		t := task.NewTask(projectID, item.Title, actionItemDescription(item), "medium")
+		t.DueDate = item.DueDate()
+		t.SourceNoteID = &n.ID
+		if err := h.taskRepo.Create(t); err != nil {
+			return fmt.Errorf("failed to create task: %w", err)
+		}
+		fmt.Printf("● #%d  %s [%s]\n", t.ID, t.Title, t.Priority)
+	}
+
+	fmt.Printf("\n%d task(s) created in project #%d from note #%d.\n", len(selected), p.ID, n.ID)
+	return nil
+}
+
+// existingActionTasks maps the lowercased titles of tasks already extracted
+// from the note into the project to their IDs.
+func (h *actionItemHandler) existingActionTasks(projectID, noteID int) (map[string]int, error) {
+	tasks, err := h.taskRepo.GetByProjectID(projectID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+
+	existing := make(map[string]int)
+	for _, t := range tasks {
+		if t.SourceNoteID != nil && *t.SourceNoteID == noteID {
+			existing[strings.ToLower(t.Title)] = t.ID
+		}
+	}
+	return existing, nil
+}
+
+// selectActionItems asks which items to create. Enter keeps the default
+// candidates; it reports false when the user declines or input is closed.
+func selectActionItems(total int, candidates []int, projectName string) ([]int, bool) {
+	question := fmt.Sprintf("Create which items in \"%s\"? [Enter] all new / numbers (e.g. 1,3) / [n]one: ", projectName)
+	for {
+		answer, ok := readInput(question)
+		if !ok {
+			return nil, false
+		}
+
+		switch strings.ToLower(answer) {
+		case "", "a", "all":
+			return candidates, true
+		case "n", "no", "none":
+			return nil, false
+		}
+
+		numbers, err := parseIDs(answer)
+		if err == nil {
+			err = checkSelection(numbers, total, candidates)
+		}
+		if err != nil {
+			fmt.Printf("%v\n", err)
+			continue
+		}
+		return numbers, true
+	}
+}
+
+// checkSelection accepts only numbers of listed items that are not tasks
+// yet, each at most once.
+func checkSelection(numbers []int, total int, candidates []int) error {
+	allowed := make(map[int]bool, len(candidates))
+	for _, number := range candidates {
+		allowed[number] = true
+	}
+
+	seen := make(map[int]bool)
+	for _, number := range numbers {
+		if number < 1 || number > total {
+			return fmt.Errorf("no item %d, choose between 1 and %d", number, total)
+		}
+		if !allowed[number] {
+			return fmt.Errorf("item %d is already a task", number)
+		}
+		if seen[number] {
+			return fmt.Errorf("item %d selected twice", number)
+		}
+		seen[number] = true
+	}
+	return nil
+}
+
+func actionItemDetails(item ai.ActionItem) string {
+	var details []string
+	if item.Owner != "" {
+		details = append(details, "owner: "+item.Owner)
+	}
+	if item.Due != "" {
+		details = append(details, "due: "+item.Due)
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
+
+// actionItemDescription keeps the owner, which tasks have no field for, in
+// the task description.
+func actionItemDescription(item ai.ActionItem) string {
+	if item.Owner == "" {
+		return ""
+	}
+	return "Responsável: " + item.Owner
+}
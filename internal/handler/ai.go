@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/snip/internal/action"
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/repository"
+	"github.com/snip/internal/usage"
+)
+
+type AIHandler interface {
+	UsageReport(since string, groupBy string) error
+	ActionLog(limit int) error
+	Prompts(export bool) error
+	CacheStats() error
+	ClearCache(expiredOnly bool) error
+}
+
+type aiHandler struct {
+	usageRepo  repository.UsageRepository
+	actionRepo repository.ActionRepository
+	cacheRepo  repository.CacheRepository
+}
+
+func NewAIHandler(usageRepo repository.UsageRepository, actionRepo repository.ActionRepository, cacheRepo repository.CacheRepository) AIHandler {
+	return &aiHandler{
+		usageRepo:  usageRepo,
+		actionRepo: actionRepo,
+		cacheRepo:  cacheRepo,
+	}
+}
+
+type usageRow struct {
+	group            string
+	calls            int
+	errors           int
+	promptTokens     int
+	completionTokens int
+	redacted         int
+	latencyTotal     float64
+	cost             float64
+}
+
+func (h *aiHandler) UsageReport(since string, groupBy string) error {
+	if groupBy != "model" && groupBy != "command" {
+		return apperr.Invalid("invalid --by value: %s (use model or command)", groupBy)
+	}
+
+	sinceTime, err := parseSinceFilter(since)
+	if err != nil {
+		return apperr.Invalid("invalid --since value: %w", err)
+	}
+
+	cfg, err := ai.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	summaries, err := h.usageRepo.Summarize(sinceTime, groupBy)
+	if err != nil {
+		return fmt.Errorf("failed to load usage: %w", err)
+	}
+
+	fmt.Printf("AI usage since %s (by %s)\n\n", sinceTime.Format("2006-01-02"), groupBy)
+
+	if len(summaries) == 0 {
+		fmt.Println("No AI calls recorded.")
+	} else {
+		rows := aggregateUsage(cfg, summaries)
+
+		var total usageRow
+		total.group = "TOTAL"
+		for _, r := range rows {
+			total.calls += r.calls
+			total.errors += r.errors
+			total.promptTokens += r.promptTokens
+			total.completionTokens += r.completionTokens
+			total.redacted += r.redacted
+			total.latencyTotal += r.latencyTotal
+			total.cost += r.cost
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\tCALLS\tERRORS\tREDACTED\tPROMPT\tCOMPLETION\tAVG LATENCY\tEST. COST\n", groupLabel(groupBy))
+		for _, r := range append(rows, total) {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%s\t$%.4f\n",
+				r.group, r.calls, r.errors, r.redacted, r.promptTokens, r.completionTokens,
+				averageLatency(r), r.cost)
+		}
+		w.Flush()
+	}
+
+	warned := make(map[string]bool)
+	for _, s := range summaries {
+		if _, ok := cfg.PriceFor(s.Model); !ok && !warned[s.Model] {
+			warned[s.Model] = true
+			fmt.Printf("\n⚠️  No price configured for %s; its cost is counted as $0.\n", s.Model)
+		}
+	}
+
+	if cfg.MonthlyBudgetUSD > 0 {
+		spent, err := ai.MonthlySpend(h.usageRepo, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to compute monthly spend: %w", err)
+		}
+		fmt.Printf("\nBudget: $%.4f of $%.2f used this month (%.0f%%)\n",
+			spent, cfg.MonthlyBudgetUSD, spent/cfg.MonthlyBudgetUSD*100)
+	}
+
+	return nil
+}
+
+// aggregateUsage folds per-model rows into one row per group, pricing each
+// model separately so command totals mix models correctly.
+func aggregateUsage(cfg *ai.Config, summaries []*usage.Summary) []usageRow {
+	byGroup := make(map[string]*usageRow)
+	var order []string
+
+	for _, s := range summaries {
+		r, ok := byGroup[s.Group]
+		if !ok {
+			r = &usageRow{group: s.Group}
+			byGroup[s.Group] = r
+			order = append(order, s.Group)
+		}
+		r.calls += s.Calls
+		r.errors += s.Errors
+		r.promptTokens += s.PromptTokens
+		r.completionTokens += s.CompletionTokens
+		r.redacted += s.Redacted
+		r.latencyTotal += s.AvgLatencyMs * float64(s.Calls)
+		r.cost += cfg.Cost(s.Model, s.PromptTokens, s.CompletionTokens)
+	}
+
+	rows := make([]usageRow, 0, len(order))
+	for _, group := range order {
+		rows = append(rows, *byGroup[group])
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].cost > rows[j].cost
+	})
+	return rows
+}
+
+func averageLatency(r usageRow) string {
+	if r.calls == 0 {
+		return "-"
+	}
+	avg := time.Duration(r.latencyTotal/float64(r.calls)) * time.Millisecond
+	return avg.Round(10 * time.Millisecond).String()
+}
+
+func groupLabel(groupBy string) string {
+	if groupBy == "command" {
+		return "COMMAND"
+	}
+	return "MODEL"
+}
+
+// ActionLog lists the latest tool calls the AI made, newest first.
+func (h *aiHandler) ActionLog(limit int) error {
+	actions, err := h.actionRepo.GetRecent(limit)
+	if err != nil {
+		return fmt.Errorf("failed to fetch AI actions: %w", err)
+	}
+
+	if len(actions) == 0 {
+		fmt.Println("No AI actions recorded.")
+		return nil
+	}
+
+	for _, a := range actions {
+		icon := "✓"
+		switch a.Status {
+		case action.StatusDeclined:
+			icon = "✗"
+		case action.StatusFailed:
+			icon = "!"
+		}
+
+		fmt.Printf("%s #%d %s %s [%s]\n", icon, a.ID, a.Tool, a.Arguments, a.Status)
+		details := a.CreatedAt.Format("2006-01-02 15:04")
+		if a.SessionID != nil {
+			details += fmt.Sprintf(", chat session #%d", *a.SessionID)
+		}
+		fmt.Printf("   └── %s\n", details)
+	}
+	return nil
+}
+
+// Prompts lists the prompt templates for the configured language and where
+// each one is loaded from. With export, the defaults are first copied to the
+// prompts directory so they can be edited.
+func (h *aiHandler) Prompts(export bool) error {
+	cfg, err := ai.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dir, err := ai.PromptsDir()
+	if err != nil {
+		return err
+	}
+
+	if export {
+		written, skipped, err := ai.ExportPrompts(cfg.Language)
+		if err != nil {
+			return fmt.Errorf("failed to export prompts: %w", err)
+		}
+		fmt.Printf("Exported %d prompt(s) to %s", len(written), filepath.Join(dir, cfg.Language))
+		if len(skipped) > 0 {
+			fmt.Printf(" (%d already there, kept)", len(skipped))
+		}
+		fmt.Printf("\n\n")
+	}
+
+	infos, err := ai.DescribePrompts(cfg.Language)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Language: %s\n", cfg.Language)
+	fmt.Printf("Overrides: %s\n\n", dir)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROMPT\tSOURCE")
+	for _, info := range infos {
+		source := info.Source
+		if info.Path != "" {
+			source = info.Path
+		}
+		if info.Err != nil {
+			source = info.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\n", info.Name, source)
+	}
+	return w.Flush()
+}
+
+// CacheStats shows how many answers are cached, how many are still fresh
+// under the configured TTL and how often they were reused.
+func (h *aiHandler) CacheStats() error {
+	cfg, err := ai.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	stats, err := h.cacheRepo.Stats(time.Now().Add(-cfg.CacheTTL()))
+	if err != nil {
+		return fmt.Errorf("failed to read AI cache: %w", err)
+	}
+
+	if cfg.CacheTTL() > 0 {
+		fmt.Printf("TTL: %s\n", cfg.CacheTTL())
+	} else {
+		fmt.Println("TTL: disabled (cache_ttl_hours < 0)")
+	}
+
+	if stats.Entries == 0 {
+		fmt.Println("The AI cache is empty.")
+		return nil
+	}
+
+	fmt.Printf("Entries: %d (%d fresh, %d expired)\n", stats.Entries, stats.Fresh, stats.Entries-stats.Fresh)
+	fmt.Printf("Hits: %d\n", stats.Hits)
+	fmt.Printf("Size: %s\n", formatBytes(stats.Bytes))
+	if stats.Oldest != nil && stats.Newest != nil {
+		fmt.Printf("Stored: %s to %s\n", stats.Oldest.Format("2006-01-02 15:04"), stats.Newest.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+// ClearCache deletes cached answers, or only the expired ones.
+func (h *aiHandler) ClearCache(expiredOnly bool) error {
+	var before time.Time
+	if expiredOnly {
+		cfg, err := ai.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		before = time.Now().Add(-cfg.CacheTTL())
+	}
+
+	deleted, err := h.cacheRepo.Clear(before)
+	if err != nil {
+		return fmt.Errorf("failed to clear AI cache: %w", err)
+	}
+
+	if expiredOnly {
+		fmt.Printf("Removed %d expired cached answer(s).\n", deleted)
+	} else {
+		fmt.Printf("Removed %d cached answer(s).\n", deleted)
+	}
+	return nil
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
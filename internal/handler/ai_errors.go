@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/snip/internal/ai"
+)
+
+// wrapAIError keeps the original error chain and appends a hint for the
+// failures users can act on.
+func wrapAIError(action string, err error) error {
+	hint := ""
+
+	var apiErr *ai.APIError
+	switch {
+	case errors.Is(err, ai.ErrAuth):
+		hint = "check that GROQ_API_KEY is set to a valid key (see README_API_KEY.md)"
+	case errors.Is(err, ai.ErrRateLimited):
+		hint = "the rate limit was reached; wait a moment and try again"
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			hint = fmt.Sprintf("the rate limit was reached; try again in %s", apiErr.RetryAfter.Round(time.Second))
+		}
+	case errors.Is(err, ai.ErrContextTooLong):
+		hint = "the input is too long for the model; try a shorter note or fewer notes"
+	case errors.Is(err, ai.ErrUnavailable):
+		hint = "the AI service is having problems; try again later"
+	case errors.Is(err, ai.ErrBudgetExceeded):
+		hint = "raise monthly_budget_usd in ~/.snip/config.json or check 'snip ai usage'"
+	}
+
+	if hint == "" {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	return fmt.Errorf("%s: %w\n  hint: %s", action, err, hint)
+}
+
+// noAIClient is returned by the AI commands when the client could not be
+// built, keeping the reason, such as a missing API key or an invalid
+// ~/.snip/config.json.
+func noAIClient(err error) error {
+	if err == nil {
+		return ai.ErrNoClient
+	}
+	return fmt.Errorf("%w: %w", ai.ErrNoClient, err)
+}
@@ -0,0 +1,465 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/chat"
+	"github.com/snip/internal/link"
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/repository"
+)
+
+const (
+	chatTag              = "chat"
+	maxChatNoteTokens    = 1000 // per note pulled into context
+	maxChatSearchResults = 3
+	maxChatTitle         = 60
+	maxToolRounds        = 5 // tool call round trips per question
+)
+
+const chatHelp = `Commands:
+  /add #42 [#43 ...]  add notes to the conversation context
+  /search <term>      add the best matching notes to the context
+  /save               save the transcript as a note
+  /save last          save the last answer as a note
+  /help               show this help
+  /exit               leave the chat (Ctrl+D also works)`
+
+type ChatHandler interface {
+	Chat(sessionID int) error
+	ListSessions(limit int) error
+}
+
+type chatHandler struct {
+	noteRepo   repository.NoteRepository
+	tagRepo    repository.TagRepository
+	chatRepo   repository.ChatRepository
+	linkRepo   repository.LinkRepository
+	tools      *ToolRunner
+	groqClient *ai.GroqClient
+	groqErr    error
+}
+
+// NewChatHandler creates the chat handler. tools may be nil, in which case
+// answers are streamed and the model cannot act on projects and tasks.
+func NewChatHandler(noteRepo repository.NoteRepository, tagRepo repository.TagRepository, chatRepo repository.ChatRepository, linkRepo repository.LinkRepository, tools *ToolRunner) ChatHandler {
+	groqClient, groqErr := ai.NewGroqClient()
+	return &chatHandler{
+		noteRepo:   noteRepo,
+		tagRepo:    tagRepo,
+		chatRepo:   chatRepo,
+		linkRepo:   linkRepo,
+		tools:      tools,
+		groqClient: groqClient,
+		groqErr:    groqErr,
+	}
+}
+
+// chatState is the conversation being held in the REPL. The session row is
+// only created once the first message is stored.
+type chatState struct {
+	session  *chat.Session
+	messages []*chat.Message
+}
+
+func (h *chatHandler) ListSessions(limit int) error {
+	sessions, err := h.chatRepo.ListSessions(limit)
+	if err != nil {
+		return fmt.Errorf("failed to fetch chat sessions: %w", err)
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("No chat sessions found.")
+		return nil
+	}
+
+	for _, s := range sessions {
+		fmt.Printf("● #%d  %s\n", s.ID, sessionTitle(s))
+		fmt.Printf("   └── %d message(s), last used %s\n", s.MessageCount, s.UpdatedAt.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+func (h *chatHandler) Chat(sessionID int) error {
+	if h.groqClient == nil {
+		return noAIClient(h.groqErr)
+	}
+
+	state := &chatState{}
+	if sessionID > 0 {
+		s, err := h.chatRepo.GetSession(sessionID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch chat session: %w", err)
+		}
+		messages, err := h.chatRepo.GetMessages(s.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch chat messages: %w", err)
+		}
+		state.session = s
+		state.messages = messages
+
+		fmt.Printf("Resuming session #%d: %s\n", s.ID, sessionTitle(s))
+		printChatHistory(messages)
+	}
+
+	fmt.Println("Type /help for commands, /exit to quit.")
+
+	for {
+		fmt.Println()
+		line, ok := readInput("you> ")
+		if !ok {
+			break
+		}
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			done, err := h.runChatCommand(state, line)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+			if done {
+				break
+			}
+			continue
+		}
+
+		if err := h.ask(state, line); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	}
+
+	if state.session != nil {
+		fmt.Printf("Session #%d saved. Resume it with: snip ai chat --session %d\n", state.session.ID, state.session.ID)
+	}
+	return nil
+}
+
+func (h *chatHandler) runChatCommand(state *chatState, line string) (bool, error) {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case "/exit", "/quit":
+		return true, nil
+	case "/help":
+		fmt.Println(chatHelp)
+		return false, nil
+	case "/add":
+		return false, h.addNotes(state, arg)
+	case "/search":
+		return false, h.searchNotes(state, arg)
+	case "/save":
+		return false, h.saveChat(state, arg == "last")
+	}
+	return false, fmt.Errorf("unknown command %s (type /help)", name)
+}
+
+// ask sends the question with the conversation so far and stores both the
+// question and the answer. A cancelled answer (Ctrl+C) is not stored.
+func (h *chatHandler) ask(state *chatState, question string) error {
+	userMsg := chat.NewMessage(0, chat.RoleUser, question)
+
+	system, err := h.groqClient.ChatSystemMessages(h.tools != nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	messages := chatMessages(system, append(state.messages, userMsg), h.groqClient.ContextBudget())
+
+	var answer string
+	if h.tools != nil {
+		answer, err = h.askWithTools(ctx, state, messages)
+		if err == nil {
+			fmt.Printf("\nai> %s\n", answer)
+		}
+	} else {
+		fmt.Print("\nai> ")
+		answer, err = h.groqClient.ChatStream(ctx, messages, 2000, 0.5, printToken)
+		fmt.Println()
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			fmt.Println("(answer cancelled)")
+			return nil
+		}
+		return wrapAIError("failed to get answer from AI", err)
+	}
+
+	if err := h.store(state, userMsg); err != nil {
+		return err
+	}
+	return h.store(state, chat.NewMessage(0, chat.RoleAssistant, strings.TrimSpace(answer)))
+}
+
+// askWithTools lets the model call tools until it answers with text. After
+// maxToolRounds the tools are withdrawn so it has to answer.
+func (h *chatHandler) askWithTools(ctx context.Context, state *chatState, messages []ai.Message) (string, error) {
+	// Actions are logged against the session, so it has to exist first.
+	if err := h.ensureSession(state); err != nil {
+		return "", err
+	}
+
+	tools := h.tools.Tools()
+
+	for round := 0; ; round++ {
+		if round == maxToolRounds {
+			tools = nil
+		}
+
+		reply, err := h.groqClient.ChatWithTools(ctx, messages, tools, 2000, 0.5)
+		if err != nil {
+			return "", err
+		}
+		if len(reply.ToolCalls) == 0 {
+			return strings.TrimSpace(reply.Content), nil
+		}
+		if tools == nil {
+			return "", fmt.Errorf("the model kept calling tools without answering")
+		}
+
+		messages = append(messages, reply)
+		for _, call := range reply.ToolCalls {
+			result := h.tools.Run(call, &state.session.ID)
+			messages = append(messages, ai.Message{Role: "tool", ToolCallID: call.ID, Content: result})
+		}
+	}
+}
+
+func (h *chatHandler) ensureSession(state *chatState) error {
+	if state.session != nil {
+		return nil
+	}
+
+	s := chat.NewSession("")
+	if err := h.chatRepo.CreateSession(s); err != nil {
+		return fmt.Errorf("failed to create chat session: %w", err)
+	}
+	state.session = s
+	return nil
+}
+
+// store saves a message, creating the session on first use and naming it
+// after the first question.
+func (h *chatHandler) store(state *chatState, m *chat.Message) error {
+	title := ""
+	if err := h.ensureSession(state); err != nil {
+		return err
+	}
+	if state.session.Title == "" && m.Role == chat.RoleUser {
+		title = truncateTitle(m.Content, maxChatTitle)
+		state.session.Title = title
+	}
+
+	m.SessionID = state.session.ID
+	if err := h.chatRepo.AddMessage(m); err != nil {
+		return fmt.Errorf("failed to save chat message: %w", err)
+	}
+	if err := h.chatRepo.UpdateSession(state.session.ID, title); err != nil {
+		return fmt.Errorf("failed to update chat session: %w", err)
+	}
+
+	state.messages = append(state.messages, m)
+	return nil
+}
+
+func (h *chatHandler) addNotes(state *chatState, arg string) error {
+	if arg == "" {
+		return fmt.Errorf("usage: /add #42 [#43 ...]")
+	}
+
+	for _, field := range strings.FieldsFunc(arg, func(r rune) bool { return r == ' ' || r == ',' }) {
+		id, err := strconv.Atoi(strings.TrimPrefix(field, "#"))
+		if err != nil {
+			return apperr.Invalid("invalid note ID: %s", field)
+		}
+		n, err := h.noteRepo.GetByID(id)
+		if err != nil {
+			return fmt.Errorf("failed to fetch note #%d: %w", id, err)
+		}
+		if h.groqClient.ExcludesNote(n.Tags) {
+			return errExcludedNote(n.ID)
+		}
+		if err := h.addContextNote(state, n.ID, n.Title, n.Content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *chatHandler) searchNotes(state *chatState, term string) error {
+	if term == "" {
+		return fmt.Errorf("usage: /search <term>")
+	}
+
+	notes, err := h.noteRepo.Search(term)
+	if err != nil {
+		return fmt.Errorf("failed to search notes: %w", err)
+	}
+	if len(notes) == 0 {
+		fmt.Println("No notes found.")
+		return nil
+	}
+
+	added := 0
+	for _, n := range notes {
+		if added == maxChatSearchResults {
+			break
+		}
+		excluded, err := noteExcluded(h.groqClient, h.noteRepo, n.ID)
+		if err != nil {
+			return err
+		}
+		if excluded {
+			continue
+		}
+		added++
+		if err := h.addContextNote(state, n.ID, n.Title, n.Content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *chatHandler) addContextNote(state *chatState, id int, title, content string) error {
+	for _, m := range state.messages {
+		if m.Role == chat.RoleContext && m.NoteID != nil && *m.NoteID == id {
+			fmt.Printf("Note #%d is already in context.\n", id)
+			return nil
+		}
+	}
+
+	content = ai.TruncateTokens(content, maxChatNoteTokens)
+
+	m := chat.NewMessage(0, chat.RoleContext, fmt.Sprintf("Note #%d: %s\n\n%s", id, title, content))
+	m.NoteID = &id
+	if err := h.store(state, m); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added note #%d %s to the context.\n", id, title)
+	return nil
+}
+
+// saveChat stores the transcript, or only the last answer, as a note tagged
+// "chat" and linked to the notes that were in context.
+func (h *chatHandler) saveChat(state *chatState, lastOnly bool) error {
+	var b strings.Builder
+	var last *chat.Message
+	for _, m := range state.messages {
+		switch m.Role {
+		case chat.RoleUser:
+			fmt.Fprintf(&b, "**You:** %s\n\n", m.Content)
+		case chat.RoleAssistant:
+			fmt.Fprintf(&b, "**AI:** %s\n\n", m.Content)
+			last = m
+		}
+	}
+	if last == nil {
+		return fmt.Errorf("nothing to save yet")
+	}
+
+	title := "Chat: " + sessionTitle(state.session)
+	content := strings.TrimSpace(b.String())
+	if lastOnly {
+		title = "Answer: " + sessionTitle(state.session)
+		content = last.Content
+	}
+
+	n := note.NewNote(truncateTitle(title, maxChatTitle+8), content)
+	if err := h.noteRepo.Create(n); err != nil {
+		return fmt.Errorf("failed to save note: %w", err)
+	}
+
+	tagObj, err := h.tagRepo.GetOrCreate(chatTag)
+	if err != nil {
+		return fmt.Errorf("failed to tag note: %w", err)
+	}
+	if err := h.noteRepo.AddTagToNote(n.ID, tagObj.ID); err != nil {
+		return fmt.Errorf("failed to tag note: %w", err)
+	}
+
+	for _, m := range state.messages {
+		if m.Role != chat.RoleContext || m.NoteID == nil {
+			continue
+		}
+		if err := h.linkRepo.Create(link.NewLink(n.ID, link.TargetNote, *m.NoteID, link.RelationSource)); err != nil {
+			return fmt.Errorf("failed to link note to its sources: %w", err)
+		}
+	}
+
+	fmt.Printf("Note created successfully!\n")
+	fmt.Printf("● #%d  %s\n", n.ID, n.Title)
+	return nil
+}
+
+// chatMessages turns the stored conversation into a request: the system
+// prompt, every context note, then as many recent turns as fit in budget
+// tokens.
+func chatMessages(system []ai.Message, messages []*chat.Message, budget int) []ai.Message {
+	result := append([]ai.Message{}, system...)
+
+	var turns []*chat.Message
+	for _, m := range messages {
+		if m.Role == chat.RoleContext {
+			result = append(result, ai.Message{Role: "system", Content: m.Content})
+			continue
+		}
+		turns = append(turns, m)
+	}
+
+	start := len(turns)
+	size := ai.EstimateMessagesTokens(result)
+	for start > 0 {
+		size += ai.EstimateTokens(turns[start-1].Content)
+		// Always keep the latest message, even when it is long on its own.
+		if size > budget && start < len(turns) {
+			break
+		}
+		start--
+	}
+
+	for _, m := range turns[start:] {
+		result = append(result, ai.Message{Role: m.Role, Content: m.Content})
+	}
+	return result
+}
+
+func printChatHistory(messages []*chat.Message) {
+	for _, m := range messages {
+		switch m.Role {
+		case chat.RoleUser:
+			fmt.Printf("\nyou> %s\n", m.Content)
+		case chat.RoleAssistant:
+			fmt.Printf("\nai> %s\n", m.Content)
+		case chat.RoleContext:
+			if m.NoteID != nil {
+				fmt.Printf("\n(note #%d in context)\n", *m.NoteID)
+			}
+		}
+	}
+}
+
+func sessionTitle(s *chat.Session) string {
+	if s == nil || s.Title == "" {
+		return "Untitled chat"
+	}
+	return s.Title
+}
+
+func truncateTitle(s string, limit int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len([]rune(s)) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit-3]) + "..."
+}
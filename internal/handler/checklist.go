@@ -6,7 +6,9 @@ import (
 	"strings"
 
 	"github.com/snip/internal/ai"
+	"github.com/snip/internal/apperr"
 	"github.com/snip/internal/checklist"
+	"github.com/snip/internal/output"
 	"github.com/snip/internal/repository"
 )
 
@@ -25,14 +27,16 @@ type checklistHandler struct {
 	checklistRepo     repository.ChecklistRepository
 	checklistItemRepo repository.ChecklistItemRepository
 	groqClient        *ai.GroqClient
+	groqErr           error
 }
 
 func NewChecklistHandler(checklistRepo repository.ChecklistRepository, checklistItemRepo repository.ChecklistItemRepository) ChecklistHandler {
-	groqClient, _ := ai.NewGroqClient()
+	groqClient, groqErr := ai.NewGroqClient()
 	return &checklistHandler{
 		checklistRepo:     checklistRepo,
 		checklistItemRepo:  checklistItemRepo,
 		groqClient:         groqClient,
+		groqErr:            groqErr,
 	}
 }
 
@@ -52,7 +56,7 @@ func (h *checklistHandler) CreateChecklist(title, description string, taskID, pr
 
 func (h *checklistHandler) CreateChecklistWithAI(topic, context string, numItems int, taskID, projectID *int) error {
 	if h.groqClient == nil {
-		return fmt.Errorf("AI client not available")
+		return noAIClient(h.groqErr)
 	}
 
 	if numItems <= 0 {
@@ -62,7 +66,7 @@ func (h *checklistHandler) CreateChecklistWithAI(topic, context string, numItems
 	fmt.Printf("Gerando checklist com IA (%d itens)...\n", numItems)
 	items, err := h.groqClient.GenerateChecklist(topic, context, numItems)
 	if err != nil {
-		return fmt.Errorf("failed to generate checklist: %w", err)
+		return wrapAIError("failed to generate checklist", err)
 	}
 
 	c := checklist.NewChecklist(topic, context)
@@ -103,6 +107,16 @@ func (h *checklistHandler) ListChecklists(taskID, projectID *int) error {
 		return fmt.Errorf("failed to fetch checklists: %w", err)
 	}
 
+	table := output.NewTable("ID", "TITLE", "TASK", "PROJECT", "CREATED")
+	for _, c := range checklists {
+		table.Add(c.ID, c.Title, c.TaskID, c.ProjectID, c.CreatedAt.Format("2006-01-02"))
+	}
+	return output.Print(checklists, table, func() error {
+		return printChecklists(checklists)
+	})
+}
+
+func printChecklists(checklists []*checklist.Checklist) error {
 	if len(checklists) == 0 {
 		fmt.Println("Nenhuma checklist encontrada.")
 		return nil
@@ -130,17 +144,29 @@ func (h *checklistHandler) ShowChecklist(id int) error {
 		return fmt.Errorf("failed to fetch checklist: %w", err)
 	}
 
-	fmt.Printf("● #%d %s\n", c.ID, c.Title)
-	if c.Description != "" {
-		fmt.Printf("   └── %s\n\n", c.Description)
-	}
-
 	// Load items
 	items, err := h.checklistItemRepo.GetByChecklistID(id)
 	if err != nil {
 		return fmt.Errorf("failed to fetch checklist items: %w", err)
 	}
 
+	c.Items = make([]checklist.ChecklistItem, len(items))
+	table := output.NewTable("ID", "DONE", "TITLE", "DESCRIPTION")
+	for i, item := range items {
+		c.Items[i] = *item
+		table.Add(item.ID, item.Completed, item.Title, item.Description)
+	}
+	return output.Print(c, table, func() error {
+		return printChecklist(c, items)
+	})
+}
+
+func printChecklist(c *checklist.Checklist, items []*checklist.ChecklistItem) error {
+	fmt.Printf("● #%d %s\n", c.ID, c.Title)
+	if c.Description != "" {
+		fmt.Printf("   └── %s\n\n", c.Description)
+	}
+
 	if len(items) == 0 {
 		fmt.Println("Nenhum item nesta checklist.")
 		return nil
@@ -214,7 +240,7 @@ func parseIDs(idStr string) ([]int, error) {
 	for _, part := range parts {
 		id, err := strconv.Atoi(strings.TrimSpace(part))
 		if err != nil {
-			return nil, fmt.Errorf("invalid ID: %s", part)
+			return nil, apperr.Invalid("invalid ID: %s", part)
 		}
 		ids = append(ids, id)
 	}
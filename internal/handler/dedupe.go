@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mitchellh/go-wordwrap"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/dedupe"
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/repository"
+)
+
+const (
+	dedupeColumnWidth = 38
+	dedupeRows        = 12 // content lines shown per note
+)
+
+type DedupeHandler interface {
+	FindDuplicates(threshold float64, useAI bool, listOnly bool) error
+}
+
+type dedupeHandler struct {
+	noteRepo   repository.NoteRepository
+	dedupeRepo repository.DedupeRepository
+	groqClient *ai.GroqClient
+	groqErr    error
+}
+
+func NewDedupeHandler(noteRepo repository.NoteRepository, dedupeRepo repository.DedupeRepository) DedupeHandler {
+	groqClient, groqErr := ai.NewGroqClient()
+	return &dedupeHandler{
+		noteRepo:   noteRepo,
+		dedupeRepo: dedupeRepo,
+		groqClient: groqClient,
+		groqErr:    groqErr,
+	}
+}
+
+// FindDuplicates compares every note with MinHash, optionally asks the AI to
+// confirm each candidate pair, and shows the pairs side by side to be merged
+// or ignored. Ignored pairs are remembered and not shown again.
+func (h *dedupeHandler) FindDuplicates(threshold float64, useAI bool, listOnly bool) error {
+	if threshold <= 0 || threshold > 1 {
+		return apperr.Invalid("threshold must be between 0 and 1, got %g", threshold)
+	}
+	if useAI && h.groqClient == nil {
+		return noAIClient(h.groqErr)
+	}
+
+	notes, err := h.noteRepo.GetAll(true, 0)
+	if err != nil {
+		return fmt.Errorf("failed to fetch notes: %w", err)
+	}
+
+	ignored, err := h.dedupeRepo.GetIgnored()
+	if err != nil {
+		return fmt.Errorf("failed to fetch ignored pairs: %w", err)
+	}
+	skip := make(map[[2]int]bool, len(ignored))
+	for _, p := range ignored {
+		skip[[2]int{p.NoteA, p.NoteB}] = true
+	}
+
+	byID := make(map[int]*note.NoteWithTags, len(notes))
+	docs := make([]dedupe.Document, 0, len(notes))
+	for _, n := range notes {
+		byID[n.ID] = n
+		docs = append(docs, dedupe.Document{ID: n.ID, Text: n.Title + "\n" + n.Content})
+	}
+
+	var pairs []dedupe.Pair
+	for _, p := range dedupe.FindPairs(docs, threshold) {
+		if !skip[[2]int{p.A, p.B}] {
+			pairs = append(pairs, p)
+		}
+	}
+	if len(pairs) == 0 {
+		fmt.Println("No duplicate notes found.")
+		return nil
+	}
+	fmt.Printf("Found %d candidate pair(s) with at least %.0f%% overlap.\n\n", len(pairs), threshold*100)
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	merged, ignoredNow, dismissed := 0, 0, 0
+	for i, p := range pairs {
+		a, b := byID[p.A], byID[p.B]
+		if a == nil || b == nil {
+			// One of the notes was merged away earlier in this run.
+			continue
+		}
+
+		fmt.Printf("[%d/%d] %.0f%% similar\n", i+1, len(pairs), p.Similarity*100)
+		printSideBySide(a, b)
+
+		if useAI {
+			if h.groqClient.ExcludesNote(a.Tags) || h.groqClient.ExcludesNote(b.Tags) {
+				fmt.Println("AI: skipped, one of the notes has an excluded tag.")
+			} else {
+				verdict, err := h.groqClient.CompareNotes(ctx,
+					ai.ContextDocument{Title: a.Title, Content: a.Content},
+					ai.ContextDocument{Title: b.Title, Content: b.Content})
+				if err != nil {
+					if ctx.Err() != nil {
+						fmt.Println("Cancelled.")
+						break
+					}
+					return wrapAIError("failed to compare notes", err)
+				}
+				if !verdict.Duplicate {
+					fmt.Printf("AI: not duplicates. %s\n\n", verdict.Reason)
+					dismissed++
+					continue
+				}
+				fmt.Printf("AI: duplicates. %s\n", verdict.Reason)
+			}
+		}
+
+		if listOnly {
+			fmt.Println()
+			continue
+		}
+
+		choice := strings.ToLower(readLine(fmt.Sprintf("Merge? [1] keep #%d / [2] keep #%d / [i]gnore / [s]kip / [q]uit: ", a.ID, b.ID)))
+		switch choice {
+		case "1", "2":
+			keep, drop := a, b
+			if choice == "2" {
+				keep, drop = b, a
+			}
+			content := mergedContent(keep, drop)
+			if err := h.noteRepo.Merge(keep.ID, drop.ID, content); err != nil {
+				return fmt.Errorf("failed to merge notes: %w", err)
+			}
+			keep.Content = content
+			keep.Tags = mergedTags(keep.Tags, drop.Tags)
+			delete(byID, drop.ID)
+			merged++
+			fmt.Printf("✓ Merged #%d into #%d.\n\n", drop.ID, keep.ID)
+		case "i":
+			if err := h.dedupeRepo.Ignore(dedupe.NewIgnoredPair(a.ID, b.ID)); err != nil {
+				return fmt.Errorf("failed to ignore pair: %w", err)
+			}
+			ignoredNow++
+			fmt.Println("Ignored; this pair will not be shown again.")
+			fmt.Println()
+		case "q":
+			fmt.Println()
+			printDedupeSummary(merged, ignoredNow, dismissed)
+			return nil
+		default:
+			fmt.Println()
+		}
+	}
+
+	if listOnly {
+		if useAI && dismissed > 0 {
+			fmt.Printf("%d pair(s) were not duplicates according to the AI.\n", dismissed)
+		}
+		return nil
+	}
+	printDedupeSummary(merged, ignoredNow, dismissed)
+	return nil
+}
+
+func printDedupeSummary(merged, ignored, dismissed int) {
+	fmt.Printf("Merged %d pair(s), ignored %d.", merged, ignored)
+	if dismissed > 0 {
+		fmt.Printf(" %d pair(s) were not duplicates according to the AI.", dismissed)
+	}
+	fmt.Println()
+}
+
+// mergedContent keeps the content of keep and appends drop's under its
+// title, unless one already contains the other.
+func mergedContent(keep, drop *note.NoteWithTags) string {
+	kept, dropped := strings.TrimSpace(keep.Content), strings.TrimSpace(drop.Content)
+	switch {
+	case dropped == "" || strings.Contains(kept, dropped):
+		return keep.Content
+	case kept == "" || strings.Contains(dropped, kept):
+		return drop.Content
+	}
+	return fmt.Sprintf("%s\n\n---\n\n## %s\n\n%s\n", kept, drop.Title, dropped)
+}
+
+func mergedTags(keep, drop []string) []string {
+	tags := append([]string{}, keep...)
+	for _, t := range drop {
+		if !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// printSideBySide shows two notes in columns: ID and title, tags, last
+// update and the start of the content.
+func printSideBySide(a, b *note.NoteWithTags) {
+	left := dedupeColumn(a)
+	right := dedupeColumn(b)
+	for len(left) < len(right) {
+		left = append(left, "")
+	}
+	for len(right) < len(left) {
+		right = append(right, "")
+	}
+
+	separator := strings.Repeat("─", dedupeColumnWidth)
+	fmt.Printf("%s─┬─%s\n", separator, separator)
+	for i := range left {
+		fmt.Println(strings.TrimRight(padRight(left[i], dedupeColumnWidth)+" │ "+right[i], " "))
+	}
+	fmt.Printf("%s─┴─%s\n", separator, separator)
+}
+
+func dedupeColumn(n *note.NoteWithTags) []string {
+	lines := wrapColumn(fmt.Sprintf("#%d %s", n.ID, n.Title))
+	tags := "(no tags)"
+	if len(n.Tags) > 0 {
+		tags = "[" + strings.Join(n.Tags, ", ") + "]"
+	}
+	lines = append(lines, wrapColumn(tags)...)
+	lines = append(lines, n.UpdatedAt.Format("2006-01-02 15:04"), "")
+
+	content := wrapColumn(strings.TrimSpace(n.Content))
+	if len(content) > dedupeRows {
+		content = append(content[:dedupeRows-1], "...")
+	}
+	return append(lines, content...)
+}
+
+// wrapColumn wraps text to the column width, cutting words that are longer
+// than a whole line.
+func wrapColumn(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(wordwrap.WrapString(text, dedupeColumnWidth), "\n") {
+		for utf8.RuneCountInString(line) > dedupeColumnWidth {
+			runes := []rune(line)
+			lines = append(lines, string(runes[:dedupeColumnWidth]))
+			line = string(runes[dedupeColumnWidth:])
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
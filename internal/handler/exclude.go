@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/repository"
+)
+
+// errExcludedNote is returned when a command targets a note whose tags keep
+// it out of AI requests (the "exclude_tags" setting).
+func errExcludedNote(id int) error {
+	return fmt.Errorf("note #%d has an excluded tag and is never sent to the AI (see exclude_tags in ~/.snip/config.json)", id)
+}
+
+// noteExcluder is satisfied by *ai.Config and *ai.GroqClient; both are safe
+// to use when nil.
+type noteExcluder interface {
+	ExcludesNote(tags []string) bool
+}
+
+// allowedNotes drops the notes whose tags keep them out of AI requests.
+func allowedNotes(client noteExcluder, notes []*note.NoteWithTags) []*note.NoteWithTags {
+	allowed := make([]*note.NoteWithTags, 0, len(notes))
+	for _, n := range notes {
+		if !client.ExcludesNote(n.Tags) {
+			allowed = append(allowed, n)
+		}
+	}
+	return allowed
+}
+
+// contextDocuments converts notes for ai.PackContext.
+func contextDocuments(notes []*note.NoteWithTags) []ai.ContextDocument {
+	docs := make([]ai.ContextDocument, 0, len(notes))
+	for _, n := range notes {
+		docs = append(docs, ai.ContextDocument{Title: n.Title, Content: n.Content})
+	}
+	return docs
+}
+
+// noteExcluded looks up the tags of a note returned without them, such as a
+// search result.
+func noteExcluded(client noteExcluder, noteRepo repository.NoteRepository, id int) (bool, error) {
+	tags, err := noteRepo.GetTagsByNote(id)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch tags for note #%d: %w", id, err)
+	}
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return client.ExcludesNote(names), nil
+}
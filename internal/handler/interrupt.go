@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+// interruptContext returns a context cancelled by Ctrl+C, so long AI
+// generations can be stopped without killing the process mid-write.
+func interruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+func printToken(token string) {
+	fmt.Print(token)
+}
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,7 +12,9 @@ import (
 	"time"
 
 	"github.com/snip/internal/ai"
+	"github.com/snip/internal/apperr"
 	"github.com/snip/internal/note"
+	"github.com/snip/internal/output"
 	"github.com/snip/internal/repository"
 	"github.com/snip/internal/validation"
 
@@ -36,30 +40,43 @@ type Handler interface {
 	ExportNotes(since string, format string) error
 	BackupDatabase() error
 	ImportNotes(importDir string) error
-	CreateNoteWithAI(topic string, context string, tag *string) error
+	CreateNoteWithAI(topic string, context string, tag *string, stream bool) error
 	ImproveSearchWithAI(query string) error
 	AskAI(question string) error
-	GenerateCodeWithAI(language string, description string, context string) error
+	GenerateCodeWithAI(language string, description string, context string, stream bool, save bool, tag *string, outPath string) error
+	SemanticFindNotes(query string, limit int) error
+	AITagNotes(idStr string, allUntagged bool, yes bool) error
+	AIEditNote(idStr string, instruction string, yes bool) error
 }
 
 type handler struct {
 	noteRepo      repository.NoteRepository
 	tagRepo       repository.TagRepository
+	embeddingRepo repository.EmbeddingRepository
 	validator     *validation.Validator
 	editorHandler *EditorHandler
 	dateFormat    string
 	groqClient    *ai.GroqClient
+	groqErr       error
+	embedder      ai.EmbeddingProvider
+	autoTag       bool
 }
 
-func NewHandler(noteRepo repository.NoteRepository, tagRepo repository.TagRepository) Handler {
-	groqClient, _ := ai.NewGroqClient()
+func NewHandler(noteRepo repository.NoteRepository, tagRepo repository.TagRepository, embeddingRepo repository.EmbeddingRepository) Handler {
+	groqClient, groqErr := ai.NewGroqClient()
+	cfg, err := ai.LoadConfig()
+	autoTag := err == nil && cfg.AutoTagOnCreate
 	return &handler{
 		noteRepo:      noteRepo,
 		tagRepo:       tagRepo,
+		embeddingRepo: embeddingRepo,
 		validator:     validation.NewValidator(),
 		dateFormat:    "2006-01-02 15:04:05",
 		editorHandler: NewEditorHandler(),
 		groqClient:    groqClient,
+		groqErr:       groqErr,
+		embedder:      ai.NewEmbeddingProvider(),
+		autoTag:       autoTag,
 	}
 }
 
@@ -83,29 +100,58 @@ func (h *handler) CreateNote(title string, message *string, tag *string) error {
 			return fmt.Errorf("failed to associate tags with note: %w", err)
 		}
 	}
+	h.indexNote(newNote.ID, newNote.Title, newNote.Content)
 
 	fmt.Printf("Note created successfully!\n")
 	fmt.Printf("● #%d  %s\n", newNote.ID, newNote.Title)
 
+	if h.autoTag && (tag == nil || *tag == "") {
+		h.autoTagNote(newNote.ID)
+	}
+
 	return nil
 }
 
 func (h *handler) ListNotes(isAsc, verbose bool, tag *string) error {
+	notes, err := h.listNotes(isAsc, tag)
+	if err != nil {
+		return err
+	}
+
+	return output.Print(notes, h.notesTable(notes), func() error {
+		return h.printNotes(notes, verbose)
+	})
+}
+
+// listNotes returns every note, or only the notes with tag when it is set.
+func (h *handler) listNotes(isAsc bool, tag *string) ([]*note.NoteWithTags, error) {
 	tagID := 0
 
 	if tag != nil && *tag != "" {
 		tagObj, err := h.tagRepo.GetByName(*tag)
 		if err != nil {
-			return fmt.Errorf("no note found for this tag: %s", *tag)
+			return nil, fmt.Errorf("no note found for this tag: %s: %w", *tag, err)
 		}
 		tagID = tagObj.ID
 	}
 
 	notes, err := h.noteRepo.GetAll(isAsc, tagID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch notes: %w", err)
+		return nil, fmt.Errorf("failed to fetch notes: %w", err)
+	}
+	return notes, nil
+}
+
+// notesTable is the --output table form of a list of notes.
+func (h *handler) notesTable(notes []*note.NoteWithTags) *output.Table {
+	t := output.NewTable("ID", "TITLE", "TAGS", "CREATED", "UPDATED")
+	for _, n := range notes {
+		t.Add(n.ID, n.Title, strings.Join(n.Tags, ","), n.CreatedAt.Format(h.dateFormat), n.UpdatedAt.Format(h.dateFormat))
 	}
+	return t
+}
 
+func (h *handler) printNotes(notes []*note.NoteWithTags, verbose bool) error {
 	if len(notes) == 0 {
 		fmt.Println("No notes found.")
 		return nil
@@ -151,13 +197,22 @@ func (h *handler) ListNotes(isAsc, verbose bool, tag *string) error {
 func (h *handler) GetNote(idStr string, verbose bool, render bool) error {
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		return fmt.Errorf("invalid note ID: %s", idStr)
+		return apperr.Invalid("invalid note ID: %s", idStr)
 	}
 
 	note, err := h.noteRepo.GetByID(id)
 	if err != nil {
 		return fmt.Errorf("failed to fetch note -> %w", err)
 	}
+
+	table := output.NewTable("ID", "TITLE", "TAGS", "LANGUAGE", "CREATED", "UPDATED")
+	table.Add(note.ID, note.Title, strings.Join(note.Tags, ","), note.Language, note.CreatedAt.Format(h.dateFormat), note.UpdatedAt.Format(h.dateFormat))
+	return output.Print(note, table, func() error {
+		return h.printNote(note, verbose, render)
+	})
+}
+
+func (h *handler) printNote(note *note.NoteWithTags, verbose bool, render bool) error {
 	tags := strings.Join(note.Tags, ", ")
 
 	fmt.Printf("● #%d %s [%s]\n", note.ID, note.Title, tags)
@@ -179,6 +234,10 @@ func (h *handler) GetNote(idStr string, verbose bool, render bool) error {
 		}
 	}
 
+	if note.Language != "" {
+		fmt.Printf("  └─ Language: %s\n", note.Language)
+	}
+
 	if verbose {
 		fmt.Printf("  └─ Created: %s\n", note.CreatedAt.Format(h.dateFormat))
 		fmt.Printf("  └─ Updated: %s\n", note.UpdatedAt.Format(h.dateFormat))
@@ -193,6 +252,16 @@ func (h *handler) FindNotes(term string) error {
 		return fmt.Errorf("failed to search notes: %w", err)
 	}
 
+	table := output.NewTable("ID", "TITLE", "UPDATED")
+	for _, n := range notes {
+		table.Add(n.ID, n.Title, n.UpdatedAt.Format(h.dateFormat))
+	}
+	return output.Print(notes, table, func() error {
+		return printFoundNotes(notes, term)
+	})
+}
+
+func printFoundNotes(notes []*note.Note, term string) error {
 	if len(notes) == 0 {
 		fmt.Println("No notes found.")
 		return nil
@@ -202,38 +271,42 @@ func (h *handler) FindNotes(term string) error {
 
 	for _, note := range notes {
 		fmt.Printf("● #%d %s\n", note.ID, note.Title)
+		printContentPreview(note.Content)
+		fmt.Println()
+	}
 
-		if note.Content != "" {
-			lines := strings.Split(strings.TrimRight(note.Content, "\n"), "\n")
-			if len(lines) > rowsLimit {
-				lines = lines[:rowsLimit]
-				lines[rowsLimit - 1] = "..."
-			}
-
-			fmt.Printf("  └── ")
+	return nil
+}
 
-			for i, line := range lines {
-				if len(line) > lineLimit {
-					line = line[:lineLimit] + "..."
-				}
-				if i != 0 {
-					fmt.Printf("      %s\n", line)
-				} else if i == 0 {
-					fmt.Printf("%s\n", line)
-				}
-			}
-		}
+func printContentPreview(content string) {
+	if content == "" {
+		return
+	}
 
-		fmt.Println()
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if len(lines) > rowsLimit {
+		lines = lines[:rowsLimit]
+		lines[rowsLimit - 1] = "..."
 	}
 
-	return nil
+	fmt.Printf("  └── ")
+
+	for i, line := range lines {
+		if len(line) > lineLimit {
+			line = line[:lineLimit] + "..."
+		}
+		if i != 0 {
+			fmt.Printf("      %s\n", line)
+		} else if i == 0 {
+			fmt.Printf("%s\n", line)
+		}
+	}
 }
 
 func (h *handler) PatchNote(idStr string, title *string, tag *string) error {
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		return fmt.Errorf("invalid note ID: %s", idStr)
+		return apperr.Invalid("invalid note ID: %s", idStr)
 	}
 
 	err = h.noteRepo.CheckByID(id)
@@ -256,13 +329,18 @@ func (h *handler) PatchNote(idStr string, title *string, tag *string) error {
 		}
 	}
 
+	// Index once the tags are final: a new excluded tag drops the vector.
+	if patched, err := h.noteRepo.GetByID(id); err == nil {
+		h.indexNote(patched.ID, patched.Title, patched.Content)
+	}
+
 	return nil
 }
 
 func (h *handler) UpdateNote(idStr string, title string) error {
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		return fmt.Errorf("invalid note ID: %d", id)
+		return apperr.Invalid("invalid note ID: %d", id)
 	}
 
 	note, err := h.noteRepo.GetByID(id)
@@ -286,6 +364,11 @@ func (h *handler) UpdateNote(idStr string, title string) error {
 		return fmt.Errorf("failed to update note: %w", err)
 	}
 
+	if title == "" {
+		title = note.Title
+	}
+	h.indexNote(id, title, contentStr)
+
 	fmt.Printf("Note updated successfully!\n")
 	return nil
 }
@@ -293,7 +376,7 @@ func (h *handler) UpdateNote(idStr string, title string) error {
 func (h *handler) DeleteNote(idStr string) error {
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		return fmt.Errorf("invalid note ID: %d", id)
+		return apperr.Invalid("invalid note ID: %d", id)
 	}
 
 	if err := h.noteRepo.CheckByID(id); err != nil {
@@ -349,6 +432,12 @@ func (h *handler) GetRecentNotes(limit int) error {
 		return fmt.Errorf("failed to get recent notes: %w", err)
 	}
 
+	return output.Print(notes, h.notesTable(notes), func() error {
+		return printRecentNotes(notes)
+	})
+}
+
+func printRecentNotes(notes []*note.NoteWithTags) error {
 	if len(notes) == 0 {
 		fmt.Println("No notes found.")
 		return nil
@@ -397,7 +486,7 @@ func (h *handler) ExportNotes(since string, format string) error {
 	if since != "" {
 		parsed, err := parseSinceFilter(since)
 		if err != nil {
-			return fmt.Errorf("invalid --since value: %w", err)
+			return apperr.Invalid("invalid --since value: %w", err)
 		}
 		sinceTime = &parsed
 	}
@@ -425,7 +514,7 @@ func (h *handler) BackupDatabase() error {
 	sourceDB := filepath.Join(snipDir, "notes.db")
 
 	if _, err := os.Stat(sourceDB); os.IsNotExist(err) {
-		return fmt.Errorf("database not found at %s", sourceDB)
+		return apperr.New(apperr.KindNotFound, fmt.Errorf("database not found at %s", sourceDB))
 	}
 
 	backupDir := filepath.Join(snipDir, "backups")
@@ -490,6 +579,7 @@ func (h *handler) ImportNotes(importDir string) error {
 		if err := h.noteRepo.Create(note); err != nil {
 			return fmt.Errorf("failed to create note: %w", err)
 		}
+		h.indexNote(note.ID, note.Title, note.Content)
 	}
 
 	return nil
@@ -533,7 +623,7 @@ func parseSinceFilter(since string) (time.Time, error) {
 	}
 
 	if len(since) < 2 {
-		return time.Time{}, fmt.Errorf("invalid format: %s (use '2025-01-01' or '30d')", since)
+		return time.Time{}, apperr.Invalid("invalid format: %s (use '2025-01-01' or '30d')", since)
 	}
 
 	unit := since[len(since)-1:]
@@ -541,7 +631,7 @@ func parseSinceFilter(since string) (time.Time, error) {
 
 	var value int
 	if _, err := fmt.Sscanf(valueStr, "%d", &value); err != nil {
-		return time.Time{}, fmt.Errorf("invalid number in duration: %s", since)
+		return time.Time{}, apperr.Invalid("invalid number in duration: %s", since)
 	}
 
 	var duration time.Duration
@@ -555,7 +645,7 @@ func parseSinceFilter(since string) (time.Time, error) {
 	case "y":
 		duration = time.Duration(value) * 365 * 24 * time.Hour
 	default:
-		return time.Time{}, fmt.Errorf("invalid duration unit: %s (use d, w, m, or y)", unit)
+		return time.Time{}, apperr.Invalid("invalid duration unit: %s (use d, w, m, or y)", unit)
 	}
 
 	return time.Now().Add(-duration), nil
@@ -566,15 +656,30 @@ func renderMarkdownContent(content string) string {
 	return string(markdown.Render(content, markdownWidth, markdownPad))
 }
 
-func (h *handler) CreateNoteWithAI(topic string, context string, tag *string) error {
+func (h *handler) CreateNoteWithAI(topic string, aiContext string, tag *string, stream bool) error {
 	if h.groqClient == nil {
-		return fmt.Errorf("AI client not available")
+		return noAIClient(h.groqErr)
 	}
 
 	fmt.Println("Generating content with AI...")
-	content, err := h.groqClient.GenerateNoteContent(topic, context)
+
+	var content string
+	var err error
+	if stream {
+		ctx, stop := interruptContext()
+		defer stop()
+
+		content, err = h.groqClient.GenerateNoteContentStream(ctx, topic, aiContext, printToken)
+		fmt.Println()
+		if errors.Is(err, context.Canceled) {
+			fmt.Println("Generation cancelled, nothing was saved.")
+			return nil
+		}
+	} else {
+		content, err = h.groqClient.GenerateNoteContent(topic, aiContext)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to generate content with AI: %w", err)
+		return wrapAIError("failed to generate content with AI", err)
 	}
 
 	newNote := note.NewNote(topic, content)
@@ -587,6 +692,7 @@ func (h *handler) CreateNoteWithAI(topic string, context string, tag *string) er
 			return fmt.Errorf("failed to associate tags with note: %w", err)
 		}
 	}
+	h.indexNote(newNote.ID, newNote.Title, newNote.Content)
 
 	fmt.Printf("Note created successfully with AI!\n")
 	fmt.Printf("● #%d  %s\n", newNote.ID, newNote.Title)
@@ -595,7 +701,7 @@ func (h *handler) CreateNoteWithAI(topic string, context string, tag *string) er
 
 func (h *handler) ImproveSearchWithAI(query string) error {
 	if h.groqClient == nil {
-		return fmt.Errorf("AI client not available")
+		return noAIClient(h.groqErr)
 	}
 
 	// Get some recent notes for context
@@ -603,16 +709,12 @@ func (h *handler) ImproveSearchWithAI(query string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get notes for context: %w", err)
 	}
-
-	notesContext := make([]string, 0, len(notes))
-	for _, note := range notes {
-		notesContext = append(notesContext, fmt.Sprintf("%s: %s", note.Title, note.Content))
-	}
+	notesContext := contextDocuments(allowedNotes(h.groqClient, notes))
 
 	fmt.Println("Improving search query with AI...")
 	improvedQuery, err := h.groqClient.ImproveSearchQuery(query, notesContext)
 	if err != nil {
-		return fmt.Errorf("failed to improve search query: %w", err)
+		return wrapAIError("failed to improve search query", err)
 	}
 
 	// Clean the improved query - remove special characters that might break FTS
@@ -642,43 +744,131 @@ func (h *handler) ImproveSearchWithAI(query string) error {
 	return h.FindNotes(improvedQuery)
 }
 
+// askContextNotes is how many recent notes AskAI offers as context.
+const askContextNotes = 50
+
 func (h *handler) AskAI(question string) error {
 	if h.groqClient == nil {
-		return fmt.Errorf("AI client not available")
+		return noAIClient(h.groqErr)
 	}
 
-	// Get relevant notes for context
-	notes, err := h.noteRepo.GetRecent(10)
+	// Recent notes are candidates; the client keeps the most relevant ones
+	// that fit the model's context budget.
+	notes, err := h.noteRepo.GetRecent(askContextNotes)
 	if err != nil {
 		return fmt.Errorf("failed to get notes for context: %w", err)
 	}
-
-	notesContext := make([]string, 0, len(notes))
-	for _, note := range notes {
-		notesContext = append(notesContext, fmt.Sprintf("%s: %s", note.Title, note.Content))
-	}
+	notesContext := contextDocuments(allowedNotes(h.groqClient, notes))
 
 	fmt.Println("Asking AI...")
 	answer, err := h.groqClient.AnswerQuestion(question, notesContext)
 	if err != nil {
-		return fmt.Errorf("failed to get answer from AI: %w", err)
+		return wrapAIError("failed to get answer from AI", err)
 	}
 
 	fmt.Println("\n" + renderMarkdownContent(answer))
 	return nil
 }
 
-func (h *handler) GenerateCodeWithAI(language string, description string, context string) error {
+// GenerateCodeWithAI prints generated code and, on request, saves the answer
+// as a snippet note and writes its code blocks to outPath.
+func (h *handler) GenerateCodeWithAI(language string, description string, aiContext string, stream bool, save bool, tag *string, outPath string) error {
 	if h.groqClient == nil {
-		return fmt.Errorf("AI client not available")
+		return noAIClient(h.groqErr)
 	}
 
 	fmt.Printf("Generating %s code with AI...\n", language)
-	code, err := h.groqClient.GenerateCode(language, description, context)
+
+	var code string
+	var err error
+	if stream {
+		ctx, stop := interruptContext()
+		defer stop()
+
+		fmt.Println()
+		code, err = h.groqClient.GenerateCodeStream(ctx, language, description, aiContext, printToken)
+		fmt.Println()
+		if errors.Is(err, context.Canceled) {
+			fmt.Println("Generation cancelled.")
+			if save || outPath != "" {
+				fmt.Println("Nothing was saved.")
+			}
+			return nil
+		}
+	} else {
+		code, err = h.groqClient.GenerateCode(language, description, aiContext)
+		if err == nil {
+			fmt.Println("\n" + renderMarkdownContent(code))
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("failed to generate code with AI: %w", err)
+		return wrapAIError("failed to generate code with AI", err)
+	}
+
+	if outPath != "" {
+		if err := writeCodeBlocks(code, language, outPath); err != nil {
+			return err
+		}
+	}
+	if save {
+		return h.saveSnippet(language, description, code, tag)
+	}
+	return nil
+}
+
+// writeCodeBlocks writes the code blocks of an answer to path, asking before
+// replacing an existing file.
+func writeCodeBlocks(answer, language, path string) error {
+	blocks := ai.SelectCodeBlocks(ai.ExtractCodeBlocks(answer), language, path)
+	if len(blocks) == 0 {
+		return fmt.Errorf("no code block found in the answer, nothing written to %s", path)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if !confirm(fmt.Sprintf("%s already exists. Overwrite? [y/N] ", path)) {
+			fmt.Println("File not written.")
+			return nil
+		}
 	}
 
-	fmt.Println("\n" + renderMarkdownContent(code))
+	parts := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		parts = append(parts, strings.TrimRight(b.Code, "\n"))
+	}
+	content := strings.Join(parts, "\n\n") + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	fmt.Printf("✓ Wrote %d code block(s) to %s\n", len(blocks), path)
 	return nil
-}
\ No newline at end of file
+}
+
+// saveSnippet stores a generated answer as a note with its language, tagged
+// "snippet" and the language plus any tags given.
+func (h *handler) saveSnippet(language, description, answer string, tag *string) error {
+	language = ai.NormalizeLanguage(language)
+
+	newNote := note.NewNote(description, answer)
+	newNote.Language = language
+	if err := h.noteRepo.Create(newNote); err != nil {
+		return fmt.Errorf("failed to create note: %w", err)
+	}
+	h.indexNote(newNote.ID, newNote.Title, newNote.Content)
+
+	tags := []string{"snippet"}
+	if t := ai.NormalizeTag(language); t != "" {
+		tags = append(tags, t)
+	}
+	if tag != nil && *tag != "" {
+		tags = append(tags, *tag)
+	}
+	joined := strings.Join(tags, " ")
+	if err := h.AssociateTagsWithNote(&joined, newNote.ID); err != nil {
+		return fmt.Errorf("failed to associate tags with note: %w", err)
+	}
+
+	fmt.Printf("Note created successfully!\n")
+	fmt.Printf("● #%d  %s [%s]\n", newNote.ID, newNote.Title, language)
+	return nil
+}
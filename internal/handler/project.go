@@ -3,10 +3,14 @@ package handler
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/snip/internal/ai"
+	"github.com/snip/internal/checklist"
+	"github.com/snip/internal/output"
 	"github.com/snip/internal/project"
 	"github.com/snip/internal/repository"
+	"github.com/snip/internal/task"
 )
 
 type ProjectHandler interface {
@@ -15,21 +19,27 @@ type ProjectHandler interface {
 	ShowProject(id int) error
 	UpdateProject(id int, name, description, status string) error
 	DeleteProject(id int) error
-	CreateProjectWithAI(name, description string) error
+	CreateProjectWithAI(name, description string, yes bool) error
 }
 
 type projectHandler struct {
-	projectRepo repository.ProjectRepository
-	taskRepo    repository.TaskRepository
-	groqClient  *ai.GroqClient
+	projectRepo       repository.ProjectRepository
+	taskRepo          repository.TaskRepository
+	checklistRepo     repository.ChecklistRepository
+	checklistItemRepo repository.ChecklistItemRepository
+	groqClient        *ai.GroqClient
+	groqErr           error
 }
 
-func NewProjectHandler(projectRepo repository.ProjectRepository, taskRepo repository.TaskRepository) ProjectHandler {
-	groqClient, _ := ai.NewGroqClient()
+func NewProjectHandler(projectRepo repository.ProjectRepository, taskRepo repository.TaskRepository, checklistRepo repository.ChecklistRepository, checklistItemRepo repository.ChecklistItemRepository) ProjectHandler {
+	groqClient, groqErr := ai.NewGroqClient()
 	return &projectHandler{
-		projectRepo: projectRepo,
-		taskRepo:    taskRepo,
-		groqClient:  groqClient,
+		projectRepo:       projectRepo,
+		taskRepo:          taskRepo,
+		checklistRepo:     checklistRepo,
+		checklistItemRepo: checklistItemRepo,
+		groqClient:        groqClient,
+		groqErr:           groqErr,
 	}
 }
 
@@ -50,6 +60,16 @@ func (h *projectHandler) ListProjects(status string) error {
 		return fmt.Errorf("failed to fetch projects: %w", err)
 	}
 
+	table := output.NewTable("ID", "NAME", "STATUS", "CREATED", "DESCRIPTION")
+	for _, p := range projects {
+		table.Add(p.ID, p.Name, p.Status, p.CreatedAt.Format("2006-01-02"), p.Description)
+	}
+	return output.Print(projects, table, func() error {
+		return printProjects(projects)
+	})
+}
+
+func printProjects(projects []*project.Project) error {
 	if len(projects) == 0 {
 		fmt.Println("Nenhum projeto encontrado.")
 		return nil
@@ -77,14 +97,34 @@ func (h *projectHandler) ShowProject(id int) error {
 		return fmt.Errorf("failed to fetch project: %w", err)
 	}
 
+	tasks, err := h.taskRepo.GetByProjectID(id, "")
+	if err != nil {
+		return fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+
+	detail := projectDetail{Project: p, Tasks: tasks}
+	if detail.Tasks == nil {
+		detail.Tasks = []*task.Task{}
+	}
+	return output.Print(detail, tasksTable(tasks), func() error {
+		return printProject(p, tasks)
+	})
+}
+
+// projectDetail is a project with its tasks, as printed by --output.
+type projectDetail struct {
+	*project.Project
+	Tasks []*task.Task `json:"tasks"`
+}
+
+func printProject(p *project.Project, tasks []*task.Task) error {
 	fmt.Printf("● #%d %s [%s]\n", p.ID, p.Name, p.Status)
 	if p.Description != "" {
 		fmt.Printf("   └── %s\n\n", p.Description)
 	}
 
 	// Show tasks
-	tasks, err := h.taskRepo.GetByProjectID(id, "")
-	if err == nil && len(tasks) > 0 {
+	if len(tasks) > 0 {
 		fmt.Printf("Tarefas (%d):\n", len(tasks))
 		for _, t := range tasks {
 			statusIcon := "○"
@@ -118,15 +158,34 @@ func (h *projectHandler) DeleteProject(id int) error {
 	return nil
 }
 
-func (h *projectHandler) CreateProjectWithAI(name, description string) error {
+func (h *projectHandler) CreateProjectWithAI(name, description string, yes bool) error {
 	if h.groqClient == nil {
-		return fmt.Errorf("AI client not available")
+		return noAIClient(h.groqErr)
 	}
 
+	ctx, stop := interruptContext()
+	defer stop()
+
 	fmt.Println("Gerando plano de projeto com IA...")
-	plan, err := h.groqClient.GenerateProjectPlan(name, description)
+	plan, err := h.groqClient.GenerateProjectPlan(ctx, name, description)
 	if err != nil {
-		return fmt.Errorf("failed to generate project plan: %w", err)
+		if ctx.Err() != nil {
+			fmt.Println("Geração cancelada, nada foi salvo.")
+			return nil
+		}
+		return wrapAIError("failed to generate project plan", err)
+	}
+
+	now := time.Now()
+	printProjectPlan(name, plan, now)
+
+	if !yes && !confirm("Criar o projeto com este plano? [s/N] ") {
+		fmt.Println("Cancelado, nada foi salvo.")
+		return nil
+	}
+
+	if description == "" {
+		description = plan.Summary
 	}
 
 	p := project.NewProject(name, description)
@@ -134,12 +193,108 @@ func (h *projectHandler) CreateProjectWithAI(name, description string) error {
 		return fmt.Errorf("failed to create project: %w", err)
 	}
 
-	fmt.Printf("Projeto criado com sucesso!\n")
-	fmt.Printf("● #%d  %s\n\n", p.ID, p.Name)
-	fmt.Println("Plano gerado pela IA:")
-	fmt.Println(strings.Repeat("─", 60))
-	fmt.Println(plan)
+	tasksCreated, itemsCreated, err := h.applyProjectPlan(p.ID, plan, now)
+	if err != nil {
+		h.projectRepo.Delete(p.ID)
+		return fmt.Errorf("failed to save project plan: %w", err)
+	}
 
+	fmt.Printf("Projeto criado com sucesso!\n")
+	fmt.Printf("● #%d  %s\n", p.ID, p.Name)
+	fmt.Printf("  %d tarefa(s) e %d item(ns) de checklist criados.\n", tasksCreated, itemsCreated)
 	return nil
 }
 
+// applyProjectPlan creates one task per plan task and, when the task has a
+// checklist, a checklist linked to both the task and the project. The
+// repositories do not share a transaction, so on failure everything created
+// so far is deleted again.
+func (h *projectHandler) applyProjectPlan(projectID int, plan *ai.ProjectPlan, now time.Time) (tasksCreated int, itemsCreated int, err error) {
+	var undo []func()
+	defer func() {
+		if err != nil {
+			for i := len(undo) - 1; i >= 0; i-- {
+				undo[i]()
+			}
+		}
+	}()
+
+	for _, phase := range plan.Phases {
+		for _, pt := range phase.Tasks {
+			description := "Fase: " + phase.Name
+			if pt.Description != "" {
+				description += "\n\n" + pt.Description
+			}
+
+			t := task.NewTask(projectID, pt.Title, description, pt.Priority)
+			t.DueDate = planDueDate(pt, now)
+			if err := h.taskRepo.Create(t); err != nil {
+				return tasksCreated, itemsCreated, fmt.Errorf("failed to create task %q: %w", pt.Title, err)
+			}
+			taskID := t.ID
+			undo = append(undo, func() { h.taskRepo.Delete(taskID) })
+			tasksCreated++
+
+			if len(pt.Checklist) == 0 {
+				continue
+			}
+
+			c := checklist.NewChecklist(pt.Title, "")
+			c.TaskID = &t.ID
+			c.ProjectID = &projectID
+			if err := h.checklistRepo.Create(c); err != nil {
+				return tasksCreated, itemsCreated, fmt.Errorf("failed to create checklist for %q: %w", pt.Title, err)
+			}
+			checklistID := c.ID
+			undo = append(undo, func() { h.checklistRepo.Delete(checklistID) })
+
+			for i, title := range pt.Checklist {
+				item := checklist.NewChecklistItem(c.ID, title, "", i+1)
+				if err := h.checklistItemRepo.Create(item); err != nil {
+					return tasksCreated, itemsCreated, fmt.Errorf("failed to create checklist item: %w", err)
+				}
+				itemID := item.ID
+				undo = append(undo, func() { h.checklistItemRepo.Delete(itemID) })
+				itemsCreated++
+			}
+		}
+	}
+
+	return tasksCreated, itemsCreated, nil
+}
+
+func planDueDate(pt ai.PlanTask, now time.Time) *time.Time {
+	if pt.DueInDays == nil {
+		return nil
+	}
+	due := now.AddDate(0, 0, *pt.DueInDays)
+	return &due
+}
+
+func printProjectPlan(name string, plan *ai.ProjectPlan, now time.Time) {
+	fmt.Printf("\nPlano proposto para \"%s\":\n", name)
+	if plan.Summary != "" {
+		fmt.Printf("%s\n", plan.Summary)
+	}
+	fmt.Println(strings.Repeat("─", 60))
+
+	totalTasks, totalItems := 0, 0
+	for i, phase := range plan.Phases {
+		fmt.Printf("\nFase %d: %s\n", i+1, phase.Name)
+		for _, pt := range phase.Tasks {
+			due := ""
+			if d := planDueDate(pt, now); d != nil {
+				due = fmt.Sprintf(" (prazo: %s)", d.Format("2006-01-02"))
+			}
+			fmt.Printf("  ○ %s [%s]%s\n", pt.Title, pt.Priority, due)
+			for _, item := range pt.Checklist {
+				fmt.Printf("      ☐ %s\n", item)
+			}
+			totalTasks++
+			totalItems += len(pt.Checklist)
+		}
+	}
+
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("Total: %d tarefa(s), %d item(ns) de checklist\n\n", totalTasks, totalItems)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// promptInput is where interactive answers are read from.
+var promptInput io.Reader = os.Stdin
+
+var promptReader *bufio.Reader
+
+// SetPromptInput makes interactive questions read their answers from r, as
+// when answers are piped in by tests.
+func SetPromptInput(r io.Reader) {
+	promptInput = r
+	promptReader = nil
+}
+
+// readLine prints question and returns the trimmed answer. EOF counts as an
+// empty answer so piped or closed stdin falls back to the default.
+func readLine(question string) string {
+	answer, _ := readInput(question)
+	return answer
+}
+
+// readInput is readLine that also reports whether input is still open, so
+// loops can stop on Ctrl+D.
+func readInput(question string) (string, bool) {
+	if promptReader == nil {
+		promptReader = bufio.NewReader(promptInput)
+	}
+
+	fmt.Print(question)
+	line, err := promptReader.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Println()
+		return "", false
+	}
+	return strings.TrimSpace(line), true
+}
+
+// confirm asks a yes/no question that defaults to no. The question carries
+// its own "[y/N]" or "[s/N]" suffix; both English and Portuguese answers are
+// accepted.
+func confirm(question string) bool {
+	switch strings.ToLower(readLine(question)) {
+	case "y", "yes", "s", "sim":
+		return true
+	}
+	return false
+}
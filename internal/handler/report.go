@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/snip/internal/activity"
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/repository"
+	"github.com/snip/internal/task"
+)
+
+const (
+	ReportStandup = "standup"
+	ReportWeekly  = "weekly"
+)
+
+type ReportHandler interface {
+	Report(kind string, projectID int, useAI bool, save bool) error
+}
+
+type reportHandler struct {
+	taskRepo     repository.TaskRepository
+	projectRepo  repository.ProjectRepository
+	activityRepo repository.ActivityRepository
+	noteRepo     repository.NoteRepository
+	tagRepo      repository.TagRepository
+	groqClient   *ai.GroqClient
+	groqErr      error
+}
+
+func NewReportHandler(taskRepo repository.TaskRepository, projectRepo repository.ProjectRepository, activityRepo repository.ActivityRepository, noteRepo repository.NoteRepository, tagRepo repository.TagRepository) ReportHandler {
+	groqClient, groqErr := ai.NewGroqClient()
+	return &reportHandler{
+		taskRepo:     taskRepo,
+		projectRepo:  projectRepo,
+		activityRepo: activityRepo,
+		noteRepo:     noteRepo,
+		tagRepo:      tagRepo,
+		groqClient:   groqClient,
+		groqErr:      groqErr,
+	}
+}
+
+// taskReport is what happened to tasks since a point in time.
+type taskReport struct {
+	kind      string
+	since     time.Time
+	completed []*activity.TaskActivity
+	started   []*activity.TaskActivity
+	overdue   []*task.Task
+}
+
+// Report prints a standup (activity since the last working day) or a weekly
+// report (last 7 days) as markdown, optionally rewritten as prose by AI and
+// saved as a note.
+func (h *reportHandler) Report(kind string, projectID int, useAI bool, save bool) error {
+	if kind != ReportStandup && kind != ReportWeekly {
+		return apperr.Invalid("invalid report kind: %s (use standup or weekly)", kind)
+	}
+	if useAI && h.groqClient == nil {
+		return noAIClient(h.groqErr)
+	}
+
+	projectNames, err := h.projectNames()
+	if err != nil {
+		return err
+	}
+	if _, ok := projectNames[projectID]; projectID > 0 && !ok {
+		return fmt.Errorf("failed to fetch project: %w", repository.ErrProjectNotFound)
+	}
+
+	now := time.Now()
+	r, err := h.build(kind, reportSince(kind, now), now, projectID)
+	if err != nil {
+		return err
+	}
+
+	title := reportTitle(kind, now, projectNames[projectID])
+	content := r.markdown(title, projectNames, projectID == 0, now)
+
+	if useAI {
+		if len(r.completed)+len(r.started)+len(r.overdue) == 0 {
+			fmt.Println("Nenhuma atividade no período, o relatório não foi enviado à IA.")
+		} else {
+			ctx, stop := interruptContext()
+			defer stop()
+
+			prose, err := h.groqClient.WriteReport(ctx, kind, content)
+			if err != nil {
+				if ctx.Err() != nil {
+					fmt.Println("Geração cancelada.")
+					return nil
+				}
+				return wrapAIError("failed to write report", err)
+			}
+			content = "# " + title + "\n\n" + prose
+		}
+	}
+
+	fmt.Println(content)
+
+	if !save {
+		return nil
+	}
+
+	n := note.NewNote(title, content)
+	if err := h.noteRepo.Create(n); err != nil {
+		return fmt.Errorf("failed to save report: %w", err)
+	}
+	tagObj, err := h.tagRepo.GetOrCreate(kind)
+	if err != nil {
+		return fmt.Errorf("failed to tag report: %w", err)
+	}
+	if err := h.noteRepo.AddTagToNote(n.ID, tagObj.ID); err != nil {
+		return fmt.Errorf("failed to tag report: %w", err)
+	}
+
+	fmt.Printf("\nRelatório salvo como nota!\n")
+	fmt.Printf("● #%d  %s\n", n.ID, n.Title)
+	return nil
+}
+
+func (h *reportHandler) build(kind string, since, now time.Time, projectID int) (*taskReport, error) {
+	entries, err := h.activityRepo.GetSince(since, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch task activity: %w", err)
+	}
+
+	// A task moved several times in the period is reported by where it
+	// ended up; one reopened afterwards is not reported at all.
+	latest := make(map[int]*activity.TaskActivity)
+	for _, e := range entries {
+		latest[e.TaskID] = e
+	}
+
+	r := &taskReport{kind: kind, since: since}
+	for _, e := range latest {
+		switch e.ToStatus {
+		case "completed":
+			r.completed = append(r.completed, e)
+		case "in_progress":
+			r.started = append(r.started, e)
+		}
+	}
+	sortActivity(r.completed)
+	sortActivity(r.started)
+
+	var tasks []*task.Task
+	if projectID > 0 {
+		tasks, err = h.taskRepo.GetByProjectID(projectID, "")
+	} else {
+		tasks, err = h.taskRepo.GetAll("")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+
+	today := startOfDay(now)
+	for _, t := range tasks {
+		if t.Status != "completed" && t.DueDate != nil && t.DueDate.Before(today) {
+			r.overdue = append(r.overdue, t)
+		}
+	}
+	sort.Slice(r.overdue, func(i, j int) bool {
+		return r.overdue[i].DueDate.Before(*r.overdue[j].DueDate)
+	})
+
+	return r, nil
+}
+
+func (r *taskReport) markdown(title string, projectNames map[int]string, showProject bool, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "_Atividade desde %s_\n", r.since.Format("2006-01-02 15:04"))
+
+	suffix := func(projectID int) string {
+		if !showProject || projectNames[projectID] == "" {
+			return ""
+		}
+		return " — " + projectNames[projectID]
+	}
+
+	writeSection := func(heading string, count int, write func()) {
+		fmt.Fprintf(&b, "\n## %s (%d)\n\n", heading, count)
+		if count == 0 {
+			b.WriteString("- Nenhuma\n")
+			return
+		}
+		write()
+	}
+
+	writeSection("Concluídas", len(r.completed), func() {
+		for _, e := range r.completed {
+			fmt.Fprintf(&b, "- #%d %s%s\n", e.TaskID, e.TaskTitle, suffix(e.ProjectID))
+		}
+	})
+	writeSection("Iniciadas", len(r.started), func() {
+		for _, e := range r.started {
+			fmt.Fprintf(&b, "- #%d %s%s\n", e.TaskID, e.TaskTitle, suffix(e.ProjectID))
+		}
+	})
+	writeSection("Atrasadas", len(r.overdue), func() {
+		today := startOfDay(now)
+		for _, t := range r.overdue {
+			days := int(today.Sub(startOfDay(*t.DueDate)).Hours() / 24)
+			fmt.Fprintf(&b, "- #%d %s [%s]%s — prazo %s (%d dia(s) de atraso)\n", t.ID, t.Title, t.Priority, suffix(t.ProjectID), t.DueDate.Format("2006-01-02"), days)
+		}
+	})
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (h *reportHandler) projectNames() (map[int]string, error) {
+	projects, err := h.projectRepo.GetAll("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch projects: %w", err)
+	}
+
+	names := make(map[int]string, len(projects))
+	for _, p := range projects {
+		names[p.ID] = p.Name
+	}
+	return names, nil
+}
+
+// reportSince is the start of the period a report covers: the previous
+// working day for a standup (Friday on Mondays), the last 7 days for a
+// weekly report.
+func reportSince(kind string, now time.Time) time.Time {
+	today := startOfDay(now)
+	if kind == ReportWeekly {
+		return today.AddDate(0, 0, -6)
+	}
+	switch now.Weekday() {
+	case time.Monday:
+		return today.AddDate(0, 0, -3)
+	case time.Sunday:
+		return today.AddDate(0, 0, -2)
+	}
+	return today.AddDate(0, 0, -1)
+}
+
+func reportTitle(kind string, now time.Time, projectName string) string {
+	title := "Standup " + now.Format("2006-01-02")
+	if kind == ReportWeekly {
+		title = "Relatório semanal " + now.Format("2006-01-02")
+	}
+	if projectName != "" {
+		title += " — " + projectName
+	}
+	return title
+}
+
+func sortActivity(entries []*activity.TaskActivity) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
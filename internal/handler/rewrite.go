@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/diff"
+)
+
+const diffContextLines = 3
+
+const (
+	ansiReset = "\033[0m"
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+	ansiCyan  = "\033[36m"
+)
+
+// AIEditNote rewrites a note following instruction, shows the change as a
+// unified diff and saves it once accepted. The proposal can be adjusted in
+// the editor before saving.
+func (h *handler) AIEditNote(idStr string, instruction string, yes bool) error {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return apperr.Invalid("invalid note ID: %s", idStr)
+	}
+
+	instruction = strings.TrimSpace(instruction)
+	if instruction == "" {
+		return apperr.Invalid("instruction cannot be empty")
+	}
+
+	if h.groqClient == nil {
+		return noAIClient(h.groqErr)
+	}
+
+	n, err := h.noteRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch note: %w", err)
+	}
+	if h.groqClient.ExcludesNote(n.Tags) {
+		return errExcludedNote(n.ID)
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	fmt.Printf("Rewriting note #%d with AI...\n", n.ID)
+	proposed, err := h.groqClient.RewriteNote(ctx, n.Title, n.Content, instruction)
+	if err != nil {
+		if ctx.Err() != nil {
+			fmt.Println("Rewrite cancelled, the note was not changed.")
+			return nil
+		}
+		return wrapAIError("failed to rewrite note", err)
+	}
+
+	for {
+		hunks := diff.Unified(n.Content, proposed, diffContextLines)
+		if len(hunks) == 0 {
+			fmt.Println("No changes proposed.")
+			return nil
+		}
+
+		fmt.Println()
+		printDiff(fmt.Sprintf("#%d %s", n.ID, n.Title), hunks)
+		fmt.Println()
+
+		if yes {
+			break
+		}
+
+		choice := strings.ToLower(readLine("Apply? [y]es / [n]o / [e]dit: "))
+		if len(choice) > 1 {
+			choice = choice[:1]
+		}
+
+		if choice == "e" {
+			edited, err := h.editProposal(proposed)
+			if err != nil {
+				return err
+			}
+			proposed = edited
+			continue
+		}
+		if choice != "y" && choice != "s" {
+			fmt.Println("Discarded, the note was not changed.")
+			return nil
+		}
+		break
+	}
+
+	if err := h.noteRepo.Update(n.ID, proposed, ""); err != nil {
+		return fmt.Errorf("failed to update note: %w", err)
+	}
+	h.indexNote(n.ID, n.Title, proposed)
+
+	fmt.Printf("Note updated successfully!\n")
+	return nil
+}
+
+// editProposal opens the proposed content in the editor and returns what
+// was saved there.
+func (h *handler) editProposal(content string) (string, error) {
+	tempFile, err := h.editorHandler.HandleEditor(content)
+	if err != nil {
+		return "", err
+	}
+	defer h.editorHandler.RemoveTempFile(tempFile)
+
+	return h.editorHandler.ReadTempFile(tempFile)
+}
+
+// printDiff prints hunks as a unified diff, colored when stdout is a
+// terminal and NO_COLOR is not set.
+func printDiff(name string, hunks []diff.Hunk) {
+	color := useColor()
+	paint := func(code, s string) string {
+		if !color {
+			return s
+		}
+		return code + s + ansiReset
+	}
+
+	fmt.Println(paint(ansiRed, "--- "+name))
+	fmt.Println(paint(ansiGreen, "+++ "+name+" (proposed)"))
+	for _, hunk := range hunks {
+		fmt.Println(paint(ansiCyan, hunk.Header()))
+		for _, l := range hunk.Lines {
+			switch l.Op {
+			case diff.Insert:
+				fmt.Println(paint(ansiGreen, "+"+l.Text))
+			case diff.Delete:
+				fmt.Println(paint(ansiRed, "-"+l.Text))
+			default:
+				fmt.Println(" " + l.Text)
+			}
+		}
+	}
+
+	added, removed := diff.Stats(hunks)
+	fmt.Printf("%d line(s) added, %d line(s) removed\n", added, removed)
+}
+
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/embedding"
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/output"
+)
+
+const (
+	embeddingBatchSize = 32
+	semanticMinScore   = 0.15
+	// rrfK dampens how much the top ranks of either list dominate the merge.
+	rrfK = 60
+)
+
+func noteContentHash(title, content string) string {
+	sum := sha256.Sum256([]byte(title + "\n" + content))
+	return hex.EncodeToString(sum[:])
+}
+
+func embeddingText(title, content string) string {
+	return title + "\n" + content
+}
+
+// indexNote refreshes the note's embedding. Failures are reported but never
+// block the note operation itself; the next semantic search retries them.
+func (h *handler) indexNote(id int, title, content string) {
+	if h.embeddingRepo == nil || h.embedder == nil {
+		return
+	}
+
+	// A remote embedder never sees excluded notes, and a note that just got
+	// an excluded tag loses the vector it had.
+	if excluder, ok := h.embedder.(noteExcluder); ok {
+		excluded, err := noteExcluded(excluder, h.noteRepo, id)
+		if err != nil {
+			fmt.Printf("Warning: failed to index note #%d: %v\n", id, err)
+			return
+		}
+		if excluded {
+			if err := h.embeddingRepo.Delete(id); err != nil {
+				fmt.Printf("Warning: failed to drop the index of note #%d: %v\n", id, err)
+			}
+			return
+		}
+	}
+
+	vectors, err := h.embedder.Embed([]string{embeddingText(title, content)})
+	if err != nil {
+		fmt.Printf("Warning: failed to index note #%d: %v\n", id, err)
+		return
+	}
+
+	e := embedding.NewEmbedding(id, h.embedder.Name(), noteContentHash(title, content), vectors[0])
+	if err := h.embeddingRepo.Upsert(e); err != nil {
+		fmt.Printf("Warning: failed to index note #%d: %v\n", id, err)
+	}
+}
+
+// ensureIndex embeds every note whose stored vector is missing or stale and
+// returns the vectors keyed by note ID. Notes the embedder excludes are
+// skipped and have no vector.
+func (h *handler) ensureIndex(notes []*note.NoteWithTags) (map[int][]float32, error) {
+	if excluder, ok := h.embedder.(noteExcluder); ok {
+		notes = allowedNotes(excluder, notes)
+	}
+
+	stored, err := h.embeddingRepo.GetAll(h.embedder.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embeddings: %w", err)
+	}
+
+	existing := make(map[int]*embedding.Embedding, len(stored))
+	for _, e := range stored {
+		existing[e.NoteID] = e
+	}
+
+	vectors := make(map[int][]float32, len(notes))
+	var stale []*note.NoteWithTags
+	for _, n := range notes {
+		e, ok := existing[n.ID]
+		if ok && e.ContentHash == noteContentHash(n.Title, n.Content) {
+			vectors[n.ID] = e.Vector
+			continue
+		}
+		stale = append(stale, n)
+	}
+
+	// Progress goes to stderr so it never mixes with --output json or yaml.
+	if len(stale) > 0 {
+		fmt.Fprintf(os.Stderr, "Indexing %d note(s)...\n", len(stale))
+	}
+
+	for start := 0; start < len(stale); start += embeddingBatchSize {
+		end := min(start+embeddingBatchSize, len(stale))
+		batch := stale[start:end]
+
+		texts := make([]string, 0, len(batch))
+		for _, n := range batch {
+			texts = append(texts, embeddingText(n.Title, n.Content))
+		}
+
+		embedded, err := h.embedder.Embed(texts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed notes: %w", err)
+		}
+
+		for i, n := range batch {
+			e := embedding.NewEmbedding(n.ID, h.embedder.Name(), noteContentHash(n.Title, n.Content), embedded[i])
+			if err := h.embeddingRepo.Upsert(e); err != nil {
+				return nil, fmt.Errorf("failed to store embedding: %w", err)
+			}
+			vectors[n.ID] = embedded[i]
+		}
+	}
+
+	return vectors, nil
+}
+
+type semanticMatch struct {
+	note       *note.NoteWithTags
+	similarity float64
+	score      float64
+}
+
+func (h *handler) SemanticFindNotes(query string, limit int) error {
+	if h.embeddingRepo == nil || h.embedder == nil {
+		return fmt.Errorf("semantic search is not available")
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	notes, err := h.noteRepo.GetAll(false, 0)
+	if err != nil {
+		return fmt.Errorf("failed to fetch notes: %w", err)
+	}
+	// Notes kept from a remote embedder are left out of the results too, so
+	// the text search cannot bring them back.
+	if excluder, ok := h.embedder.(noteExcluder); ok {
+		notes = allowedNotes(excluder, notes)
+	}
+
+	vectors, err := h.ensureIndex(notes)
+	if err != nil {
+		return err
+	}
+
+	queryVectors, err := h.embedder.Embed([]string{query})
+	if err != nil {
+		return fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	matches := make(map[int]*semanticMatch)
+	byID := make(map[int]*note.NoteWithTags, len(notes))
+	for _, n := range notes {
+		byID[n.ID] = n
+	}
+
+	var semantic []*semanticMatch
+	for _, n := range notes {
+		similarity := ai.CosineSimilarity(queryVectors[0], vectors[n.ID])
+		if similarity < semanticMinScore {
+			continue
+		}
+		semantic = append(semantic, &semanticMatch{note: n, similarity: similarity})
+	}
+	sort.SliceStable(semantic, func(i, j int) bool {
+		return semantic[i].similarity > semantic[j].similarity
+	})
+	for rank, m := range semantic {
+		m.score += 1.0 / float64(rrfK+rank+1)
+		matches[m.note.ID] = m
+	}
+
+	if ftsQuery := buildFTSQuery(query); ftsQuery != "" {
+		ftsNotes, err := h.noteRepo.Search(ftsQuery)
+		if err != nil {
+			return fmt.Errorf("failed to search notes: %w", err)
+		}
+		for rank, n := range ftsNotes {
+			m, ok := matches[n.ID]
+			if !ok {
+				full, found := byID[n.ID]
+				if !found {
+					continue
+				}
+				m = &semanticMatch{note: full, similarity: ai.CosineSimilarity(queryVectors[0], vectors[n.ID])}
+				matches[n.ID] = m
+			}
+			m.score += 1.0 / float64(rrfK+rank+1)
+		}
+	}
+
+	results := make([]*semanticMatch, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, m)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score == results[j].score {
+			return results[i].similarity > results[j].similarity
+		}
+		return results[i].score > results[j].score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	found := make([]semanticResult, len(results))
+	table := output.NewTable("ID", "TITLE", "TAGS", "SIMILARITY")
+	for i, m := range results {
+		found[i] = semanticResult{NoteWithTags: m.note, Similarity: m.similarity}
+		table.Add(m.note.ID, m.note.Title, strings.Join(m.note.Tags, ","), fmt.Sprintf("%.0f%%", m.similarity*100))
+	}
+	return output.Print(found, table, func() error {
+		return printSemanticMatches(results, query)
+	})
+}
+
+// semanticResult is a semantic search match as printed by --output.
+type semanticResult struct {
+	*note.NoteWithTags
+	Similarity float64 `json:"similarity"`
+}
+
+func printSemanticMatches(results []*semanticMatch, query string) error {
+	if len(results) == 0 {
+		fmt.Println("No notes found.")
+		return nil
+	}
+
+	fmt.Printf("Found %d note(s) related to '%s':\n\n", len(results), query)
+
+	for _, m := range results {
+		fmt.Printf("● #%d %s (%.0f%% similar)\n", m.note.ID, m.note.Title, m.similarity*100)
+		printContentPreview(m.note.Content)
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// buildFTSQuery turns free text into an FTS4 OR query, dropping stop words and
+// characters that would break the MATCH syntax.
+func buildFTSQuery(query string) string {
+	tokens := ai.Tokenize(query)
+	if len(tokens) == 0 {
+		return ""
+	}
+	return strings.Join(tokens, " OR ")
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/link"
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/repository"
+)
+
+const summaryTag = "summary"
+
+type SummaryHandler interface {
+	SummarizeNote(idStr string, save bool) error
+	SummarizeTag(tagName string, save bool) error
+	SummarizeProject(projectID int, save bool) error
+}
+
+type summaryHandler struct {
+	noteRepo    repository.NoteRepository
+	tagRepo     repository.TagRepository
+	projectRepo repository.ProjectRepository
+	taskRepo    repository.TaskRepository
+	linkRepo    repository.LinkRepository
+	groqClient  *ai.GroqClient
+	groqErr     error
+}
+
+func NewSummaryHandler(noteRepo repository.NoteRepository, tagRepo repository.TagRepository, projectRepo repository.ProjectRepository, taskRepo repository.TaskRepository, linkRepo repository.LinkRepository) SummaryHandler {
+	groqClient, groqErr := ai.NewGroqClient()
+	return &summaryHandler{
+		noteRepo:    noteRepo,
+		tagRepo:     tagRepo,
+		projectRepo: projectRepo,
+		taskRepo:    taskRepo,
+		linkRepo:    linkRepo,
+		groqClient:  groqClient,
+		groqErr:     groqErr,
+	}
+}
+
+// summarySource is something a saved summary links back to.
+type summarySource struct {
+	targetType string
+	targetID   int
+	label      string
+}
+
+func (h *summaryHandler) SummarizeNote(idStr string, save bool) error {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return apperr.Invalid("invalid note ID: %s", idStr)
+	}
+
+	n, err := h.noteRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch note: %w", err)
+	}
+	if h.groqClient.ExcludesNote(n.Tags) {
+		return errExcludedNote(n.ID)
+	}
+
+	docs := []ai.SummaryDocument{{Title: n.Title, Content: n.Content}}
+	sources := []summarySource{noteSource(n)}
+	return h.summarize(n.Title, docs, sources, save)
+}
+
+func (h *summaryHandler) SummarizeTag(tagName string, save bool) error {
+	t, err := h.tagRepo.GetByName(tagName)
+	if err != nil {
+		if errors.Is(err, repository.ErrTagNotFound) {
+			return fmt.Errorf("no notes found for tag: %s", tagName)
+		}
+		return fmt.Errorf("failed to fetch tag: %w", err)
+	}
+
+	notes, err := h.noteRepo.GetAll(true, t.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch notes: %w", err)
+	}
+
+	docs := make([]ai.SummaryDocument, 0, len(notes))
+	sources := make([]summarySource, 0, len(notes))
+	for _, n := range allowedNotes(h.groqClient, notes) {
+		// Do not feed earlier summaries of the same tag back in.
+		if hasTag(n, summaryTag) {
+			continue
+		}
+		docs = append(docs, ai.SummaryDocument{Title: n.Title, Content: n.Content})
+		sources = append(sources, noteSource(n))
+	}
+
+	if len(docs) == 0 {
+		return fmt.Errorf("no notes found for tag: %s", tagName)
+	}
+
+	fmt.Printf("Summarizing %d note(s) tagged %s...\n", len(docs), tagName)
+	return h.summarize("Notes tagged "+tagName, docs, sources, save)
+}
+
+func (h *summaryHandler) SummarizeProject(projectID int, save bool) error {
+	p, err := h.projectRepo.GetByID(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch project: %w", err)
+	}
+
+	tasks, err := h.taskRepo.GetByProjectID(projectID, "")
+	if err != nil {
+		return fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Status: %s\n", p.Status)
+	if p.Description != "" {
+		fmt.Fprintf(&b, "Description: %s\n", p.Description)
+	}
+	fmt.Fprintf(&b, "\nTasks (%d):\n", len(tasks))
+	for _, t := range tasks {
+		fmt.Fprintf(&b, "- [%s] %s (priority: %s", t.Status, t.Title, t.Priority)
+		if t.DueDate != nil {
+			fmt.Fprintf(&b, ", due: %s", t.DueDate.Format("2006-01-02"))
+		}
+		b.WriteString(")\n")
+		if t.Description != "" {
+			fmt.Fprintf(&b, "  %s\n", strings.ReplaceAll(t.Description, "\n", "\n  "))
+		}
+	}
+
+	docs := []ai.SummaryDocument{{Title: "Project: " + p.Name, Content: b.String()}}
+	sources := []summarySource{{targetType: link.TargetProject, targetID: p.ID, label: fmt.Sprintf("project #%d %s", p.ID, p.Name)}}
+	return h.summarize(p.Name, docs, sources, save)
+}
+
+func (h *summaryHandler) summarize(subject string, docs []ai.SummaryDocument, sources []summarySource, save bool) error {
+	if h.groqClient == nil {
+		return noAIClient(h.groqErr)
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	summary, err := h.groqClient.Summarize(ctx, subject, docs, func(step string) {
+		fmt.Fprintf(os.Stderr, "%s...\n", step)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			fmt.Println("Summary cancelled, nothing was saved.")
+			return nil
+		}
+		return wrapAIError("failed to summarize", err)
+	}
+
+	fmt.Println("\n" + renderMarkdownContent(summary))
+
+	if !save {
+		return nil
+	}
+	return h.saveSummary(subject, summary, sources)
+}
+
+// saveSummary stores the summary as a new note tagged "summary", with a
+// sources footer and a note_links row for every source.
+func (h *summaryHandler) saveSummary(subject, summary string, sources []summarySource) error {
+	var b strings.Builder
+	b.WriteString(summary)
+	b.WriteString("\n\n---\nSources:\n")
+	for _, s := range sources {
+		fmt.Fprintf(&b, "- %s\n", s.label)
+	}
+
+	newNote := note.NewNote("Summary: "+subject, b.String())
+	if err := h.noteRepo.Create(newNote); err != nil {
+		return fmt.Errorf("failed to save summary: %w", err)
+	}
+
+	tagObj, err := h.tagRepo.GetOrCreate(summaryTag)
+	if err != nil {
+		return fmt.Errorf("failed to tag summary: %w", err)
+	}
+	if err := h.noteRepo.AddTagToNote(newNote.ID, tagObj.ID); err != nil {
+		return fmt.Errorf("failed to tag summary: %w", err)
+	}
+
+	for _, s := range sources {
+		if err := h.linkRepo.Create(link.NewLink(newNote.ID, s.targetType, s.targetID, link.RelationSource)); err != nil {
+			return fmt.Errorf("failed to link summary to its sources: %w", err)
+		}
+	}
+
+	fmt.Printf("Summary saved as a note!\n")
+	fmt.Printf("● #%d  %s\n", newNote.ID, newNote.Title)
+	return nil
+}
+
+func noteSource(n *note.NoteWithTags) summarySource {
+	return summarySource{
+		targetType: link.TargetNote,
+		targetID:   n.ID,
+		label:      fmt.Sprintf("#%d %s", n.ID, n.Title),
+	}
+}
+
+func hasTag(n *note.NoteWithTags, name string) bool {
+	for _, t := range n.Tags {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/note"
+)
+
+// AITagNotes suggests tags and a better title for one note, or for every
+// note that has no tags yet, and applies them after confirmation.
+func (h *handler) AITagNotes(idStr string, allUntagged bool, yes bool) error {
+	if h.groqClient == nil {
+		return noAIClient(h.groqErr)
+	}
+
+	var notes []*note.NoteWithTags
+	if allUntagged {
+		all, err := h.noteRepo.GetAll(true, 0)
+		if err != nil {
+			return fmt.Errorf("failed to fetch notes: %w", err)
+		}
+		for _, n := range all {
+			if len(n.Tags) == 0 {
+				notes = append(notes, n)
+			}
+		}
+		if len(notes) == 0 {
+			fmt.Println("All notes already have tags.")
+			return nil
+		}
+		fmt.Printf("Found %d untagged note(s).\n\n", len(notes))
+	} else {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			return apperr.Invalid("invalid note ID: %s", idStr)
+		}
+		n, err := h.noteRepo.GetByID(id)
+		if err != nil {
+			return fmt.Errorf("failed to fetch note: %w", err)
+		}
+		if h.groqClient.ExcludesNote(n.Tags) {
+			return errExcludedNote(n.ID)
+		}
+		notes = append(notes, n)
+	}
+
+	existing, err := h.existingTagNames()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	applied := 0
+	for i, n := range notes {
+		if len(notes) > 1 {
+			fmt.Printf("[%d/%d] ", i+1, len(notes))
+		}
+
+		choice, err := h.suggestAndApplyTags(ctx, n, &existing, yes)
+		if err != nil {
+			if ctx.Err() != nil {
+				fmt.Println("Cancelled.")
+				break
+			}
+			if !allUntagged {
+				return err
+			}
+			fmt.Printf("⚠️  Skipping #%d: %v\n\n", n.ID, err)
+			continue
+		}
+
+		if choice == "q" {
+			break
+		}
+		if choice != "n" {
+			applied++
+		}
+	}
+
+	if len(notes) > 1 {
+		fmt.Printf("Updated %d of %d note(s).\n", applied, len(notes))
+	}
+	return nil
+}
+
+// autoTagNote runs the tag suggestion for a freshly created note. Failures
+// only produce a warning; the note itself is already saved.
+func (h *handler) autoTagNote(id int) {
+	if h.groqClient == nil {
+		return
+	}
+
+	n, err := h.noteRepo.GetByID(id)
+	if err != nil {
+		return
+	}
+
+	existing, err := h.existingTagNames()
+	if err != nil {
+		fmt.Printf("⚠️  Auto-tagging skipped: %v\n", err)
+		return
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	fmt.Println()
+	if _, err := h.suggestAndApplyTags(ctx, n, &existing, false); err != nil && ctx.Err() == nil {
+		fmt.Printf("⚠️  Auto-tagging skipped: %v\n", err)
+	}
+}
+
+// suggestAndApplyTags asks for a suggestion, shows it and applies the part
+// the user accepts. It returns the choice: "y" (all), "t" (tags only), "n"
+// or "q". Tags created along the way are added to existing.
+func (h *handler) suggestAndApplyTags(ctx context.Context, n *note.NoteWithTags, existing *[]string, yes bool) (string, error) {
+	fmt.Printf("● #%d %s\n", n.ID, n.Title)
+
+	suggestion, err := h.groqClient.SuggestTags(ctx, n.Title, n.Content, *existing, ai.DefaultMaxTags)
+	if err != nil {
+		return "", wrapAIError("failed to suggest tags", err)
+	}
+
+	current := make(map[string]bool)
+	for _, t := range n.Tags {
+		current[t] = true
+	}
+	known := make(map[string]bool)
+	for _, t := range *existing {
+		known[t] = true
+	}
+
+	var newTags []string
+	for _, t := range suggestion.Tags {
+		if !current[t] {
+			newTags = append(newTags, t)
+		}
+	}
+
+	title := suggestion.Title
+	if title == n.Title || h.validator.ValidateNote(title) != nil {
+		title = ""
+	}
+
+	if len(newTags) == 0 && title == "" {
+		fmt.Println("   └── No changes suggested.")
+		fmt.Println()
+		return "n", nil
+	}
+
+	if title != "" {
+		fmt.Printf("   └── Title: %s → %s\n", n.Title, title)
+	}
+	if len(newTags) > 0 {
+		labels := make([]string, len(newTags))
+		for i, t := range newTags {
+			labels[i] = t
+			if !known[t] {
+				labels[i] += " (new)"
+			}
+		}
+		fmt.Printf("   └── Tags:  + %s\n", strings.Join(labels, ", "))
+	}
+
+	choice := "y"
+	if !yes {
+		prompt := "Apply? [y]es / [n]o / [q]uit: "
+		if title != "" && len(newTags) > 0 {
+			prompt = "Apply? [y]es / [t]ags only / [n]o / [q]uit: "
+		}
+		choice = strings.ToLower(readLine(prompt))
+		if len(choice) > 1 {
+			choice = choice[:1]
+		}
+		if choice == "s" {
+			choice = "y"
+		}
+	}
+
+	switch choice {
+	case "y", "t":
+	case "q":
+		fmt.Println()
+		return "q", nil
+	default:
+		fmt.Println()
+		return "n", nil
+	}
+
+	for _, t := range newTags {
+		tagObj, err := h.tagRepo.GetOrCreate(t)
+		if err != nil {
+			return "", fmt.Errorf("failed to create tag %s: %w", t, err)
+		}
+		if err := h.noteRepo.AddTagToNote(n.ID, tagObj.ID); err != nil {
+			return "", fmt.Errorf("failed to add tag to note: %w", err)
+		}
+		if !known[t] {
+			*existing = append(*existing, t)
+		}
+	}
+
+	if choice == "y" && title != "" {
+		if err := h.noteRepo.Patch(n.ID, title); err != nil {
+			return "", fmt.Errorf("failed to update note: %w", err)
+		}
+		h.indexNote(n.ID, title, n.Content)
+	}
+
+	fmt.Println("✓ Applied.")
+	fmt.Println()
+	return choice, nil
+}
+
+func (h *handler) existingTagNames() ([]string, error) {
+	tags, err := h.tagRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tags: %w", err)
+	}
+
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return names, nil
+}
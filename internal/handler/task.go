@@ -2,34 +2,50 @@ package handler
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/checklist"
+	"github.com/snip/internal/output"
 	"github.com/snip/internal/repository"
 	"github.com/snip/internal/task"
 )
 
 type TaskHandler interface {
-	CreateTask(projectID int, title, description, priority string, dueDate *time.Time) error
+	CreateTask(projectID int, title, description, priority string, dueDate *time.Time) (*task.Task, error)
 	ListTasks(projectID int, status string) error
 	ShowTask(id int) error
 	UpdateTask(id int, title, description, status, priority string, dueDate *time.Time) error
 	DeleteTask(id int) error
 	ToggleTaskComplete(id int) error
+	BreakdownTaskWithAI(id int, as string, maxSteps int, yes bool) error
 }
 
 type taskHandler struct {
-	taskRepo    repository.TaskRepository
-	projectRepo repository.ProjectRepository
+	taskRepo          repository.TaskRepository
+	projectRepo       repository.ProjectRepository
+	checklistRepo     repository.ChecklistRepository
+	checklistItemRepo repository.ChecklistItemRepository
+	groqClient        *ai.GroqClient
+	groqErr           error
 }
 
-func NewTaskHandler(taskRepo repository.TaskRepository, projectRepo repository.ProjectRepository) TaskHandler {
+func NewTaskHandler(taskRepo repository.TaskRepository, projectRepo repository.ProjectRepository, checklistRepo repository.ChecklistRepository, checklistItemRepo repository.ChecklistItemRepository) TaskHandler {
+	groqClient, groqErr := ai.NewGroqClient()
 	return &taskHandler{
-		taskRepo:    taskRepo,
-		projectRepo: projectRepo,
+		taskRepo:          taskRepo,
+		projectRepo:       projectRepo,
+		checklistRepo:     checklistRepo,
+		checklistItemRepo: checklistItemRepo,
+		groqClient:        groqClient,
+		groqErr:           groqErr,
 	}
 }
 
-func (h *taskHandler) CreateTask(projectID int, title, description, priority string, dueDate *time.Time) error {
+// CreateTask saves a new task and returns it with its ID.
+func (h *taskHandler) CreateTask(projectID int, title, description, priority string, dueDate *time.Time) (*task.Task, error) {
 	if priority == "" {
 		priority = "medium"
 	}
@@ -40,12 +56,12 @@ func (h *taskHandler) CreateTask(projectID int, title, description, priority str
 	}
 
 	if err := h.taskRepo.Create(t); err != nil {
-		return fmt.Errorf("failed to create task: %w", err)
+		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
 	fmt.Printf("Tarefa criada com sucesso!\n")
 	fmt.Printf("● #%d  %s [%s]\n", t.ID, t.Title, t.Priority)
-	return nil
+	return t, nil
 }
 
 func (h *taskHandler) ListTasks(projectID int, status string) error {
@@ -62,6 +78,25 @@ func (h *taskHandler) ListTasks(projectID int, status string) error {
 		return fmt.Errorf("failed to fetch tasks: %w", err)
 	}
 
+	return output.Print(tasks, tasksTable(tasks), func() error {
+		return printTasks(tasks)
+	})
+}
+
+// tasksTable is the --output table form of a list of tasks.
+func tasksTable(tasks []*task.Task) *output.Table {
+	table := output.NewTable("ID", "PROJECT", "PARENT", "STATUS", "PRIORITY", "DUE", "TITLE")
+	for _, t := range tasks {
+		due := ""
+		if t.DueDate != nil {
+			due = t.DueDate.Format("2006-01-02")
+		}
+		table.Add(t.ID, t.ProjectID, t.ParentID, t.Status, t.Priority, due, t.Title)
+	}
+	return table
+}
+
+func printTasks(tasks []*task.Task) error {
 	if len(tasks) == 0 {
 		fmt.Println("Nenhuma tarefa encontrada.")
 		return nil
@@ -101,6 +136,27 @@ func (h *taskHandler) ShowTask(id int) error {
 		return fmt.Errorf("failed to fetch task: %w", err)
 	}
 
+	children, err := h.taskRepo.GetChildren(id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch subtasks: %w", err)
+	}
+
+	detail := taskDetail{Task: t, Subtasks: children}
+	if detail.Subtasks == nil {
+		detail.Subtasks = []*task.Task{}
+	}
+	return output.Print(detail, tasksTable(append([]*task.Task{t}, children...)), func() error {
+		return printTask(t, children)
+	})
+}
+
+// taskDetail is a task with its subtasks, as printed by --output.
+type taskDetail struct {
+	*task.Task
+	Subtasks []*task.Task `json:"subtasks"`
+}
+
+func printTask(t *task.Task, children []*task.Task) error {
 	statusIcon := "○"
 	if t.Status == "completed" {
 		statusIcon = "✓"
@@ -115,10 +171,33 @@ func (h *taskHandler) ShowTask(id int) error {
 	if t.DueDate != nil {
 		fmt.Printf("   └── Prazo: %s\n", t.DueDate.Format("2006-01-02 15:04"))
 	}
+	if t.ParentID != nil {
+		fmt.Printf("   └── Subtarefa de #%d\n", *t.ParentID)
+	}
+	if t.SourceNoteID != nil {
+		fmt.Printf("   └── Extraída da nota #%d\n", *t.SourceNoteID)
+	}
+
+	if len(children) > 0 {
+		fmt.Printf("\nSubtarefas (%d):\n", len(children))
+		for _, c := range children {
+			fmt.Printf("  %s #%d %s [%s]\n", taskStatusIcon(c.Status), c.ID, c.Title, c.Priority)
+		}
+	}
 
 	return nil
 }
 
+func taskStatusIcon(status string) string {
+	switch status {
+	case "completed":
+		return "✓"
+	case "in_progress":
+		return "◐"
+	}
+	return "○"
+}
+
 func (h *taskHandler) UpdateTask(id int, title, description, status, priority string, dueDate *time.Time) error {
 	if err := h.taskRepo.Update(id, title, description, status, priority, dueDate); err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
@@ -156,3 +235,151 @@ func (h *taskHandler) ToggleTaskComplete(id int) error {
 	return nil
 }
 
+// BreakdownTaskWithAI splits a task into ordered steps and saves them either
+// as subtasks (as == "tasks") or as a checklist attached to the task.
+func (h *taskHandler) BreakdownTaskWithAI(id int, as string, maxSteps int, yes bool) error {
+	if as != "tasks" && as != "checklist" {
+		return apperr.Invalid("invalid value for --as: %s (use tasks or checklist)", as)
+	}
+
+	if h.groqClient == nil {
+		return noAIClient(h.groqErr)
+	}
+
+	t, err := h.taskRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch task: %w", err)
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	fmt.Printf("Dividindo a tarefa #%d com IA...\n", t.ID)
+	breakdown, err := h.groqClient.GenerateTaskBreakdown(ctx, t.Title, t.Description, h.taskProjectContext(t), maxSteps)
+	if err != nil {
+		if ctx.Err() != nil {
+			fmt.Println("Geração cancelada, nada foi salvo.")
+			return nil
+		}
+		return wrapAIError("failed to break down task", err)
+	}
+
+	fmt.Printf("\nPassos propostos para \"%s\":\n", t.Title)
+	for i, step := range breakdown.Steps {
+		fmt.Printf("  %d. %s [%s]\n", i+1, step.Title, step.Priority)
+		if step.Description != "" {
+			fmt.Printf("     └── %s\n", step.Description)
+		}
+	}
+	fmt.Println()
+
+	question := "Criar estes passos como subtarefas? [s/N] "
+	if as == "checklist" {
+		question = "Criar estes passos como checklist da tarefa? [s/N] "
+	}
+	if !yes && !confirm(question) {
+		fmt.Println("Cancelado, nada foi salvo.")
+		return nil
+	}
+
+	if as == "checklist" {
+		return h.saveBreakdownAsChecklist(t, breakdown)
+	}
+	return h.saveBreakdownAsTasks(t, breakdown)
+}
+
+func (h *taskHandler) saveBreakdownAsTasks(parent *task.Task, breakdown *ai.TaskBreakdown) (err error) {
+	var created []*task.Task
+	defer func() {
+		if err != nil {
+			for i := len(created) - 1; i >= 0; i-- {
+				h.taskRepo.Delete(created[i].ID)
+			}
+		}
+	}()
+
+	for _, step := range breakdown.Steps {
+		child := task.NewTask(parent.ProjectID, step.Title, step.Description, step.Priority)
+		child.ParentID = &parent.ID
+		if err := h.taskRepo.Create(child); err != nil {
+			return fmt.Errorf("failed to create subtask: %w", err)
+		}
+		created = append(created, child)
+	}
+
+	for _, child := range created {
+		fmt.Printf("● #%d  %s [%s]\n", child.ID, child.Title, child.Priority)
+	}
+
+	fmt.Printf("\n%d subtarefa(s) criada(s) em #%d.\n", len(breakdown.Steps), parent.ID)
+	return nil
+}
+
+func (h *taskHandler) saveBreakdownAsChecklist(parent *task.Task, breakdown *ai.TaskBreakdown) (err error) {
+	var undo []func()
+	defer func() {
+		if err != nil {
+			for i := len(undo) - 1; i >= 0; i-- {
+				undo[i]()
+			}
+		}
+	}()
+
+	c := checklist.NewChecklist("Passos: "+parent.Title, "Gerado por IA a partir da tarefa")
+	c.TaskID = &parent.ID
+	if parent.ProjectID > 0 {
+		c.ProjectID = &parent.ProjectID
+	}
+	if err := h.checklistRepo.Create(c); err != nil {
+		return fmt.Errorf("failed to create checklist: %w", err)
+	}
+	checklistID := c.ID
+	undo = append(undo, func() { h.checklistRepo.Delete(checklistID) })
+
+	for i, step := range breakdown.Steps {
+		description := step.Description
+		if step.Priority != "medium" {
+			description = strings.TrimSpace(fmt.Sprintf("[%s] %s", step.Priority, description))
+		}
+		item := checklist.NewChecklistItem(c.ID, step.Title, description, i+1)
+		if err := h.checklistItemRepo.Create(item); err != nil {
+			return fmt.Errorf("failed to add checklist item: %w", err)
+		}
+		itemID := item.ID
+		undo = append(undo, func() { h.checklistItemRepo.Delete(itemID) })
+	}
+
+	fmt.Printf("Checklist criada com sucesso!\n")
+	fmt.Printf("● #%d  %s (%d itens)\n", c.ID, c.Title, len(breakdown.Steps))
+	return nil
+}
+
+// taskProjectContext describes the task's project and its other tasks for
+// the breakdown prompt. It is nil when the project cannot be read.
+func (h *taskHandler) taskProjectContext(t *task.Task) *ai.BreakdownProject {
+	p, err := h.projectRepo.GetByID(t.ProjectID)
+	if err != nil {
+		return nil
+	}
+
+	project := &ai.BreakdownProject{Name: p.Name, Description: p.Description}
+
+	tasks, err := h.taskRepo.GetByProjectID(p.ID, "")
+	if err != nil {
+		return project
+	}
+
+	const maxContextTasks = 30
+	for _, other := range tasks {
+		if other.ID == t.ID {
+			continue
+		}
+		if len(project.OtherTasks) == maxContextTasks {
+			project.MoreTasks++
+			continue
+		}
+		project.OtherTasks = append(project.OtherTasks, fmt.Sprintf("%s [%s]", other.Title, other.Status))
+	}
+
+	return project
+}
@@ -0,0 +1,397 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/snip/internal/action"
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/repository"
+	"github.com/snip/internal/task"
+)
+
+const (
+	maxToolSearchResults = 5
+	maxToolListResults   = 50
+	maxToolSnippet       = 300
+)
+
+var errToolDeclined = errors.New("declined by the user")
+
+// ToolRunner exposes snip operations to the AI as function-calling tools.
+// Reads run directly; writes go through the task and checklist handlers and
+// only after the user confirms them. Every call is logged in ai_actions.
+type ToolRunner struct {
+	noteRepo          repository.NoteRepository
+	projectRepo       repository.ProjectRepository
+	taskRepo          repository.TaskRepository
+	checklistItemRepo repository.ChecklistItemRepository
+	actionRepo        repository.ActionRepository
+	taskHandler       TaskHandler
+	checklistHandler  ChecklistHandler
+	config            *ai.Config
+}
+
+func NewToolRunner(noteRepo repository.NoteRepository, projectRepo repository.ProjectRepository, taskRepo repository.TaskRepository, checklistItemRepo repository.ChecklistItemRepository, actionRepo repository.ActionRepository, taskHandler TaskHandler, checklistHandler ChecklistHandler) *ToolRunner {
+	// Only needed for exclude_tags; without a config nothing is excluded.
+	config, _ := ai.LoadConfig()
+	return &ToolRunner{
+		config:            config,
+		noteRepo:          noteRepo,
+		projectRepo:       projectRepo,
+		taskRepo:          taskRepo,
+		checklistItemRepo: checklistItemRepo,
+		actionRepo:        actionRepo,
+		taskHandler:       taskHandler,
+		checklistHandler:  checklistHandler,
+	}
+}
+
+// Tools returns the tool definitions sent with chat requests.
+func (r *ToolRunner) Tools() []ai.Tool {
+	return []ai.Tool{
+		ai.NewTool("search_notes", "Searches the user's notes by text and returns the id, title and a snippet of each.", `{
+			"type": "object",
+			"properties": {
+				"query": {"type": "string", "description": "Search terms"}
+			},
+			"required": ["query"]
+		}`),
+		ai.NewTool("list_projects", "Lists projects with their id, name and status.", `{
+			"type": "object",
+			"properties": {
+				"status": {"type": "string", "enum": ["active", "completed", "archived"]}
+			}
+		}`),
+		ai.NewTool("list_tasks", "Lists tasks, optionally of one project and with one status.", `{
+			"type": "object",
+			"properties": {
+				"project_id": {"type": "integer"},
+				"status": {"type": "string", "enum": ["pending", "in_progress", "completed"]}
+			}
+		}`),
+		ai.NewTool("create_task", "Creates a task in a project. Requires the user's confirmation.", `{
+			"type": "object",
+			"properties": {
+				"project_id": {"type": "integer"},
+				"title": {"type": "string"},
+				"description": {"type": "string"},
+				"priority": {"type": "string", "enum": ["low", "medium", "high"]},
+				"due_date": {"type": "string", "description": "Due date as YYYY-MM-DD"}
+			},
+			"required": ["project_id", "title"]
+		}`),
+		ai.NewTool("update_task_status", "Changes the status of a task. Requires the user's confirmation.", `{
+			"type": "object",
+			"properties": {
+				"task_id": {"type": "integer"},
+				"status": {"type": "string", "enum": ["pending", "in_progress", "completed"]}
+			},
+			"required": ["task_id", "status"]
+		}`),
+		ai.NewTool("toggle_checklist_item", "Checks or unchecks a checklist item. Requires the user's confirmation.", `{
+			"type": "object",
+			"properties": {
+				"item_id": {"type": "integer"}
+			},
+			"required": ["item_id"]
+		}`),
+	}
+}
+
+// Run executes one tool call, logs it and returns the result for the model
+// as JSON. Failures are reported to the model rather than returned, so it
+// can explain them or try something else.
+func (r *ToolRunner) Run(call ai.ToolCall, sessionID *int) string {
+	name := call.Function.Name
+	args := strings.TrimSpace(call.Function.Arguments)
+	if args == "" {
+		args = "{}"
+	}
+
+	fmt.Printf("[tool] %s %s\n", name, args)
+
+	a := action.NewAction(sessionID, name, args)
+	result, err := r.execute(name, []byte(args))
+
+	var payload any
+	switch {
+	case errors.Is(err, errToolDeclined):
+		a.Status = action.StatusDeclined
+		payload = map[string]string{"status": "declined", "message": "The user declined this action."}
+	case err != nil:
+		a.Status = action.StatusFailed
+		payload = map[string]string{"status": "error", "error": err.Error()}
+	default:
+		a.Status = action.StatusExecuted
+		payload = map[string]any{"status": "ok", "result": result}
+	}
+
+	out, _ := json.Marshal(payload)
+	a.Result = string(out)
+	if err := r.actionRepo.Create(a); err != nil {
+		fmt.Printf("Warning: failed to log AI action: %v\n", err)
+	}
+	return string(out)
+}
+
+func (r *ToolRunner) execute(name string, raw []byte) (any, error) {
+	switch name {
+	case "search_notes":
+		var args struct {
+			Query string `json:"query"`
+		}
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+		return r.searchNotes(args.Query)
+
+	case "list_projects":
+		var args struct {
+			Status string `json:"status"`
+		}
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+		return r.listProjects(args.Status)
+
+	case "list_tasks":
+		var args struct {
+			ProjectID int    `json:"project_id"`
+			Status    string `json:"status"`
+		}
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+		return r.listTasks(args.ProjectID, args.Status)
+
+	case "create_task":
+		var args struct {
+			ProjectID   int    `json:"project_id"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Priority    string `json:"priority"`
+			DueDate     string `json:"due_date"`
+		}
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+		return r.createTask(args.ProjectID, args.Title, args.Description, args.Priority, args.DueDate)
+
+	case "update_task_status":
+		var args struct {
+			TaskID int    `json:"task_id"`
+			Status string `json:"status"`
+		}
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+		return r.updateTaskStatus(args.TaskID, args.Status)
+
+	case "toggle_checklist_item":
+		var args struct {
+			ItemID int `json:"item_id"`
+		}
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+		return r.toggleChecklistItem(args.ItemID)
+	}
+
+	return nil, fmt.Errorf("unknown tool: %s", name)
+}
+
+func (r *ToolRunner) searchNotes(query string) (any, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+
+	notes, err := r.noteRepo.Search(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search notes: %w", err)
+	}
+
+	type noteResult struct {
+		ID      int    `json:"id"`
+		Title   string `json:"title"`
+		Snippet string `json:"snippet"`
+	}
+	results := []noteResult{}
+	for _, n := range notes {
+		if len(results) == maxToolSearchResults {
+			break
+		}
+		excluded, err := noteExcluded(r.config, r.noteRepo, n.ID)
+		if err != nil {
+			return nil, err
+		}
+		if excluded {
+			continue
+		}
+		results = append(results, noteResult{ID: n.ID, Title: n.Title, Snippet: toolSnippet(n.Content)})
+	}
+	return results, nil
+}
+
+// toolSnippet shortens note content to maxToolSnippet characters. The cut
+// falls between words when possible, so a secret is either kept whole, where
+// redaction still recognises it, or left out.
+func toolSnippet(content string) string {
+	runes := []rune(content)
+	if len(runes) <= maxToolSnippet {
+		return content
+	}
+	snippet := string(runes[:maxToolSnippet])
+	if i := strings.LastIndexAny(snippet, " \t\n"); i > 0 {
+		snippet = snippet[:i]
+	}
+	return snippet + "..."
+}
+
+func (r *ToolRunner) listProjects(status string) (any, error) {
+	projects, err := r.projectRepo.GetAll(status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch projects: %w", err)
+	}
+
+	type projectResult struct {
+		ID     int    `json:"id"`
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	results := []projectResult{}
+	for _, p := range projects {
+		if len(results) == maxToolListResults {
+			break
+		}
+		results = append(results, projectResult{ID: p.ID, Name: p.Name, Status: p.Status})
+	}
+	return results, nil
+}
+
+func (r *ToolRunner) listTasks(projectID int, status string) (any, error) {
+	var tasks []*task.Task
+	var err error
+	if projectID > 0 {
+		tasks, err = r.taskRepo.GetByProjectID(projectID, status)
+	} else {
+		tasks, err = r.taskRepo.GetAll(status)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+
+	results := []*taskResult{}
+	for _, t := range tasks {
+		if len(results) == maxToolListResults {
+			break
+		}
+		results = append(results, newTaskResult(t))
+	}
+	return results, nil
+}
+
+type taskResult struct {
+	ID        int    `json:"id"`
+	ProjectID int    `json:"project_id"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	Priority  string `json:"priority"`
+	DueDate   string `json:"due_date,omitempty"`
+}
+
+func newTaskResult(t *task.Task) *taskResult {
+	result := &taskResult{ID: t.ID, ProjectID: t.ProjectID, Title: t.Title, Status: t.Status, Priority: t.Priority}
+	if t.DueDate != nil {
+		result.DueDate = t.DueDate.Format("2006-01-02")
+	}
+	return result
+}
+
+func (r *ToolRunner) createTask(projectID int, title, description, priority, dueDateStr string) (any, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, fmt.Errorf("title cannot be empty")
+	}
+	if priority == "" {
+		priority = "medium"
+	}
+	if priority != "low" && priority != "medium" && priority != "high" {
+		return nil, fmt.Errorf("invalid priority: %s", priority)
+	}
+
+	var dueDate *time.Time
+	if dueDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dueDateStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due_date: %s (use YYYY-MM-DD)", dueDateStr)
+		}
+		dueDate = &parsed
+	}
+
+	p, err := r.projectRepo.GetByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch project #%d: %w", projectID, err)
+	}
+
+	question := fmt.Sprintf("Create task \"%s\" [%s] in project #%d %s? [y/N] ", title, priority, p.ID, p.Name)
+	if !confirm(question) {
+		return nil, errToolDeclined
+	}
+
+	t, err := r.taskHandler.CreateTask(p.ID, title, description, priority, dueDate)
+	if err != nil {
+		return nil, err
+	}
+	return newTaskResult(t), nil
+}
+
+func (r *ToolRunner) updateTaskStatus(taskID int, status string) (any, error) {
+	if status != "pending" && status != "in_progress" && status != "completed" {
+		return nil, fmt.Errorf("invalid status: %s", status)
+	}
+
+	t, err := r.taskRepo.GetByID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch task #%d: %w", taskID, err)
+	}
+
+	if t.Status != status {
+		question := fmt.Sprintf("Change task #%d \"%s\" from %s to %s? [y/N] ", t.ID, t.Title, t.Status, status)
+		if !confirm(question) {
+			return nil, errToolDeclined
+		}
+		if err := r.taskHandler.UpdateTask(t.ID, t.Title, t.Description, status, t.Priority, t.DueDate); err != nil {
+			return nil, err
+		}
+	}
+
+	result := newTaskResult(t)
+	result.Status = status
+	return result, nil
+}
+
+func (r *ToolRunner) toggleChecklistItem(itemID int) (any, error) {
+	item, err := r.checklistItemRepo.GetByID(itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch checklist item #%d: %w", itemID, err)
+	}
+
+	state := "done"
+	if item.Completed {
+		state = "not done"
+	}
+	question := fmt.Sprintf("Mark checklist item #%d \"%s\" as %s? [y/N] ", item.ID, item.Title, state)
+	if !confirm(question) {
+		return nil, errToolDeclined
+	}
+
+	if err := r.checklistHandler.ToggleChecklistItem(item.ID); err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"id": item.ID, "title": item.Title, "completed": !item.Completed}, nil
+}
@@ -0,0 +1,30 @@
+package link
+
+import "time"
+
+const (
+	TargetNote    = "note"
+	TargetProject = "project"
+	TargetTask    = "task"
+
+	RelationSource = "source" // the note was generated from the target
+)
+
+// Link connects a note to another note, a project or a task.
+type Link struct {
+	NoteID     int       `json:"note_id"`
+	TargetType string    `json:"target_type"`
+	TargetID   int       `json:"target_id"`
+	Relation   string    `json:"relation"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewLink(noteID int, targetType string, targetID int, relation string) *Link {
+	return &Link{
+		NoteID:     noteID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Relation:   relation,
+		CreatedAt:  time.Now(),
+	}
+}
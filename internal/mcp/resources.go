@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/snip/internal/ai"
+)
+
+const (
+	noteURIPrefix = "snip://note/"
+
+	// resourcePageSize is how many notes one resources/list call returns;
+	// the cursor for the next page is the offset as a string.
+	resourcePageSize = 100
+)
+
+func noteURI(id int) string {
+	return noteURIPrefix + strconv.Itoa(id)
+}
+
+func (s *Server) listResources(params json.RawMessage) (any, error) {
+	var p struct {
+		Cursor string `json:"cursor"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	offset := 0
+	if p.Cursor != "" {
+		n, err := strconv.Atoi(p.Cursor)
+		if err != nil || n < 0 {
+			return nil, invalidParams("invalid cursor: %s", p.Cursor)
+		}
+		offset = n
+	}
+
+	notes, err := s.repos.Notes.GetAll(false, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notes: %w", err)
+	}
+
+	resources := []map[string]any{}
+	next := ""
+	for i, n := range notes {
+		if s.config.ExcludesNote(n.Tags) || i < offset {
+			continue
+		}
+		if len(resources) == resourcePageSize {
+			next = strconv.Itoa(i)
+			break
+		}
+		resource := map[string]any{
+			"uri":      noteURI(n.ID),
+			"name":     n.Title,
+			"mimeType": "text/markdown",
+		}
+		if len(n.Tags) > 0 {
+			resource["description"] = "Tags: " + strings.Join(n.Tags, ", ")
+		}
+		resources = append(resources, resource)
+	}
+
+	result := map[string]any{"resources": resources}
+	if next != "" {
+		result["nextCursor"] = next
+	}
+	return result, nil
+}
+
+func (s *Server) listResourceTemplates() any {
+	return map[string]any{
+		"resourceTemplates": []map[string]any{{
+			"uriTemplate": noteURIPrefix + "{id}",
+			"name":        "Note",
+			"description": "A Snip note by id, as Markdown",
+			"mimeType":    "text/markdown",
+		}},
+	}
+}
+
+func (s *Server) readResource(params json.RawMessage) (any, error) {
+	var p struct {
+		URI string `json:"uri"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	idStr, ok := strings.CutPrefix(p.URI, noteURIPrefix)
+	id, err := strconv.Atoi(idStr)
+	if !ok || err != nil || id <= 0 {
+		return nil, invalidParams("unknown resource: %s (use %s<id>)", p.URI, noteURIPrefix)
+	}
+
+	n, err := s.allowedNote(id)
+	if err != nil {
+		return nil, invalidParams("%v", err)
+	}
+
+	text := "# " + n.Title + "\n\n"
+	if len(n.Tags) > 0 {
+		text += "Tags: " + strings.Join(n.Tags, ", ") + "\n\n"
+	}
+	text += n.Content
+
+	return map[string]any{
+		"contents": []map[string]any{{
+			"uri":      p.URI,
+			"mimeType": "text/markdown",
+			"text":     s.redact(text),
+		}},
+	}, nil
+}
+
+func (s *Server) redact(text string) string {
+	masked, _ := ai.RedactText(s.config, text)
+	return masked
+}
+
+func truncate(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max]) + "..."
+}
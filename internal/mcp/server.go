@@ -0,0 +1,244 @@
+// Package mcp serves Snip to AI agents over the Model Context Protocol: a
+// JSON-RPC 2.0 exchange of newline-delimited messages on stdin and stdout.
+// Notes, tasks and checklist items are exposed as tools, and notes also as
+// resources. Everything sent out is filtered and masked like AI requests:
+// notes with an excluded tag are hidden and secrets are redacted.
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/repository"
+)
+
+const (
+	ServerName    = "snip"
+	ServerVersion = "1.0.0"
+
+	// LatestProtocolVersion is answered to clients asking for a version this
+	// server does not know.
+	LatestProtocolVersion = "2025-06-18"
+)
+
+var supportedProtocolVersions = []string{"2024-11-05", "2025-03-26", LatestProtocolVersion}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Repositories are the stores the tools and resources are backed by.
+// Actions logs every tool call, like tool calls made during ai chat.
+type Repositories struct {
+	Notes          repository.NoteRepository
+	Tags           repository.TagRepository
+	Projects       repository.ProjectRepository
+	Tasks          repository.TaskRepository
+	ChecklistItems repository.ChecklistItemRepository
+	Actions        repository.ActionRepository
+}
+
+type Server struct {
+	repos    Repositories
+	config   *ai.Config
+	readOnly bool
+	tools    []tool
+	log      io.Writer
+}
+
+// New returns a server over repos. config supplies exclude_tags and
+// redact_patterns and may be nil. A read-only server does not offer the
+// tools that change data.
+func New(repos Repositories, config *ai.Config, readOnly bool) *Server {
+	s := &Server{repos: repos, config: config, readOnly: readOnly, log: io.Discard}
+	for _, t := range s.toolTable() {
+		if readOnly && !t.readOnly {
+			continue
+		}
+		s.tools = append(s.tools, t)
+	}
+	return s
+}
+
+// SetLog sets where diagnostics go. Stdout carries the protocol, so the
+// command points this at stderr.
+func (s *Server) SetLog(w io.Writer) {
+	s.log = w
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+func invalidParams(format string, args ...any) error {
+	return &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+// Serve handles messages from in until it is closed, writing one response
+// per line to out. Notifications get no response.
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	writer := bufio.NewWriter(out)
+	enc := json.NewEncoder(writer)
+	enc.SetEscapeHTML(false)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if resp := s.handleMessage(line); resp != nil {
+				// Encode ends the message with the newline that delimits it.
+				if err := enc.Encode(resp); err != nil {
+					return err
+				}
+				if err := writer.Flush(); err != nil {
+					return err
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handleMessage(line []byte) *response {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		code := codeParseError
+		if bytes.HasPrefix(bytes.TrimSpace(line), []byte("[")) {
+			code = codeInvalidRequest
+			err = errors.New("batches are not supported")
+		}
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: code, Message: err.Error()}}
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return &response{JSONRPC: "2.0", ID: idOrNull(req.ID), Error: &rpcError{Code: codeInvalidRequest, Message: "invalid JSON-RPC 2.0 request"}}
+	}
+
+	result, err := s.dispatch(req.Method, req.Params)
+	if len(req.ID) == 0 {
+		// A notification: nothing is sent back, not even errors.
+		if err != nil {
+			fmt.Fprintf(s.log, "mcp: %s: %v\n", req.Method, err)
+		}
+		return nil
+	}
+
+	resp := &response{JSONRPC: "2.0", ID: req.ID}
+	if err != nil {
+		var rpcErr *rpcError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &rpcError{Code: codeInternalError, Message: err.Error()}
+		}
+		resp.Error = rpcErr
+		return resp
+	}
+	if result == nil {
+		result = struct{}{}
+	}
+	resp.Result = result
+	return resp
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+func (s *Server) dispatch(method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "initialize":
+		return s.initialize(params)
+	case "ping", "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	case "tools/list":
+		return s.listTools(), nil
+	case "tools/call":
+		return s.callTool(params)
+	case "resources/list":
+		return s.listResources(params)
+	case "resources/templates/list":
+		return s.listResourceTemplates(), nil
+	case "resources/read":
+		return s.readResource(params)
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + method}
+}
+
+func (s *Server) initialize(params json.RawMessage) (any, error) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ClientInfo      struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"clientInfo"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	version := LatestProtocolVersion
+	if slices.Contains(supportedProtocolVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+	fmt.Fprintf(s.log, "mcp: %s %s connected (protocol %s)\n", p.ClientInfo.Name, p.ClientInfo.Version, version)
+
+	instructions := "Snip is the user's notes and task manager. Search notes before answering questions about the user's work, and cite notes by their snip://note/<id> URI."
+	if s.readOnly {
+		instructions += " This server is read-only."
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities": map[string]any{
+			"tools":     map[string]any{},
+			"resources": map[string]any{},
+		},
+		"serverInfo":   map[string]any{"name": ServerName, "version": ServerVersion},
+		"instructions": instructions,
+	}, nil
+}
+
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return invalidParams("invalid params: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,467 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/snip/internal/action"
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/task"
+)
+
+const (
+	defaultSearchResults = 10
+	maxSearchResults     = 50
+	maxListResults       = 100
+	maxSnippet           = 300
+)
+
+var (
+	taskStatuses   = []string{"pending", "in_progress", "completed"}
+	taskPriorities = []string{"low", "medium", "high"}
+)
+
+type tool struct {
+	name        string
+	description string
+	inputSchema string
+	readOnly    bool
+	run         func(args json.RawMessage) (any, error)
+}
+
+func (s *Server) toolTable() []tool {
+	return []tool{
+		{
+			name:        "search_notes",
+			description: "Full-text search over the user's notes. Returns id, title, tags, a snippet and the resource URI of each match, best first.",
+			inputSchema: `{
+				"type": "object",
+				"properties": {
+					"query": {"type": "string", "description": "Search terms"},
+					"limit": {"type": "integer", "minimum": 1, "maximum": 50, "description": "Maximum number of results (default 10)"}
+				},
+				"required": ["query"]
+			}`,
+			readOnly: true,
+			run:      s.searchNotes,
+		},
+		{
+			name:        "get_note",
+			description: "Get the full content of a note by id.",
+			inputSchema: `{
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer"}
+				},
+				"required": ["id"]
+			}`,
+			readOnly: true,
+			run:      s.getNote,
+		},
+		{
+			name:        "create_note",
+			description: "Create a note. Content is Markdown.",
+			inputSchema: `{
+				"type": "object",
+				"properties": {
+					"title": {"type": "string"},
+					"content": {"type": "string"},
+					"tags": {"type": "array", "items": {"type": "string"}, "description": "Single-word tags"}
+				},
+				"required": ["title", "content"]
+			}`,
+			run: s.createNote,
+		},
+		{
+			name:        "list_tasks",
+			description: "List tasks, newest first, optionally of one project and with one status.",
+			inputSchema: `{
+				"type": "object",
+				"properties": {
+					"project_id": {"type": "integer"},
+					"status": {"type": "string", "enum": ["pending", "in_progress", "completed"]}
+				}
+			}`,
+			readOnly: true,
+			run:      s.listTasks,
+		},
+		{
+			name:        "create_task",
+			description: "Create a task in a project.",
+			inputSchema: `{
+				"type": "object",
+				"properties": {
+					"project_id": {"type": "integer"},
+					"title": {"type": "string"},
+					"description": {"type": "string"},
+					"priority": {"type": "string", "enum": ["low", "medium", "high"]},
+					"due_date": {"type": "string", "description": "Due date as YYYY-MM-DD"}
+				},
+				"required": ["project_id", "title"]
+			}`,
+			run: s.createTask,
+		},
+		{
+			name:        "toggle_checklist_item",
+			description: "Mark a checklist item as done, or as not done if it already is.",
+			inputSchema: `{
+				"type": "object",
+				"properties": {
+					"item_id": {"type": "integer"}
+				},
+				"required": ["item_id"]
+			}`,
+			run: s.toggleChecklistItem,
+		},
+	}
+}
+
+func (s *Server) listTools() any {
+	tools := make([]map[string]any, 0, len(s.tools))
+	for _, t := range s.tools {
+		tools = append(tools, map[string]any{
+			"name":        t.name,
+			"description": t.description,
+			"inputSchema": json.RawMessage(t.inputSchema),
+			"annotations": map[string]any{"readOnlyHint": t.readOnly},
+		})
+	}
+	return map[string]any{"tools": tools}
+}
+
+// callTool runs a tool and logs the call. Failures of the tool itself are
+// returned as an error result, so the agent can read them and adjust; only
+// unknown tools are protocol errors.
+func (s *Server) callTool(params json.RawMessage) (any, error) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(s.tools, func(t tool) bool { return t.name == p.Name })
+	if i < 0 {
+		return nil, invalidParams("unknown tool: %s", p.Name)
+	}
+	args := p.Arguments
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+
+	fmt.Fprintf(s.log, "mcp: %s %s\n", p.Name, args)
+	a := action.NewAction(nil, p.Name, string(args))
+
+	result, err := s.tools[i].run(args)
+	var text string
+	if err != nil {
+		a.Status = action.StatusFailed
+		text = err.Error()
+	} else {
+		a.Status = action.StatusExecuted
+		data, _ := json.MarshalIndent(result, "", "  ")
+		text = string(data)
+	}
+
+	a.Result = text
+	if s.repos.Actions != nil {
+		if err := s.repos.Actions.Create(a); err != nil {
+			fmt.Fprintf(s.log, "mcp: failed to log action: %v\n", err)
+		}
+	}
+
+	return map[string]any{
+		"content": []map[string]any{{"type": "text", "text": text}},
+		"isError": err != nil,
+	}, nil
+}
+
+func unmarshalArgs(raw json.RawMessage, v any) error {
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+type noteSummary struct {
+	ID      int      `json:"id"`
+	URI     string   `json:"uri"`
+	Title   string   `json:"title"`
+	Tags    []string `json:"tags"`
+	Snippet string   `json:"snippet"`
+}
+
+func (s *Server) searchNotes(raw json.RawMessage) (any, error) {
+	var args struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := unmarshalArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.Query) == "" {
+		return nil, errors.New("query cannot be empty")
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultSearchResults
+	}
+	limit = min(limit, maxSearchResults)
+
+	notes, err := s.repos.Notes.Search(args.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search notes: %w", err)
+	}
+
+	results := []noteSummary{}
+	for _, n := range notes {
+		if len(results) == limit {
+			break
+		}
+		tags, err := s.noteTags(n.ID)
+		if err != nil {
+			return nil, err
+		}
+		if s.config.ExcludesNote(tags) {
+			continue
+		}
+		results = append(results, noteSummary{
+			ID:      n.ID,
+			URI:     noteURI(n.ID),
+			Title:   n.Title,
+			Tags:    tags,
+			Snippet: s.redact(truncate(n.Content, maxSnippet)),
+		})
+	}
+	return results, nil
+}
+
+func (s *Server) noteTags(id int) ([]string, error) {
+	tags, err := s.repos.Notes.GetTagsByNote(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tags for note #%d: %w", id, err)
+	}
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return names, nil
+}
+
+type noteResult struct {
+	ID        int       `json:"id"`
+	URI       string    `json:"uri"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	Tags      []string  `json:"tags"`
+	Language  string    `json:"language,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (s *Server) getNote(raw json.RawMessage) (any, error) {
+	var args struct {
+		ID int `json:"id"`
+	}
+	if err := unmarshalArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	n, err := s.allowedNote(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.newNoteResult(n), nil
+}
+
+// allowedNote fetches a note, refusing the ones with an excluded tag.
+func (s *Server) allowedNote(id int) (*note.NoteWithTags, error) {
+	n, err := s.repos.Notes.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch note #%d: %w", id, err)
+	}
+	if s.config.ExcludesNote(n.Tags) {
+		return nil, fmt.Errorf("note #%d has an excluded tag and is not shared with AI agents", id)
+	}
+	return n, nil
+}
+
+func (s *Server) newNoteResult(n *note.NoteWithTags) *noteResult {
+	tags := n.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return &noteResult{
+		ID:        n.ID,
+		URI:       noteURI(n.ID),
+		Title:     n.Title,
+		Content:   s.redact(n.Content),
+		Tags:      tags,
+		Language:  n.Language,
+		CreatedAt: n.CreatedAt,
+		UpdatedAt: n.UpdatedAt,
+	}
+}
+
+func (s *Server) createNote(raw json.RawMessage) (any, error) {
+	var args struct {
+		Title   string   `json:"title"`
+		Content string   `json:"content"`
+		Tags    []string `json:"tags"`
+	}
+	if err := unmarshalArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.Title) == "" {
+		return nil, errors.New("title cannot be empty")
+	}
+
+	n := note.NewNote(args.Title, args.Content)
+	if err := s.repos.Notes.Create(n); err != nil {
+		return nil, fmt.Errorf("failed to create note: %w", err)
+	}
+
+	tags := []string{}
+	for _, name := range args.Tags {
+		for name := range strings.FieldsSeq(name) {
+			if slices.Contains(tags, name) {
+				continue
+			}
+			t, err := s.repos.Tags.GetOrCreate(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create tag %q: %w", name, err)
+			}
+			if err := s.repos.Notes.AddTagToNote(n.ID, t.ID); err != nil {
+				return nil, fmt.Errorf("failed to tag note: %w", err)
+			}
+			tags = append(tags, name)
+		}
+	}
+
+	return map[string]any{"id": n.ID, "uri": noteURI(n.ID), "title": n.Title, "tags": tags}, nil
+}
+
+type taskResult struct {
+	ID          int    `json:"id"`
+	ProjectID   int    `json:"project_id"`
+	ParentID    *int   `json:"parent_id,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Status      string `json:"status"`
+	Priority    string `json:"priority"`
+	DueDate     string `json:"due_date,omitempty"`
+}
+
+func newTaskResult(t *task.Task) *taskResult {
+	result := &taskResult{
+		ID:          t.ID,
+		ProjectID:   t.ProjectID,
+		ParentID:    t.ParentID,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      t.Status,
+		Priority:    t.Priority,
+	}
+	if t.DueDate != nil {
+		result.DueDate = t.DueDate.Format("2006-01-02")
+	}
+	return result
+}
+
+func (s *Server) listTasks(raw json.RawMessage) (any, error) {
+	var args struct {
+		ProjectID int    `json:"project_id"`
+		Status    string `json:"status"`
+	}
+	if err := unmarshalArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if args.Status != "" && !slices.Contains(taskStatuses, args.Status) {
+		return nil, fmt.Errorf("invalid status: %s (use %s)", args.Status, strings.Join(taskStatuses, ", "))
+	}
+
+	var tasks []*task.Task
+	var err error
+	if args.ProjectID > 0 {
+		tasks, err = s.repos.Tasks.GetByProjectID(args.ProjectID, args.Status)
+	} else {
+		tasks, err = s.repos.Tasks.GetAll(args.Status)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+
+	results := []*taskResult{}
+	for _, t := range tasks {
+		if len(results) == maxListResults {
+			break
+		}
+		results = append(results, newTaskResult(t))
+	}
+	return results, nil
+}
+
+func (s *Server) createTask(raw json.RawMessage) (any, error) {
+	var args struct {
+		ProjectID   int    `json:"project_id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Priority    string `json:"priority"`
+		DueDate     string `json:"due_date"`
+	}
+	if err := unmarshalArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.Title) == "" {
+		return nil, errors.New("title cannot be empty")
+	}
+	if args.Priority == "" {
+		args.Priority = "medium"
+	}
+	if !slices.Contains(taskPriorities, args.Priority) {
+		return nil, fmt.Errorf("invalid priority: %s (use %s)", args.Priority, strings.Join(taskPriorities, ", "))
+	}
+
+	var dueDate *time.Time
+	if args.DueDate != "" {
+		parsed, err := time.Parse("2006-01-02", args.DueDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due_date: %s (use YYYY-MM-DD)", args.DueDate)
+		}
+		dueDate = &parsed
+	}
+
+	p, err := s.repos.Projects.GetByID(args.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch project #%d: %w", args.ProjectID, err)
+	}
+
+	t := task.NewTask(p.ID, args.Title, args.Description, args.Priority)
+	t.DueDate = dueDate
+	if err := s.repos.Tasks.Create(t); err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+	return newTaskResult(t), nil
+}
+
+func (s *Server) toggleChecklistItem(raw json.RawMessage) (any, error) {
+	var args struct {
+		ItemID int `json:"item_id"`
+	}
+	if err := unmarshalArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	item, err := s.repos.ChecklistItems.GetByID(args.ItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch checklist item #%d: %w", args.ItemID, err)
+	}
+	if err := s.repos.ChecklistItems.ToggleComplete(item.ID); err != nil {
+		return nil, fmt.Errorf("failed to toggle checklist item: %w", err)
+	}
+
+	return map[string]any{"id": item.ID, "checklist_id": item.ChecklistID, "title": item.Title, "completed": !item.Completed}, nil
+}
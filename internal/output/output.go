@@ -0,0 +1,196 @@
+// Package output prints command results in the format chosen with the
+// global --output flag. Text, the default, is each command's usual human
+// output. JSON and YAML encode the same data with the models' json field
+// names, and table prints one aligned row per item, so scripts do not have
+// to scrape the text.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatTable Format = "table"
+)
+
+// Formats lists the accepted --output values.
+var Formats = []Format{FormatText, FormatJSON, FormatYAML, FormatTable}
+
+var (
+	selected           = FormatText
+	out      io.Writer = os.Stdout
+)
+
+// ParseFormat validates an --output value. An empty value is text.
+func ParseFormat(value string) (Format, error) {
+	if value == "" {
+		return FormatText, nil
+	}
+	for _, f := range Formats {
+		if strings.EqualFold(value, string(f)) {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("invalid output format %q (use %s)", value, strings.Join(names, ", "))
+}
+
+// SetFormat selects the format Print uses for the rest of the command.
+func SetFormat(f Format) {
+	selected = f
+}
+
+// Selected returns the format chosen with SetFormat.
+func Selected() Format {
+	return selected
+}
+
+// IsStructured reports whether the selected format is meant for programs
+// rather than people.
+func IsStructured() bool {
+	return selected != FormatText
+}
+
+// SetWriter changes where Print writes, os.Stdout by default.
+func SetWriter(w io.Writer) {
+	out = w
+}
+
+// Table is the tabular form of a result: one header per column and one
+// row per item.
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// NewTable returns an empty table with the given column headers.
+func NewTable(columns ...string) *Table {
+	return &Table{Columns: columns}
+}
+
+// Add appends a row, formatting each cell with fmt.Sprint. Nil pointers
+// and nil values become empty cells.
+func (t *Table) Add(cells ...any) {
+	row := make([]string, len(cells))
+	for i, cell := range cells {
+		v := reflect.ValueOf(cell)
+		if !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+			continue
+		}
+		if v.Kind() == reflect.Pointer {
+			cell = v.Elem().Interface()
+		}
+		row[i] = strings.ReplaceAll(fmt.Sprint(cell), "\n", " ")
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// Print writes data in the selected format. text prints the command's usual
+// output and is only called for the text format; table may be nil when the
+// command has no tabular form, in which case table falls back to YAML.
+func Print(data any, table *Table, text func() error) error {
+	if selected == FormatText {
+		return text()
+	}
+	return Write(out, selected, data, table)
+}
+
+// Write encodes data, or table for the table format, to w. A nil slice is
+// written as an empty list, so "no results" is still valid JSON or YAML.
+func Write(w io.Writer, f Format, data any, table *Table) error {
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice && v.IsNil() {
+		data = []any{}
+	}
+
+	switch f {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(data)
+	case FormatYAML:
+		return writeYAML(w, data)
+	case FormatTable:
+		if table == nil {
+			return writeYAML(w, data)
+		}
+		return writeTable(w, table)
+	}
+
+	return fmt.Errorf("unsupported output format: %s", f)
+}
+
+// writeYAML encodes data through JSON so field names and order match the
+// JSON output, then rewrites the nodes in block style.
+func writeYAML(w io.Writer, data any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(encoded, &doc); err != nil {
+		return err
+	}
+	blockStyle(&doc)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func blockStyle(node *yaml.Node) {
+	// Strings keep their quotes when they would otherwise read as another
+	// type, such as "true" or "12".
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		node.Style = 0
+		var probe any
+		if yaml.Unmarshal([]byte(node.Value), &probe) != nil || reflect.TypeOf(probe) != reflect.TypeOf("") {
+			node.Style = yaml.DoubleQuotedStyle
+		}
+	} else {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+func writeTable(w io.Writer, table *Table) error {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(table.Columns, "\t"))
+	for _, row := range table.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// tabwriter pads the last column too; drop the trailing spaces.
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/snip/internal/action"
+)
+
+type ActionRepository interface {
+	Create(a *action.Action) error
+	GetRecent(limit int) ([]*action.Action, error)
+	Close() error
+}
+
+type actionRepository struct {
+	db *sql.DB
+}
+
+func NewActionRepository(db *sql.DB) (ActionRepository, error) {
+	return &actionRepository{db: db}, nil
+}
+
+func (r *actionRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *actionRepository) Create(a *action.Action) error {
+	query := `
+		INSERT INTO ai_actions (session_id, tool, arguments, status, result, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query, a.SessionID, a.Tool, a.Arguments, a.Status, a.Result, a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = int(id)
+	return nil
+}
+
+// GetRecent returns the latest actions first.
+func (r *actionRepository) GetRecent(limit int) ([]*action.Action, error) {
+	query := `
+		SELECT id, session_id, tool, arguments, status, result, created_at
+		FROM ai_actions ORDER BY id DESC LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var actions []*action.Action
+	for rows.Next() {
+		a := &action.Action{}
+		var sessionID sql.NullInt64
+		if err := rows.Scan(&a.ID, &sessionID, &a.Tool, &a.Arguments, &a.Status, &a.Result, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		if sessionID.Valid {
+			id := int(sessionID.Int64)
+			a.SessionID = &id
+		}
+		actions = append(actions, a)
+	}
+
+	return actions, rows.Err()
+}
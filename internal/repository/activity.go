@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/snip/internal/activity"
+)
+
+type ActivityRepository interface {
+	// GetSince returns task status transitions since the given time, oldest
+	// first. projectID 0 means all projects.
+	GetSince(since time.Time, projectID int) ([]*activity.TaskActivity, error)
+	Close() error
+}
+
+type activityRepository struct {
+	db *sql.DB
+}
+
+func NewActivityRepository(db *sql.DB) (ActivityRepository, error) {
+	return &activityRepository{db: db}, nil
+}
+
+func (r *activityRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *activityRepository) GetSince(since time.Time, projectID int) ([]*activity.TaskActivity, error) {
+	// Rows are written by a trigger with CURRENT_TIMESTAMP, which is UTC text.
+	query := `
+		SELECT id, task_id, project_id, task_title, COALESCE(from_status, ''), to_status, created_at
+		FROM task_activity WHERE created_at >= ?
+	`
+	args := []any{since.UTC().Format("2006-01-02 15:04:05")}
+
+	if projectID > 0 {
+		query += ` AND project_id = ?`
+		args = append(args, projectID)
+	}
+	query += ` ORDER BY created_at, id`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*activity.TaskActivity
+	for rows.Next() {
+		a := &activity.TaskActivity{}
+		if err := rows.Scan(&a.ID, &a.TaskID, &a.ProjectID, &a.TaskTitle, &a.FromStatus, &a.ToStatus, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, a)
+	}
+
+	return entries, rows.Err()
+}
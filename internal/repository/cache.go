@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/snip/internal/cache"
+)
+
+var ErrCacheMiss = errors.New("cache entry not found")
+
+type CacheRepository interface {
+	// Get returns the entry for key if it was stored after since and counts
+	// the hit. Missing and expired entries return ErrCacheMiss.
+	Get(key string, since time.Time) (*cache.Entry, error)
+	Put(e *cache.Entry) error
+	Stats(since time.Time) (*cache.Stats, error)
+	// Clear deletes the entries stored before the given time; the zero time
+	// deletes everything.
+	Clear(before time.Time) (int64, error)
+	Close() error
+}
+
+type cacheRepository struct {
+	db *sql.DB
+}
+
+func NewCacheRepository(db *sql.DB) (CacheRepository, error) {
+	return &cacheRepository{db: db}, nil
+}
+
+func (r *cacheRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *cacheRepository) Get(key string, since time.Time) (*cache.Entry, error) {
+	query := `
+		SELECT key, model, response, hits, created_at, last_hit_at
+		FROM ai_cache
+		WHERE key = ? AND created_at >= ?
+	`
+	e := &cache.Entry{}
+	var lastHit sql.NullTime
+	err := r.db.QueryRow(query, key, since).Scan(&e.Key, &e.Model, &e.Response, &e.Hits, &e.CreatedAt, &lastHit)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCacheMiss
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := r.db.Exec(`UPDATE ai_cache SET hits = hits + 1, last_hit_at = ? WHERE key = ?`, now, key); err != nil {
+		return nil, err
+	}
+	e.Hits++
+	e.LastHitAt = &now
+	return e, nil
+}
+
+func (r *cacheRepository) Put(e *cache.Entry) error {
+	query := `
+		INSERT INTO ai_cache (key, model, response, hits, created_at, last_hit_at)
+		VALUES (?, ?, ?, 0, ?, NULL)
+		ON CONFLICT(key) DO UPDATE SET
+			model = excluded.model,
+			response = excluded.response,
+			hits = 0,
+			created_at = excluded.created_at,
+			last_hit_at = NULL
+	`
+	_, err := r.db.Exec(query, e.Key, e.Model, e.Response, e.CreatedAt)
+	return err
+}
+
+func (r *cacheRepository) Stats(since time.Time) (*cache.Stats, error) {
+	query := `
+		SELECT COUNT(*),
+			COALESCE(SUM(CASE WHEN created_at >= ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(hits), 0),
+			COALESCE(SUM(LENGTH(response)), 0)
+		FROM ai_cache
+	`
+	s := &cache.Stats{}
+	if err := r.db.QueryRow(query, since).Scan(&s.Entries, &s.Fresh, &s.Hits, &s.Bytes); err != nil {
+		return nil, err
+	}
+	if s.Entries == 0 {
+		return s, nil
+	}
+
+	// Read the bounds as stored values so they scan back into time.Time.
+	var oldest, newest time.Time
+	if err := r.db.QueryRow(`SELECT created_at FROM ai_cache ORDER BY created_at ASC LIMIT 1`).Scan(&oldest); err != nil {
+		return nil, err
+	}
+	if err := r.db.QueryRow(`SELECT created_at FROM ai_cache ORDER BY created_at DESC LIMIT 1`).Scan(&newest); err != nil {
+		return nil, err
+	}
+	s.Oldest = &oldest
+	s.Newest = &newest
+	return s, nil
+}
+
+func (r *cacheRepository) Clear(before time.Time) (int64, error) {
+	var (
+		result sql.Result
+		err    error
+	)
+	if before.IsZero() {
+		result, err = r.db.Exec(`DELETE FROM ai_cache`)
+	} else {
+		result, err = r.db.Exec(`DELETE FROM ai_cache WHERE created_at < ?`, before)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/snip/internal/chat"
+)
+
+var ErrSessionNotFound = errors.New("chat session not found")
+
+type ChatRepository interface {
+	CreateSession(s *chat.Session) error
+	GetSession(id int) (*chat.Session, error)
+	ListSessions(limit int) ([]*chat.Session, error)
+	UpdateSession(id int, title string) error
+	AddMessage(m *chat.Message) error
+	GetMessages(sessionID int) ([]*chat.Message, error)
+	Close() error
+}
+
+type chatRepository struct {
+	db *sql.DB
+}
+
+func NewChatRepository(db *sql.DB) (ChatRepository, error) {
+	return &chatRepository{db: db}, nil
+}
+
+func (r *chatRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *chatRepository) CreateSession(s *chat.Session) error {
+	query := `INSERT INTO ai_sessions (title, created_at, updated_at) VALUES (?, ?, ?)`
+
+	result, err := r.db.Exec(query, s.Title, s.CreatedAt, s.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	s.ID = int(id)
+	return nil
+}
+
+func (r *chatRepository) GetSession(id int) (*chat.Session, error) {
+	query := `
+		SELECT s.id, s.title, s.created_at, s.updated_at,
+			(SELECT COUNT(*) FROM ai_messages m WHERE m.session_id = s.id AND m.role != 'context')
+		FROM ai_sessions s WHERE s.id = ?
+	`
+
+	s := &chat.Session{}
+	err := r.db.QueryRow(query, id).Scan(&s.ID, &s.Title, &s.CreatedAt, &s.UpdatedAt, &s.MessageCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ListSessions returns the most recently used sessions first.
+func (r *chatRepository) ListSessions(limit int) ([]*chat.Session, error) {
+	query := `
+		SELECT s.id, s.title, s.created_at, s.updated_at,
+			(SELECT COUNT(*) FROM ai_messages m WHERE m.session_id = s.id AND m.role != 'context')
+		FROM ai_sessions s
+		ORDER BY s.updated_at DESC, s.id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*chat.Session
+	for rows.Next() {
+		s := &chat.Session{}
+		if err := rows.Scan(&s.ID, &s.Title, &s.CreatedAt, &s.UpdatedAt, &s.MessageCount); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	return sessions, rows.Err()
+}
+
+// UpdateSession marks the session as used now and, when title is not empty,
+// renames it.
+func (r *chatRepository) UpdateSession(id int, title string) error {
+	query := `UPDATE ai_sessions SET updated_at = ?`
+	args := []any{time.Now()}
+
+	if title != "" {
+		query += `, title = ?`
+		args = append(args, title)
+	}
+
+	query += ` WHERE id = ?`
+	args = append(args, id)
+
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+func (r *chatRepository) AddMessage(m *chat.Message) error {
+	query := `
+		INSERT INTO ai_messages (session_id, role, content, note_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query, m.SessionID, m.Role, m.Content, m.NoteID, m.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	m.ID = int(id)
+	return nil
+}
+
+func (r *chatRepository) GetMessages(sessionID int) ([]*chat.Message, error) {
+	query := `
+		SELECT id, session_id, role, content, note_id, created_at
+		FROM ai_messages WHERE session_id = ? ORDER BY id
+	`
+
+	rows, err := r.db.Query(query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*chat.Message
+	for rows.Next() {
+		m := &chat.Message{}
+		var noteID sql.NullInt64
+		if err := rows.Scan(&m.ID, &m.SessionID, &m.Role, &m.Content, &noteID, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		if noteID.Valid {
+			id := int(noteID.Int64)
+			m.NoteID = &id
+		}
+		messages = append(messages, m)
+	}
+
+	return messages, rows.Err()
+}
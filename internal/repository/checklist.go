@@ -9,6 +9,7 @@ import (
 )
 
 var ErrChecklistNotFound = errors.New("checklist not found")
+var ErrChecklistItemNotFound = errors.New("checklist item not found")
 
 type ChecklistRepository interface {
 	Create(c *checklist.Checklist) error
@@ -23,6 +24,7 @@ type ChecklistRepository interface {
 
 type ChecklistItemRepository interface {
 	Create(item *checklist.ChecklistItem) error
+	GetByID(id int) (*checklist.ChecklistItem, error)
 	GetByChecklistID(checklistID int) ([]*checklist.ChecklistItem, error)
 	Update(id int, title, description string, completed bool) error
 	ToggleComplete(id int) error
@@ -171,14 +173,14 @@ func (r *checklistRepository) scanChecklists(rows *sql.Rows) ([]*checklist.Check
 
 func (r *checklistRepository) Update(id int, title, description string) error {
 	query := `UPDATE checklists SET title = ?, description = ?, updated_at = ? WHERE id = ?`
-	_, err := r.db.Exec(query, title, description, time.Now(), id)
-	return err
+	result, err := r.db.Exec(query, title, description, time.Now(), id)
+	return expectRow(result, err, ErrChecklistNotFound)
 }
 
 func (r *checklistRepository) Delete(id int) error {
 	query := `DELETE FROM checklists WHERE id = ?`
-	_, err := r.db.Exec(query, id)
-	return err
+	result, err := r.db.Exec(query, id)
+	return expectRow(result, err, ErrChecklistNotFound)
 }
 
 // ChecklistItemRepository methods
@@ -207,6 +209,24 @@ func (r *checklistItemRepository) Create(item *checklist.ChecklistItem) error {
 	return nil
 }
 
+func (r *checklistItemRepository) GetByID(id int) (*checklist.ChecklistItem, error) {
+	query := `SELECT id, checklist_id, title, description, completed, item_order, created_at, updated_at
+		FROM checklist_items WHERE id = ?`
+
+	item := &checklist.ChecklistItem{}
+	var completed int
+	err := r.db.QueryRow(query, id).Scan(&item.ID, &item.ChecklistID, &item.Title, &item.Description, &completed, &item.Order, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrChecklistItemNotFound
+		}
+		return nil, err
+	}
+	item.Completed = completed == 1
+
+	return item, nil
+}
+
 func (r *checklistItemRepository) GetByChecklistID(checklistID int) ([]*checklist.ChecklistItem, error) {
 	query := `SELECT id, checklist_id, title, description, completed, item_order, created_at, updated_at 
 		FROM checklist_items WHERE checklist_id = ? ORDER BY item_order ASC, created_at ASC`
@@ -238,8 +258,8 @@ func (r *checklistItemRepository) Update(id int, title, description string, comp
 		completedVal = 1
 	}
 	query := `UPDATE checklist_items SET title = ?, description = ?, completed = ?, updated_at = ? WHERE id = ?`
-	_, err := r.db.Exec(query, title, description, completedVal, time.Now(), id)
-	return err
+	result, err := r.db.Exec(query, title, description, completedVal, time.Now(), id)
+	return expectRow(result, err, ErrChecklistItemNotFound)
 }
 
 func (r *checklistItemRepository) ToggleComplete(id int) error {
@@ -249,13 +269,13 @@ func (r *checklistItemRepository) ToggleComplete(id int) error {
 		    updated_at = ?
 		WHERE id = ?
 	`
-	_, err := r.db.Exec(query, time.Now(), id)
-	return err
+	result, err := r.db.Exec(query, time.Now(), id)
+	return expectRow(result, err, ErrChecklistItemNotFound)
 }
 
 func (r *checklistItemRepository) Delete(id int) error {
 	query := `DELETE FROM checklist_items WHERE id = ?`
-	_, err := r.db.Exec(query, id)
-	return err
+	result, err := r.db.Exec(query, id)
+	return expectRow(result, err, ErrChecklistItemNotFound)
 }
 
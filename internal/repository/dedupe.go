@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/snip/internal/dedupe"
+)
+
+type DedupeRepository interface {
+	Ignore(p *dedupe.IgnoredPair) error
+	GetIgnored() ([]*dedupe.IgnoredPair, error)
+	Close() error
+}
+
+type dedupeRepository struct {
+	db *sql.DB
+}
+
+func NewDedupeRepository(db *sql.DB) (DedupeRepository, error) {
+	return &dedupeRepository{db: db}, nil
+}
+
+func (r *dedupeRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *dedupeRepository) Ignore(p *dedupe.IgnoredPair) error {
+	query := `
+		INSERT OR IGNORE INTO dedupe_ignored (note_a, note_b, created_at)
+		VALUES (?, ?, ?)
+	`
+	_, err := r.db.Exec(query, p.NoteA, p.NoteB, p.CreatedAt)
+	return err
+}
+
+func (r *dedupeRepository) GetIgnored() ([]*dedupe.IgnoredPair, error) {
+	rows, err := r.db.Query(`SELECT note_a, note_b, created_at FROM dedupe_ignored ORDER BY note_a, note_b`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pairs []*dedupe.IgnoredPair
+	for rows.Next() {
+		p := &dedupe.IgnoredPair{}
+		if err := rows.Scan(&p.NoteA, &p.NoteB, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, p)
+	}
+
+	return pairs, rows.Err()
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/snip/internal/embedding"
+)
+
+type EmbeddingRepository interface {
+	Upsert(e *embedding.Embedding) error
+	GetAll(provider string) ([]*embedding.Embedding, error)
+	Delete(noteID int) error
+	Close() error
+}
+
+type embeddingRepository struct {
+	db *sql.DB
+}
+
+func NewEmbeddingRepository(db *sql.DB) (EmbeddingRepository, error) {
+	return &embeddingRepository{db: db}, nil
+}
+
+func (r *embeddingRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *embeddingRepository) Upsert(e *embedding.Embedding) error {
+	query := `
+		INSERT INTO note_embeddings (note_id, provider, content_hash, vector, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(note_id) DO UPDATE SET
+			provider = excluded.provider,
+			content_hash = excluded.content_hash,
+			vector = excluded.vector,
+			updated_at = excluded.updated_at
+	`
+	_, err := r.db.Exec(query, e.NoteID, e.Provider, e.ContentHash, encodeVector(e.Vector), e.UpdatedAt)
+	return err
+}
+
+func (r *embeddingRepository) GetAll(provider string) ([]*embedding.Embedding, error) {
+	query := `SELECT note_id, provider, content_hash, vector, updated_at FROM note_embeddings WHERE provider = ?`
+
+	rows, err := r.db.Query(query, provider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var embeddings []*embedding.Embedding
+	for rows.Next() {
+		e := &embedding.Embedding{}
+		var blob []byte
+		if err := rows.Scan(&e.NoteID, &e.Provider, &e.ContentHash, &blob, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		vector, err := decodeVector(blob)
+		if err != nil {
+			return nil, err
+		}
+		e.Vector = vector
+		embeddings = append(embeddings, e)
+	}
+
+	return embeddings, rows.Err()
+}
+
+func (r *embeddingRepository) Delete(noteID int) error {
+	query := `DELETE FROM note_embeddings WHERE note_id = ?`
+	_, err := r.db.Exec(query, noteID)
+	return err
+}
+
+// Vectors are stored as little-endian float32 blobs.
+func encodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(v))
+	}
+	return buf
+}
+
+func decodeVector(buf []byte) ([]float32, error) {
+	if len(buf)%4 != 0 {
+		return nil, errors.New("corrupted embedding vector")
+	}
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+	return vector, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/snip/internal/link"
+)
+
+type LinkRepository interface {
+	Create(l *link.Link) error
+	GetByNote(noteID int) ([]*link.Link, error)
+	GetBacklinks(targetType string, targetID int) ([]*link.Link, error)
+	Close() error
+}
+
+type linkRepository struct {
+	db *sql.DB
+}
+
+func NewLinkRepository(db *sql.DB) (LinkRepository, error) {
+	return &linkRepository{db: db}, nil
+}
+
+func (r *linkRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *linkRepository) Create(l *link.Link) error {
+	query := `
+		INSERT OR IGNORE INTO note_links (note_id, target_type, target_id, relation, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	_, err := r.db.Exec(query, l.NoteID, l.TargetType, l.TargetID, l.Relation, l.CreatedAt)
+	return err
+}
+
+func (r *linkRepository) GetByNote(noteID int) ([]*link.Link, error) {
+	query := `
+		SELECT note_id, target_type, target_id, relation, created_at
+		FROM note_links WHERE note_id = ? ORDER BY target_type, target_id
+	`
+	return r.query(query, noteID)
+}
+
+// GetBacklinks returns the links pointing at a note, project or task.
+func (r *linkRepository) GetBacklinks(targetType string, targetID int) ([]*link.Link, error) {
+	query := `
+		SELECT note_id, target_type, target_id, relation, created_at
+		FROM note_links WHERE target_type = ? AND target_id = ? ORDER BY note_id
+	`
+	return r.query(query, targetType, targetID)
+}
+
+func (r *linkRepository) query(query string, args ...interface{}) ([]*link.Link, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []*link.Link
+	for rows.Next() {
+		l := &link.Link{}
+		if err := rows.Scan(&l.NoteID, &l.TargetType, &l.TargetID, &l.Relation, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+
+	return links, rows.Err()
+}
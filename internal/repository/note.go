@@ -14,6 +14,8 @@ import (
 	"github.com/snip/internal/tag"
 )
 
+var ErrNoteNotFound = errors.New("note not found")
+
 type NoteRepository interface {
 	Create(note *note.Note) error
 	GetByID(id int) (*note.NoteWithTags, error)
@@ -24,6 +26,7 @@ type NoteRepository interface {
 	CheckByID(id int) error
 	Patch(id int, title string) error
 	GetRecent(limit int) ([]*note.NoteWithTags, error)
+	Merge(keepID, dropID int, content string) error
 	ExportNotes(exportDir string, since *time.Time, format string) error
 
 	// Tag operations
@@ -48,11 +51,11 @@ func (r *repository) Close() error {
 
 func (r *repository) Create(note *note.Note) error {
 	query := `
-		INSERT INTO notes (title, content, created_at, updated_at)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO notes (title, content, language, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
 	`
 
-	result, err := r.db.Exec(query, note.Title, note.Content, note.CreatedAt, note.UpdatedAt)
+	result, err := r.db.Exec(query, note.Title, note.Content, note.Language, note.CreatedAt, note.UpdatedAt)
 	if err != nil {
 		return err
 	}
@@ -68,23 +71,24 @@ func (r *repository) Create(note *note.Note) error {
 
 func (r *repository) GetByID(id int) (*note.NoteWithTags, error) {
 	query := `
-		SELECT n.id, n.title, n.content, n.created_at, n.updated_at, GROUP_CONCAT(t.name) AS tags
+		SELECT n.id, n.title, n.content, n.language, n.created_at, n.updated_at, GROUP_CONCAT(t.name) AS tags
 		FROM notes n
 		LEFT JOIN notes_tags nt ON n.id = nt.note_id
 		LEFT JOIN tags t ON nt.tag_id = t.id
 		WHERE n.id = ?
+		GROUP BY n.id
 	`
 
 	note := &note.NoteWithTags{}
 	var tagsStr sql.NullString
 
 	err := r.db.QueryRow(query, id).Scan(
-		&note.ID, &note.Title, &note.Content, &note.CreatedAt, &note.UpdatedAt, &tagsStr,
+		&note.ID, &note.Title, &note.Content, &note.Language, &note.CreatedAt, &note.UpdatedAt, &tagsStr,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("not found")
+			return nil, ErrNoteNotFound
 		}
 		return nil, err
 	}
@@ -103,7 +107,7 @@ func (r *repository) CheckByID(id int) error {
 
 	if err := r.db.QueryRow(query, id).Scan(&id); err != nil {
 		if err == sql.ErrNoRows {
-			return errors.New("not found")
+			return ErrNoteNotFound
 		}
 		return err
 	}
@@ -122,7 +126,7 @@ func (r *repository) GetAll(isAsc bool, tagID int) ([]*note.NoteWithTags, error)
 	args := []any{}
 
 	query := `
-		SELECT n.id, n.title, n.content, n.created_at, n.updated_at, GROUP_CONCAT(t.name) AS tags
+		SELECT n.id, n.title, n.content, n.language, n.created_at, n.updated_at, GROUP_CONCAT(t.name) AS tags
 		FROM notes n
 		LEFT JOIN notes_tags nt ON n.id = nt.note_id
 		LEFT JOIN tags t ON nt.tag_id = t.id
@@ -147,7 +151,7 @@ func (r *repository) GetAll(isAsc bool, tagID int) ([]*note.NoteWithTags, error)
 	for db.Next() {
 		note := &note.NoteWithTags{}
 		var tagsStr sql.NullString
-		err := db.Scan(&note.ID, &note.Title, &note.Content, &note.CreatedAt, &note.UpdatedAt, &tagsStr)
+		err := db.Scan(&note.ID, &note.Title, &note.Content, &note.Language, &note.CreatedAt, &note.UpdatedAt, &tagsStr)
 		if err != nil {
 			return nil, err
 		}
@@ -193,6 +197,37 @@ func (r *repository) Delete(id int) error {
 	return err
 }
 
+// Merge folds note dropID into keepID: keepID gets content and the tags of
+// both notes, links and tasks pointing at dropID move to keepID, and dropID
+// is deleted. Everything happens in one transaction.
+func (r *repository) Merge(keepID, dropID int, content string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	steps := []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE notes SET content = ?, updated_at = ? WHERE id = ?`, []any{content, time.Now(), keepID}},
+		{`INSERT OR IGNORE INTO notes_tags (note_id, tag_id) SELECT ?, tag_id FROM notes_tags WHERE note_id = ?`, []any{keepID, dropID}},
+		{`UPDATE OR IGNORE note_links SET note_id = ? WHERE note_id = ?`, []any{keepID, dropID}},
+		{`UPDATE OR IGNORE note_links SET target_id = ? WHERE target_type = 'note' AND target_id = ?`, []any{keepID, dropID}},
+		{`DELETE FROM note_links WHERE note_id = ? AND target_type = 'note' AND target_id = ?`, []any{keepID, keepID}},
+		{`UPDATE tasks SET source_note_id = ? WHERE source_note_id = ?`, []any{keepID, dropID}},
+		{`DELETE FROM notes WHERE id = ?`, []any{dropID}},
+	}
+	for _, step := range steps {
+		if _, err := tx.Exec(step.query, step.args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 func (r *repository) Search(term string) ([]*note.Note, error) {
 	query := `
 		SELECT id, title, content
@@ -271,7 +306,7 @@ func (r *repository) Patch(id int, title string) error {
 
 func (r *repository) GetRecent(limit int) ([]*note.NoteWithTags, error) {
 	query := `
-		SELECT n.id, n.title, n.content, n.created_at, n.updated_at, GROUP_CONCAT(t.name) AS tags
+		SELECT n.id, n.title, n.content, n.language, n.created_at, n.updated_at, GROUP_CONCAT(t.name) AS tags
 		FROM notes n
 		LEFT JOIN notes_tags nt ON n.id = nt.note_id
 		LEFT JOIN tags t ON nt.tag_id = t.id
@@ -291,7 +326,7 @@ func (r *repository) GetRecent(limit int) ([]*note.NoteWithTags, error) {
 	for db.Next() {
 		note := &note.NoteWithTags{}
 		var tagsStr sql.NullString
-		err := db.Scan(&note.ID, &note.Title, &note.Content, &note.CreatedAt, &note.UpdatedAt, &tagsStr)
+		err := db.Scan(&note.ID, &note.Title, &note.Content, &note.Language, &note.CreatedAt, &note.UpdatedAt, &tagsStr)
 		if err != nil {
 			return nil, err
 		}
@@ -314,6 +349,7 @@ func (r *repository) ExportNotes(exportDir string, since *time.Time, format stri
 			n.id,
 			n.title,
 			n.content,
+			n.language,
 			n.created_at,
 			n.updated_at,
 			GROUP_CONCAT(t.name) as tags
@@ -344,12 +380,13 @@ func (r *repository) ExportNotes(exportDir string, since *time.Time, format stri
 			id        int
 			title     string
 			content   string
+			language  string
 			createdAt time.Time
 			updatedAt time.Time
 			tagsStr   sql.NullString
 		)
 
-		if err := rows.Scan(&id, &title, &content, &createdAt, &updatedAt, &tagsStr); err != nil {
+		if err := rows.Scan(&id, &title, &content, &language, &createdAt, &updatedAt, &tagsStr); err != nil {
 			return err
 		}
 
@@ -363,6 +400,7 @@ func (r *repository) ExportNotes(exportDir string, since *time.Time, format stri
 			Title:     title,
 			Content:   content,
 			Tags:      tags,
+			Language:  language,
 			CreatedAt: createdAt,
 			UpdatedAt: updatedAt,
 		}
@@ -419,6 +457,9 @@ func writeMarkdownNotesToFile(note note.NoteWithTags, exportDir string) error {
 	if len(note.Tags) > 0 {
 		fmt.Fprintf(f, "**Tags:** %s\n", strings.Join(note.Tags, ", "))
 	}
+	if note.Language != "" {
+		fmt.Fprintf(f, "**Language:** %s\n", note.Language)
+	}
 	fmt.Fprintf(f, "**Created:** %s\n", note.CreatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Fprintf(f, "**Updated:** %s\n", note.UpdatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Fprintf(f, "\n---\n\n")
@@ -103,13 +103,29 @@ func (r *projectRepository) Update(id int, name, description, status string) err
 		SET name = ?, description = ?, status = ?, updated_at = ?
 		WHERE id = ?
 	`
-	_, err := r.db.Exec(query, name, description, status, time.Now(), id)
-	return err
+	result, err := r.db.Exec(query, name, description, status, time.Now(), id)
+	return expectRow(result, err, ErrProjectNotFound)
 }
 
 func (r *projectRepository) Delete(id int) error {
 	query := `DELETE FROM projects WHERE id = ?`
-	_, err := r.db.Exec(query, id)
-	return err
+	result, err := r.db.Exec(query, id)
+	return expectRow(result, err, ErrProjectNotFound)
+}
+
+// expectRow turns the result of an UPDATE or DELETE by ID into notFound when
+// no row matched, so changing a missing record is an error like reading one.
+func expectRow(result sql.Result, err error, notFound error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
 }
 
@@ -18,9 +18,37 @@ type TaskRepository interface {
 	Update(id int, title, description, status, priority string, dueDate *time.Time) error
 	Delete(id int) error
 	ToggleComplete(id int) error
+	GetChildren(parentID int) ([]*task.Task, error)
 	Close() error
 }
 
+const taskColumns = `id, project_id, parent_id, source_note_id, title, description, status, priority, due_date, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTask(row rowScanner) (*task.Task, error) {
+	t := &task.Task{}
+	var dueDate sql.NullTime
+	var parentID, sourceNoteID sql.NullInt64
+	if err := row.Scan(&t.ID, &t.ProjectID, &parentID, &sourceNoteID, &t.Title, &t.Description, &t.Status, &t.Priority, &dueDate, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if dueDate.Valid {
+		t.DueDate = &dueDate.Time
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		t.ParentID = &id
+	}
+	if sourceNoteID.Valid {
+		id := int(sourceNoteID.Int64)
+		t.SourceNoteID = &id
+	}
+	return t, nil
+}
+
 type taskRepository struct {
 	db *sql.DB
 }
@@ -35,15 +63,23 @@ func (r *taskRepository) Close() error {
 
 func (r *taskRepository) Create(t *task.Task) error {
 	query := `
-		INSERT INTO tasks (project_id, title, description, status, priority, due_date, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO tasks (project_id, parent_id, source_note_id, title, description, status, priority, due_date, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	var dueDate interface{}
 	if t.DueDate != nil {
 		dueDate = t.DueDate
 	}
+	var parentID interface{}
+	if t.ParentID != nil {
+		parentID = *t.ParentID
+	}
+	var sourceNoteID interface{}
+	if t.SourceNoteID != nil {
+		sourceNoteID = *t.SourceNoteID
+	}
 
-	result, err := r.db.Exec(query, t.ProjectID, t.Title, t.Description, t.Status, t.Priority, dueDate, t.CreatedAt, t.UpdatedAt)
+	result, err := r.db.Exec(query, t.ProjectID, parentID, sourceNoteID, t.Title, t.Description, t.Status, t.Priority, dueDate, t.CreatedAt, t.UpdatedAt)
 	if err != nil {
 		return err
 	}
@@ -58,13 +94,9 @@ func (r *taskRepository) Create(t *task.Task) error {
 }
 
 func (r *taskRepository) GetByID(id int) (*task.Task, error) {
-	query := `SELECT id, project_id, title, description, status, priority, due_date, created_at, updated_at FROM tasks WHERE id = ?`
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = ?`
 	
-	t := &task.Task{}
-	var dueDate sql.NullTime
-	err := r.db.QueryRow(query, id).Scan(
-		&t.ID, &t.ProjectID, &t.Title, &t.Description, &t.Status, &t.Priority, &dueDate, &t.CreatedAt, &t.UpdatedAt,
-	)
+	t, err := scanTask(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrTaskNotFound
@@ -72,10 +104,6 @@ func (r *taskRepository) GetByID(id int) (*task.Task, error) {
 		return nil, err
 	}
 
-	if dueDate.Valid {
-		t.DueDate = &dueDate.Time
-	}
-
 	return t, nil
 }
 
@@ -84,11 +112,11 @@ func (r *taskRepository) GetByProjectID(projectID int, status string) ([]*task.T
 	var args []interface{}
 
 	if status != "" {
-		query = `SELECT id, project_id, title, description, status, priority, due_date, created_at, updated_at 
+		query = `SELECT ` + taskColumns + ` 
 			FROM tasks WHERE project_id = ? AND status = ? ORDER BY created_at DESC`
 		args = []interface{}{projectID, status}
 	} else {
-		query = `SELECT id, project_id, title, description, status, priority, due_date, created_at, updated_at 
+		query = `SELECT ` + taskColumns + ` 
 			FROM tasks WHERE project_id = ? ORDER BY created_at DESC`
 		args = []interface{}{projectID}
 	}
@@ -99,21 +127,7 @@ func (r *taskRepository) GetByProjectID(projectID int, status string) ([]*task.T
 	}
 	defer rows.Close()
 
-	var tasks []*task.Task
-	for rows.Next() {
-		t := &task.Task{}
-		var dueDate sql.NullTime
-		err := rows.Scan(&t.ID, &t.ProjectID, &t.Title, &t.Description, &t.Status, &t.Priority, &dueDate, &t.CreatedAt, &t.UpdatedAt)
-		if err != nil {
-			return nil, err
-		}
-		if dueDate.Valid {
-			t.DueDate = &dueDate.Time
-		}
-		tasks = append(tasks, t)
-	}
-
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 func (r *taskRepository) GetAll(status string) ([]*task.Task, error) {
@@ -121,11 +135,11 @@ func (r *taskRepository) GetAll(status string) ([]*task.Task, error) {
 	var args []interface{}
 
 	if status != "" {
-		query = `SELECT id, project_id, title, description, status, priority, due_date, created_at, updated_at 
+		query = `SELECT ` + taskColumns + ` 
 			FROM tasks WHERE status = ? ORDER BY created_at DESC`
 		args = []interface{}{status}
 	} else {
-		query = `SELECT id, project_id, title, description, status, priority, due_date, created_at, updated_at 
+		query = `SELECT ` + taskColumns + ` 
 			FROM tasks ORDER BY created_at DESC`
 	}
 
@@ -135,21 +149,7 @@ func (r *taskRepository) GetAll(status string) ([]*task.Task, error) {
 	}
 	defer rows.Close()
 
-	var tasks []*task.Task
-	for rows.Next() {
-		t := &task.Task{}
-		var dueDate sql.NullTime
-		err := rows.Scan(&t.ID, &t.ProjectID, &t.Title, &t.Description, &t.Status, &t.Priority, &dueDate, &t.CreatedAt, &t.UpdatedAt)
-		if err != nil {
-			return nil, err
-		}
-		if dueDate.Valid {
-			t.DueDate = &dueDate.Time
-		}
-		tasks = append(tasks, t)
-	}
-
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 func (r *taskRepository) Update(id int, title, description, status, priority string, dueDate *time.Time) error {
@@ -162,14 +162,19 @@ func (r *taskRepository) Update(id int, title, description, status, priority str
 	if dueDate != nil {
 		dueDateVal = dueDate
 	}
-	_, err := r.db.Exec(query, title, description, status, priority, dueDateVal, time.Now(), id)
-	return err
+	result, err := r.db.Exec(query, title, description, status, priority, dueDateVal, time.Now(), id)
+	return expectRow(result, err, ErrTaskNotFound)
 }
 
 func (r *taskRepository) Delete(id int) error {
+	// Foreign keys are not enforced, so detach subtasks explicitly.
+	if _, err := r.db.Exec(`UPDATE tasks SET parent_id = NULL WHERE parent_id = ?`, id); err != nil {
+		return err
+	}
+
 	query := `DELETE FROM tasks WHERE id = ?`
-	_, err := r.db.Exec(query, id)
-	return err
+	result, err := r.db.Exec(query, id)
+	return expectRow(result, err, ErrTaskNotFound)
 }
 
 func (r *taskRepository) ToggleComplete(id int) error {
@@ -179,7 +184,32 @@ func (r *taskRepository) ToggleComplete(id int) error {
 		    updated_at = ?
 		WHERE id = ?
 	`
-	_, err := r.db.Exec(query, time.Now(), id)
-	return err
+	result, err := r.db.Exec(query, time.Now(), id)
+	return expectRow(result, err, ErrTaskNotFound)
+}
+
+// GetChildren returns the subtasks of a task in creation order.
+func (r *taskRepository) GetChildren(parentID int) ([]*task.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE parent_id = ? ORDER BY id ASC`
+
+	rows, err := r.db.Query(query, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTasks(rows)
 }
 
+func scanTasks(rows *sql.Rows) ([]*task.Task, error) {
+	var tasks []*task.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, rows.Err()
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/snip/internal/usage"
+)
+
+type UsageRepository interface {
+	Create(u *usage.Usage) error
+	Summarize(since time.Time, groupBy string) ([]*usage.Summary, error)
+	Close() error
+}
+
+type usageRepository struct {
+	db *sql.DB
+}
+
+func NewUsageRepository(db *sql.DB) (UsageRepository, error) {
+	return &usageRepository{db: db}, nil
+}
+
+func (r *usageRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *usageRepository) Create(u *usage.Usage) error {
+	query := `
+		INSERT INTO ai_usage (command, model, prompt_tokens, completion_tokens, latency_ms, status, error, redactions, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, u.Command, u.Model, u.PromptTokens, u.CompletionTokens, u.LatencyMs, u.Status, u.Error, u.Redactions, u.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	u.ID = int(id)
+	return nil
+}
+
+func (r *usageRepository) Summarize(since time.Time, groupBy string) ([]*usage.Summary, error) {
+	var groupColumn string
+	switch groupBy {
+	case "model":
+		groupColumn = "model"
+	case "command":
+		groupColumn = "command"
+	default:
+		return nil, fmt.Errorf("invalid group: %s (use model or command)", groupBy)
+	}
+
+	query := `
+		SELECT ` + groupColumn + `, model, COUNT(*),
+			SUM(CASE WHEN status = 'error' THEN 1 ELSE 0 END),
+			SUM(prompt_tokens), SUM(completion_tokens), AVG(latency_ms),
+			SUM(CASE WHEN redactions <> '' THEN 1 ELSE 0 END)
+		FROM ai_usage
+		WHERE created_at >= ?
+		GROUP BY ` + groupColumn + `, model
+		ORDER BY ` + groupColumn + `, model
+	`
+
+	rows, err := r.db.Query(query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []*usage.Summary
+	for rows.Next() {
+		s := &usage.Summary{}
+		if err := rows.Scan(&s.Group, &s.Model, &s.Calls, &s.Errors, &s.PromptTokens, &s.CompletionTokens, &s.AvgLatencyMs, &s.Redacted); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, s)
+	}
+
+	return summaries, rows.Err()
+}
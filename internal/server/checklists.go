@@ -0,0 +1,268 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/snip/internal/checklist"
+)
+
+// ChecklistInput is the body of POST /checklists. A checklist belongs to a
+// task, a project or neither.
+type ChecklistInput struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	TaskID      *int     `json:"task_id,omitempty"`
+	ProjectID   *int     `json:"project_id,omitempty"`
+	Items       []string `json:"items,omitempty"`
+}
+
+// ChecklistUpdate is the body of PATCH /checklists/{id}.
+type ChecklistUpdate struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// ItemInput is the body of POST /checklists/{id}/items.
+type ItemInput struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// ItemUpdate is the body of PATCH /checklist-items/{id}.
+type ItemUpdate struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Completed   *bool   `json:"completed,omitempty"`
+}
+
+func (s *Server) listChecklists(r *http.Request) (any, error) {
+	taskID, err := queryInt(r, "task_id")
+	if err != nil {
+		return nil, err
+	}
+	projectID, err := queryInt(r, "project_id")
+	if err != nil {
+		return nil, err
+	}
+
+	var checklists []*checklist.Checklist
+	switch {
+	case taskID != 0:
+		checklists, err = s.repos.Checklists.GetByTaskID(taskID)
+	case projectID != 0:
+		checklists, err = s.repos.Checklists.GetByProjectID(projectID)
+	default:
+		checklists, err = s.repos.Checklists.GetAll()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch checklists: %w", err)
+	}
+
+	q := r.URL.Query().Get("q")
+	checklists = filter(checklists, func(c *checklist.Checklist) bool {
+		return (projectID == 0 || c.ProjectID != nil && *c.ProjectID == projectID) && matches(q, c.Title, c.Description)
+	})
+
+	page, err := paginate(r, deref(checklists))
+	if err != nil {
+		return nil, err
+	}
+	// Items are only loaded for the checklists on this page.
+	for i := range page.Items {
+		if err := s.loadItems(&page.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+func (s *Server) getChecklist(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.checklistWithItems(id)
+}
+
+func (s *Server) createChecklist(r *http.Request) (any, error) {
+	var in ChecklistInput
+	if err := decode(r, &in); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(in.Title) == "" {
+		return nil, badRequest("title is required")
+	}
+	if in.TaskID != nil {
+		if _, err := s.repos.Tasks.GetByID(*in.TaskID); err != nil {
+			return nil, err
+		}
+	}
+	if in.ProjectID != nil {
+		if _, err := s.repos.Projects.GetByID(*in.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	c := checklist.NewChecklist(in.Title, in.Description)
+	c.TaskID = in.TaskID
+	c.ProjectID = in.ProjectID
+	if err := s.repos.Checklists.Create(c); err != nil {
+		return nil, fmt.Errorf("failed to create checklist: %w", err)
+	}
+
+	for _, title := range in.Items {
+		if strings.TrimSpace(title) == "" {
+			continue
+		}
+		item := checklist.NewChecklistItem(c.ID, title, "", len(c.Items)+1)
+		if err := s.repos.ChecklistItems.Create(item); err != nil {
+			return nil, fmt.Errorf("failed to create checklist item: %w", err)
+		}
+		c.Items = append(c.Items, *item)
+	}
+	return c, nil
+}
+
+func (s *Server) updateChecklist(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	var in ChecklistUpdate
+	if err := decode(r, &in); err != nil {
+		return nil, err
+	}
+
+	c, err := s.repos.Checklists.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in.Title != nil {
+		if strings.TrimSpace(*in.Title) == "" {
+			return nil, badRequest("title cannot be empty")
+		}
+		c.Title = *in.Title
+	}
+	if in.Description != nil {
+		c.Description = *in.Description
+	}
+
+	if err := s.repos.Checklists.Update(id, c.Title, c.Description); err != nil {
+		return nil, fmt.Errorf("failed to update checklist: %w", err)
+	}
+	return s.checklistWithItems(id)
+}
+
+func (s *Server) deleteChecklist(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.checklistWithItems(id)
+	if err != nil {
+		return nil, err
+	}
+	// Foreign keys are not enforced, so remove the items explicitly.
+	for _, item := range c.Items {
+		if err := s.repos.ChecklistItems.Delete(item.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete checklist item: %w", err)
+		}
+	}
+	if err := s.repos.Checklists.Delete(id); err != nil {
+		return nil, fmt.Errorf("failed to delete checklist: %w", err)
+	}
+	return nil, nil
+}
+
+func (s *Server) addChecklistItem(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	var in ItemInput
+	if err := decode(r, &in); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(in.Title) == "" {
+		return nil, badRequest("title is required")
+	}
+
+	c, err := s.checklistWithItems(id)
+	if err != nil {
+		return nil, err
+	}
+	item := checklist.NewChecklistItem(id, in.Title, in.Description, len(c.Items)+1)
+	if err := s.repos.ChecklistItems.Create(item); err != nil {
+		return nil, fmt.Errorf("failed to create checklist item: %w", err)
+	}
+	return item, nil
+}
+
+func (s *Server) updateChecklistItem(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	var in ItemUpdate
+	if err := decode(r, &in); err != nil {
+		return nil, err
+	}
+
+	item, err := s.repos.ChecklistItems.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in.Title != nil {
+		if strings.TrimSpace(*in.Title) == "" {
+			return nil, badRequest("title cannot be empty")
+		}
+		item.Title = *in.Title
+	}
+	if in.Description != nil {
+		item.Description = *in.Description
+	}
+	if in.Completed != nil {
+		item.Completed = *in.Completed
+	}
+
+	if err := s.repos.ChecklistItems.Update(id, item.Title, item.Description, item.Completed); err != nil {
+		return nil, fmt.Errorf("failed to update checklist item: %w", err)
+	}
+	return s.repos.ChecklistItems.GetByID(id)
+}
+
+func (s *Server) deleteChecklistItem(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repos.ChecklistItems.GetByID(id); err != nil {
+		return nil, err
+	}
+	if err := s.repos.ChecklistItems.Delete(id); err != nil {
+		return nil, fmt.Errorf("failed to delete checklist item: %w", err)
+	}
+	return nil, nil
+}
+
+func (s *Server) checklistWithItems(id int) (*checklist.Checklist, error) {
+	c, err := s.repos.Checklists.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadItems(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *Server) loadItems(c *checklist.Checklist) error {
+	items, err := s.repos.ChecklistItems.GetByChecklistID(c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch checklist items: %w", err)
+	}
+	c.Items = deref(items)
+	return nil
+}
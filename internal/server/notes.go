@@ -0,0 +1,246 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/repository"
+	"github.com/snip/internal/tag"
+	"github.com/snip/internal/validation"
+)
+
+// NoteInput is the body of POST /notes.
+type NoteInput struct {
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Language string   `json:"language,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// NoteUpdate is the body of PATCH /notes/{id}. Tags, when given, replace the
+// note's tags.
+type NoteUpdate struct {
+	Title   *string   `json:"title,omitempty"`
+	Content *string   `json:"content,omitempty"`
+	Tags    *[]string `json:"tags,omitempty"`
+}
+
+// TagInput is the body of POST /tags.
+type TagInput struct {
+	Name string `json:"name"`
+}
+
+func (s *Server) listNotes(r *http.Request) (any, error) {
+	query := r.URL.Query()
+
+	order := query.Get("order")
+	if order != "" && order != "asc" && order != "desc" {
+		return nil, badRequest("invalid order %q, use asc or desc", order)
+	}
+
+	tagID := 0
+	if name := query.Get("tag"); name != "" {
+		t, err := s.repos.Tags.GetByName(name)
+		if errors.Is(err, repository.ErrTagNotFound) {
+			return paginate(r, []note.NoteWithTags{})
+		}
+		if err != nil {
+			return nil, err
+		}
+		tagID = t.ID
+	}
+
+	notes, err := s.repos.Notes.GetAll(order == "asc", tagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notes: %w", err)
+	}
+
+	if q := query.Get("q"); q != "" {
+		found, err := s.repos.Notes.Search(q)
+		if err != nil {
+			return nil, badRequest("invalid search query: %v", err)
+		}
+		ids := make(map[int]bool, len(found))
+		for _, n := range found {
+			ids[n.ID] = true
+		}
+		notes = filter(notes, func(n *note.NoteWithTags) bool { return ids[n.ID] })
+	}
+
+	return paginate(r, deref(notes))
+}
+
+func (s *Server) getNote(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.repos.Notes.GetByID(id)
+}
+
+func (s *Server) createNote(r *http.Request) (any, error) {
+	var in NoteInput
+	if err := decode(r, &in); err != nil {
+		return nil, err
+	}
+	if err := validation.NewValidator().ValidateNote(in.Title); err != nil {
+		return nil, badRequest("%v", err)
+	}
+
+	n := note.NewNote(in.Title, in.Content)
+	n.Language = strings.TrimSpace(in.Language)
+	if err := s.repos.Notes.Create(n); err != nil {
+		return nil, fmt.Errorf("failed to create note: %w", err)
+	}
+
+	tags, err := s.setNoteTags(n.ID, in.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return &note.NoteWithTags{
+		ID:        n.ID,
+		Title:     n.Title,
+		Content:   n.Content,
+		Tags:      tags,
+		Language:  n.Language,
+		CreatedAt: n.CreatedAt,
+		UpdatedAt: n.UpdatedAt,
+	}, nil
+}
+
+func (s *Server) updateNote(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	var in NoteUpdate
+	if err := decode(r, &in); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repos.Notes.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Title != nil || in.Content != nil {
+		title, content := existing.Title, existing.Content
+		if in.Title != nil {
+			if err := validation.NewValidator().ValidateNote(*in.Title); err != nil {
+				return nil, badRequest("%v", err)
+			}
+			title = *in.Title
+		}
+		if in.Content != nil {
+			content = *in.Content
+		}
+		if err := s.repos.Notes.Update(id, content, title); err != nil {
+			return nil, fmt.Errorf("failed to update note: %w", err)
+		}
+	}
+
+	if in.Tags != nil {
+		if err := s.repos.Notes.RemoveTagFromNote(id); err != nil {
+			return nil, fmt.Errorf("failed to update tags: %w", err)
+		}
+		if _, err := s.setNoteTags(id, *in.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repos.Notes.GetByID(id)
+}
+
+func (s *Server) deleteNote(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repos.Notes.CheckByID(id); err != nil {
+		return nil, err
+	}
+	if err := s.repos.Notes.Delete(id); err != nil {
+		return nil, fmt.Errorf("failed to delete note: %w", err)
+	}
+	return nil, nil
+}
+
+// setNoteTags adds names to the note, creating tags that do not exist yet,
+// and returns the cleaned-up names.
+func (s *Server) setNoteTags(noteID int, names []string) ([]string, error) {
+	tags := []string{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(tags, name) {
+			continue
+		}
+		t, err := s.repos.Tags.GetOrCreate(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tag %q: %w", name, err)
+		}
+		if err := s.repos.Notes.AddTagToNote(noteID, t.ID); err != nil {
+			return nil, fmt.Errorf("failed to tag note: %w", err)
+		}
+		tags = append(tags, name)
+	}
+	return tags, nil
+}
+
+func (s *Server) listTags(r *http.Request) (any, error) {
+	tags, err := s.repos.Tags.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tags: %w", err)
+	}
+	q := r.URL.Query().Get("q")
+	tags = filter(tags, func(t *tag.Tag) bool { return matches(q, t.Name) })
+	return paginate(r, deref(tags))
+}
+
+func (s *Server) createTag(r *http.Request) (any, error) {
+	var in TagInput
+	if err := decode(r, &in); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(in.Name)
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return nil, badRequest("name is required and must be a single word")
+	}
+	t, err := s.repos.Tags.GetOrCreate(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	return t, nil
+}
+
+func (s *Server) deleteTag(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.repos.Tags.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tags: %w", err)
+	}
+	if !slices.ContainsFunc(tags, func(t *tag.Tag) bool { return t.ID == id }) {
+		return nil, repository.ErrTagNotFound
+	}
+	if err := s.repos.Tags.Delete(id); err != nil {
+		return nil, fmt.Errorf("failed to delete tag: %w", err)
+	}
+	return nil, nil
+}
+
+// deref copies repository results into a value slice, so an empty result
+// encodes as [] and pages hold plain objects.
+func deref[T any](items []*T) []T {
+	values := make([]T, 0, len(items))
+	for _, item := range items {
+		values = append(values, *item)
+	}
+	return values
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const apiVersion = "1.0.0"
+
+var pathParamPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// OpenAPI builds an OpenAPI 3.0 document for routes. Request and response
+// schemas come from the Go types in each route, read through their json tags:
+// fields tagged omitempty, and pointers, are optional.
+func OpenAPI(routes []Route) map[string]any {
+	schemas := map[string]any{}
+	paths := map[string]any{}
+
+	errorRef := schemaRef(reflect.TypeOf(ErrorResponse{}), schemas)
+	errorResponse := func(description string) map[string]any {
+		return map[string]any{
+			"description": description,
+			"content":     map[string]any{"application/json": map[string]any{"schema": errorRef}},
+		}
+	}
+
+	for _, route := range routes {
+		op := map[string]any{
+			"operationId": route.Name,
+			"summary":     route.Summary,
+			"tags":        []string{route.Tag},
+		}
+
+		var params []any
+		for _, match := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+			params = append(params, map[string]any{
+				"name":     match[1],
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]any{"type": "integer"},
+			})
+		}
+		for _, p := range route.Query {
+			params = append(params, map[string]any{
+				"name":        p.Name,
+				"in":          "query",
+				"description": p.Description,
+				"schema":      map[string]any{"type": p.Type},
+			})
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		if route.Body != nil {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": schemaRef(reflect.TypeOf(route.Body), schemas)}},
+			}
+		}
+
+		responses := map[string]any{}
+		switch {
+		case route.Result != nil:
+			responses[strconv.Itoa(route.status())] = map[string]any{
+				"description": http.StatusText(route.status()),
+				"content":     map[string]any{"application/json": map[string]any{"schema": schemaRef(reflect.TypeOf(route.Result), schemas)}},
+			}
+		case route.Method == http.MethodDelete:
+			responses["204"] = map[string]any{"description": "Deleted"}
+		default:
+			responses["200"] = map[string]any{"description": http.StatusText(http.StatusOK)}
+		}
+		if route.Body != nil || len(route.Query) > 0 || strings.Contains(route.Path, "{") {
+			responses["400"] = errorResponse("Invalid request")
+		}
+		if strings.Contains(route.Path, "{") || route.Method == http.MethodPost {
+			responses["404"] = errorResponse("Not found")
+		}
+		if route.Public {
+			op["security"] = []any{}
+		} else {
+			responses["401"] = errorResponse("Missing or invalid token")
+		}
+		op["responses"] = responses
+
+		item, ok := paths[route.Path].(map[string]any)
+		if !ok {
+			item = map[string]any{}
+			paths[route.Path] = item
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "Snip API",
+			"version":     apiVersion,
+			"description": "Local REST API over the Snip notes, tags, projects, tasks and checklists.",
+		},
+		"servers":  []any{map[string]any{"url": "http://" + DefaultAddr}},
+		"security": []any{map[string]any{"bearerAuth": []string{}}},
+		"paths":    paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaRef returns the schema for t, registering named structs under
+// components/schemas and referring to them by name.
+func schemaRef(t reflect.Type, schemas map[string]any) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Pointer:
+		return schemaRef(t.Elem(), schemas)
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		name := schemaName(t)
+		if _, ok := schemas[name]; !ok {
+			schemas[name] = nil // placeholder, in case the type refers to itself
+			schemas[name] = structSchema(t, schemas)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaRef(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaRef(t.Elem(), schemas)}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	}
+	return map[string]any{}
+}
+
+func structSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	properties := map[string]any{}
+	var required []string
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = schemaRef(field.Type, schemas)
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// schemaName is the type's name, with generic instantiations such as
+// Page[note.NoteWithTags] turned into NoteWithTagsPage.
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	base, args, ok := strings.Cut(name, "[")
+	if !ok {
+		return name
+	}
+	args = strings.TrimSuffix(args, "]")
+	return args[strings.LastIndex(args, ".")+1:] + base
+}
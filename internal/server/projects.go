@@ -0,0 +1,320 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/snip/internal/project"
+	"github.com/snip/internal/task"
+)
+
+var (
+	projectStatuses = []string{"active", "completed", "archived"}
+	taskStatuses    = []string{"pending", "in_progress", "completed"}
+	taskPriorities  = []string{"low", "medium", "high"}
+)
+
+// ProjectInput is the body of POST /projects.
+type ProjectInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// ProjectUpdate is the body of PATCH /projects/{id}.
+type ProjectUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Status      *string `json:"status,omitempty"`
+}
+
+// TaskInput is the body of POST /tasks. DueDate is YYYY-MM-DD or RFC 3339.
+type TaskInput struct {
+	ProjectID   int    `json:"project_id"`
+	ParentID    *int   `json:"parent_id,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+	DueDate     string `json:"due_date,omitempty"`
+}
+
+// TaskUpdate is the body of PATCH /tasks/{id}. An empty DueDate clears it.
+type TaskUpdate struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Status      *string `json:"status,omitempty"`
+	Priority    *string `json:"priority,omitempty"`
+	DueDate     *string `json:"due_date,omitempty"`
+}
+
+func (s *Server) listProjects(r *http.Request) (any, error) {
+	query := r.URL.Query()
+	status := query.Get("status")
+	if err := checkValue("status", status, projectStatuses); err != nil {
+		return nil, err
+	}
+
+	projects, err := s.repos.Projects.GetAll(status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch projects: %w", err)
+	}
+	q := query.Get("q")
+	projects = filter(projects, func(p *project.Project) bool { return matches(q, p.Name, p.Description) })
+	return paginate(r, deref(projects))
+}
+
+func (s *Server) getProject(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.repos.Projects.GetByID(id)
+}
+
+func (s *Server) createProject(r *http.Request) (any, error) {
+	var in ProjectInput
+	if err := decode(r, &in); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(in.Name) == "" {
+		return nil, badRequest("name is required")
+	}
+
+	p := project.NewProject(in.Name, in.Description)
+	if err := s.repos.Projects.Create(p); err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+	return p, nil
+}
+
+func (s *Server) updateProject(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	var in ProjectUpdate
+	if err := decode(r, &in); err != nil {
+		return nil, err
+	}
+
+	p, err := s.repos.Projects.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in.Name != nil {
+		if strings.TrimSpace(*in.Name) == "" {
+			return nil, badRequest("name cannot be empty")
+		}
+		p.Name = *in.Name
+	}
+	if in.Description != nil {
+		p.Description = *in.Description
+	}
+	if in.Status != nil {
+		if err := checkValue("status", *in.Status, projectStatuses); err != nil {
+			return nil, err
+		}
+		p.Status = *in.Status
+	}
+
+	if err := s.repos.Projects.Update(id, p.Name, p.Description, p.Status); err != nil {
+		return nil, fmt.Errorf("failed to update project: %w", err)
+	}
+	return s.repos.Projects.GetByID(id)
+}
+
+func (s *Server) deleteProject(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repos.Projects.GetByID(id); err != nil {
+		return nil, err
+	}
+	if err := s.repos.Projects.Delete(id); err != nil {
+		return nil, fmt.Errorf("failed to delete project: %w", err)
+	}
+	return nil, nil
+}
+
+func (s *Server) listProjectTasks(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	status := r.URL.Query().Get("status")
+	if err := checkValue("status", status, taskStatuses); err != nil {
+		return nil, err
+	}
+	if _, err := s.repos.Projects.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.repos.Tasks.GetByProjectID(id, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+	return paginate(r, deref(tasks))
+}
+
+func (s *Server) listTasks(r *http.Request) (any, error) {
+	query := r.URL.Query()
+	status, priority := query.Get("status"), query.Get("priority")
+	if err := checkValue("status", status, taskStatuses); err != nil {
+		return nil, err
+	}
+	if err := checkValue("priority", priority, taskPriorities); err != nil {
+		return nil, err
+	}
+	projectID, err := queryInt(r, "project_id")
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*task.Task
+	if projectID != 0 {
+		tasks, err = s.repos.Tasks.GetByProjectID(projectID, status)
+	} else {
+		tasks, err = s.repos.Tasks.GetAll(status)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+
+	q := query.Get("q")
+	tasks = filter(tasks, func(t *task.Task) bool {
+		return (priority == "" || t.Priority == priority) && matches(q, t.Title, t.Description)
+	})
+	return paginate(r, deref(tasks))
+}
+
+func (s *Server) getTask(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.repos.Tasks.GetByID(id)
+}
+
+func (s *Server) createTask(r *http.Request) (any, error) {
+	var in TaskInput
+	if err := decode(r, &in); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(in.Title) == "" {
+		return nil, badRequest("title is required")
+	}
+	if in.Priority == "" {
+		in.Priority = "medium"
+	}
+	if err := checkValue("priority", in.Priority, taskPriorities); err != nil {
+		return nil, err
+	}
+	dueDate, err := parseDueDate(in.DueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repos.Projects.GetByID(in.ProjectID); err != nil {
+		return nil, err
+	}
+	if in.ParentID != nil {
+		parent, err := s.repos.Tasks.GetByID(*in.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ProjectID != in.ProjectID {
+			return nil, badRequest("parent task #%d belongs to another project", parent.ID)
+		}
+	}
+
+	t := task.NewTask(in.ProjectID, in.Title, in.Description, in.Priority)
+	t.ParentID = in.ParentID
+	t.DueDate = dueDate
+	if err := s.repos.Tasks.Create(t); err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+	return t, nil
+}
+
+func (s *Server) updateTask(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	var in TaskUpdate
+	if err := decode(r, &in); err != nil {
+		return nil, err
+	}
+
+	t, err := s.repos.Tasks.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in.Title != nil {
+		if strings.TrimSpace(*in.Title) == "" {
+			return nil, badRequest("title cannot be empty")
+		}
+		t.Title = *in.Title
+	}
+	if in.Description != nil {
+		t.Description = *in.Description
+	}
+	if in.Status != nil {
+		if err := checkValue("status", *in.Status, taskStatuses); err != nil {
+			return nil, err
+		}
+		t.Status = *in.Status
+	}
+	if in.Priority != nil {
+		if err := checkValue("priority", *in.Priority, taskPriorities); err != nil {
+			return nil, err
+		}
+		t.Priority = *in.Priority
+	}
+	if in.DueDate != nil {
+		if t.DueDate, err = parseDueDate(*in.DueDate); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repos.Tasks.Update(id, t.Title, t.Description, t.Status, t.Priority, t.DueDate); err != nil {
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+	return s.repos.Tasks.GetByID(id)
+}
+
+func (s *Server) deleteTask(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repos.Tasks.GetByID(id); err != nil {
+		return nil, err
+	}
+	if err := s.repos.Tasks.Delete(id); err != nil {
+		return nil, fmt.Errorf("failed to delete task: %w", err)
+	}
+	return nil, nil
+}
+
+func checkValue(field, value string, allowed []string) error {
+	if value == "" || slices.Contains(allowed, value) {
+		return nil
+	}
+	return badRequest("invalid %s %q, use one of: %s", field, value, strings.Join(allowed, ", "))
+}
+
+func parseDueDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, badRequest("invalid due_date %q, use YYYY-MM-DD or RFC 3339", value)
+}
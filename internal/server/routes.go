@@ -0,0 +1,133 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/snip/internal/checklist"
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/project"
+	"github.com/snip/internal/tag"
+	"github.com/snip/internal/task"
+)
+
+// Route is one endpoint. Path uses net/http pattern syntax, so "{id}" is a
+// path parameter. Body and Result hold zero values of the request and
+// response types; they are only used to describe the endpoint in the
+// OpenAPI document.
+type Route struct {
+	Method  string
+	Path    string
+	Name    string
+	Tag     string
+	Summary string
+	Query   []Param
+	Body    any
+	Result  any
+	Status  int // success status, 200 unless set
+	Public  bool
+
+	handle func(r *http.Request) (any, error)
+}
+
+// Param is a query string parameter.
+type Param struct {
+	Name        string
+	Type        string // string, integer or boolean
+	Description string
+}
+
+func (r Route) status() int {
+	if r.Status == 0 {
+		return http.StatusOK
+	}
+	return r.Status
+}
+
+var pageParams = []Param{
+	{Name: "limit", Type: "integer", Description: "Maximum number of items to return (default 50, max 500)"},
+	{Name: "offset", Type: "integer", Description: "Number of items to skip"},
+}
+
+func listParams(params ...Param) []Param {
+	return append(params, pageParams...)
+}
+
+func (s *Server) routeTable() []Route {
+	return []Route{
+		{Method: "GET", Path: "/health", Name: "health", Tag: "meta", Summary: "Check that the server is up", Result: Health{}, Public: true, handle: s.health},
+		{Method: "GET", Path: "/openapi.json", Name: "openapi", Tag: "meta", Summary: "This API's OpenAPI document", Public: true, handle: s.openAPI},
+
+		{Method: "GET", Path: "/notes", Name: "listNotes", Tag: "notes", Summary: "List notes, newest first",
+			Query: listParams(
+				Param{Name: "q", Type: "string", Description: "Full-text search over title and content"},
+				Param{Name: "tag", Type: "string", Description: "Only notes with this tag"},
+				Param{Name: "order", Type: "string", Description: "asc or desc (default desc)"},
+			),
+			Result: Page[note.NoteWithTags]{}, handle: s.listNotes},
+		{Method: "POST", Path: "/notes", Name: "createNote", Tag: "notes", Summary: "Create a note", Body: NoteInput{}, Result: note.NoteWithTags{}, Status: http.StatusCreated, handle: s.createNote},
+		{Method: "GET", Path: "/notes/{id}", Name: "getNote", Tag: "notes", Summary: "Get a note", Result: note.NoteWithTags{}, handle: s.getNote},
+		{Method: "PATCH", Path: "/notes/{id}", Name: "updateNote", Tag: "notes", Summary: "Update a note; fields left out keep their value", Body: NoteUpdate{}, Result: note.NoteWithTags{}, handle: s.updateNote},
+		{Method: "DELETE", Path: "/notes/{id}", Name: "deleteNote", Tag: "notes", Summary: "Delete a note", handle: s.deleteNote},
+
+		{Method: "GET", Path: "/tags", Name: "listTags", Tag: "tags", Summary: "List tags",
+			Query:  listParams(Param{Name: "q", Type: "string", Description: "Only tags whose name contains this text"}),
+			Result: Page[tag.Tag]{}, handle: s.listTags},
+		{Method: "POST", Path: "/tags", Name: "createTag", Tag: "tags", Summary: "Create a tag, or return it if it exists", Body: TagInput{}, Result: tag.Tag{}, Status: http.StatusCreated, handle: s.createTag},
+		{Method: "DELETE", Path: "/tags/{id}", Name: "deleteTag", Tag: "tags", Summary: "Delete a tag", handle: s.deleteTag},
+
+		{Method: "GET", Path: "/projects", Name: "listProjects", Tag: "projects", Summary: "List projects, newest first",
+			Query: listParams(
+				Param{Name: "q", Type: "string", Description: "Only projects whose name or description contains this text"},
+				Param{Name: "status", Type: "string", Description: "active, completed or archived"},
+			),
+			Result: Page[project.Project]{}, handle: s.listProjects},
+		{Method: "POST", Path: "/projects", Name: "createProject", Tag: "projects", Summary: "Create a project", Body: ProjectInput{}, Result: project.Project{}, Status: http.StatusCreated, handle: s.createProject},
+		{Method: "GET", Path: "/projects/{id}", Name: "getProject", Tag: "projects", Summary: "Get a project", Result: project.Project{}, handle: s.getProject},
+		{Method: "PATCH", Path: "/projects/{id}", Name: "updateProject", Tag: "projects", Summary: "Update a project; fields left out keep their value", Body: ProjectUpdate{}, Result: project.Project{}, handle: s.updateProject},
+		{Method: "DELETE", Path: "/projects/{id}", Name: "deleteProject", Tag: "projects", Summary: "Delete a project", handle: s.deleteProject},
+		{Method: "GET", Path: "/projects/{id}/tasks", Name: "listProjectTasks", Tag: "projects", Summary: "List a project's tasks",
+			Query:  listParams(Param{Name: "status", Type: "string", Description: "pending, in_progress or completed"}),
+			Result: Page[task.Task]{}, handle: s.listProjectTasks},
+
+		{Method: "GET", Path: "/tasks", Name: "listTasks", Tag: "tasks", Summary: "List tasks, newest first",
+			Query: listParams(
+				Param{Name: "q", Type: "string", Description: "Only tasks whose title or description contains this text"},
+				Param{Name: "status", Type: "string", Description: "pending, in_progress or completed"},
+				Param{Name: "priority", Type: "string", Description: "low, medium or high"},
+				Param{Name: "project_id", Type: "integer", Description: "Only tasks of this project"},
+			),
+			Result: Page[task.Task]{}, handle: s.listTasks},
+		{Method: "POST", Path: "/tasks", Name: "createTask", Tag: "tasks", Summary: "Create a task", Body: TaskInput{}, Result: task.Task{}, Status: http.StatusCreated, handle: s.createTask},
+		{Method: "GET", Path: "/tasks/{id}", Name: "getTask", Tag: "tasks", Summary: "Get a task", Result: task.Task{}, handle: s.getTask},
+		{Method: "PATCH", Path: "/tasks/{id}", Name: "updateTask", Tag: "tasks", Summary: "Update a task; fields left out keep their value", Body: TaskUpdate{}, Result: task.Task{}, handle: s.updateTask},
+		{Method: "DELETE", Path: "/tasks/{id}", Name: "deleteTask", Tag: "tasks", Summary: "Delete a task", handle: s.deleteTask},
+
+		{Method: "GET", Path: "/checklists", Name: "listChecklists", Tag: "checklists", Summary: "List checklists, newest first",
+			Query: listParams(
+				Param{Name: "q", Type: "string", Description: "Only checklists whose title or description contains this text"},
+				Param{Name: "task_id", Type: "integer", Description: "Only checklists of this task"},
+				Param{Name: "project_id", Type: "integer", Description: "Only checklists of this project"},
+			),
+			Result: Page[checklist.Checklist]{}, handle: s.listChecklists},
+		{Method: "POST", Path: "/checklists", Name: "createChecklist", Tag: "checklists", Summary: "Create a checklist, optionally with items", Body: ChecklistInput{}, Result: checklist.Checklist{}, Status: http.StatusCreated, handle: s.createChecklist},
+		{Method: "GET", Path: "/checklists/{id}", Name: "getChecklist", Tag: "checklists", Summary: "Get a checklist with its items", Result: checklist.Checklist{}, handle: s.getChecklist},
+		{Method: "PATCH", Path: "/checklists/{id}", Name: "updateChecklist", Tag: "checklists", Summary: "Update a checklist; fields left out keep their value", Body: ChecklistUpdate{}, Result: checklist.Checklist{}, handle: s.updateChecklist},
+		{Method: "DELETE", Path: "/checklists/{id}", Name: "deleteChecklist", Tag: "checklists", Summary: "Delete a checklist and its items", handle: s.deleteChecklist},
+		{Method: "POST", Path: "/checklists/{id}/items", Name: "addChecklistItem", Tag: "checklists", Summary: "Add an item to the end of a checklist", Body: ItemInput{}, Result: checklist.ChecklistItem{}, Status: http.StatusCreated, handle: s.addChecklistItem},
+		{Method: "PATCH", Path: "/checklist-items/{id}", Name: "updateChecklistItem", Tag: "checklists", Summary: "Update a checklist item; fields left out keep their value", Body: ItemUpdate{}, Result: checklist.ChecklistItem{}, handle: s.updateChecklistItem},
+		{Method: "DELETE", Path: "/checklist-items/{id}", Name: "deleteChecklistItem", Tag: "checklists", Summary: "Delete a checklist item", handle: s.deleteChecklistItem},
+	}
+}
+
+// Health is the body of GET /health.
+type Health struct {
+	Status string `json:"status"`
+}
+
+func (s *Server) health(r *http.Request) (any, error) {
+	return Health{Status: "ok"}, nil
+}
+
+func (s *Server) openAPI(r *http.Request) (any, error) {
+	return OpenAPI(s.routes), nil
+}
@@ -0,0 +1,262 @@
+// Package server exposes the Snip repositories as a local JSON REST API for
+// editor plugins and dashboards. Routes are declared once, in a table that
+// both registers the handlers and generates the OpenAPI document.
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/snip/internal/repository"
+)
+
+const (
+	// DefaultAddr is where snip serve listens unless told otherwise. It is
+	// loopback only: the API is meant for tools running on the same machine.
+	DefaultAddr = "127.0.0.1:7777"
+
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+
+	maxBodyBytes = 1 << 20
+)
+
+// Repositories are the stores the API reads from and writes to.
+type Repositories struct {
+	Notes          repository.NoteRepository
+	Tags           repository.TagRepository
+	Projects       repository.ProjectRepository
+	Tasks          repository.TaskRepository
+	Checklists     repository.ChecklistRepository
+	ChecklistItems repository.ChecklistItemRepository
+}
+
+type Server struct {
+	repos       Repositories
+	token       string
+	allowOrigin string
+	routes      []Route
+}
+
+// New returns a server over repos. Every route except the public ones
+// requires the header "Authorization: Bearer <token>"; an empty token turns
+// authentication off.
+func New(repos Repositories, token string) *Server {
+	s := &Server{repos: repos, token: token}
+	s.routes = s.routeTable()
+	return s
+}
+
+// AllowOrigin lets browser pages served from origin call the API, for
+// dashboards that are not served by Snip itself.
+func (s *Server) AllowOrigin(origin string) {
+	s.allowOrigin = origin
+}
+
+// Routes returns the route table, in declaration order.
+func (s *Server) Routes() []Route {
+	return s.routes
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, route := range s.routes {
+		mux.Handle(route.Method+" "+route.Path, s.wrap(route))
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions && s.allowOrigin != "" {
+			s.writeCORS(w)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeError(w, &apiError{status: http.StatusNotFound, message: "no route for " + r.Method + " " + r.URL.Path})
+	})
+	return mux
+}
+
+func (s *Server) wrap(route Route) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.allowOrigin != "" {
+			s.writeCORS(w)
+		}
+		if !route.Public && !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, &apiError{status: http.StatusUnauthorized, message: "missing or invalid token"})
+			return
+		}
+
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+		}
+
+		result, err := route.handle(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if result == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, route.status(), result)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(given)), []byte(s.token)) == 1
+}
+
+func (s *Server) writeCORS(w http.ResponseWriter) {
+	w.Header().Set("Access-Control-Allow-Origin", s.allowOrigin)
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE, OPTIONS")
+}
+
+// ErrorResponse is the body of every failed request.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...any) error {
+	return &apiError{status: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+var notFoundErrors = []error{
+	repository.ErrNoteNotFound,
+	repository.ErrTagNotFound,
+	repository.ErrProjectNotFound,
+	repository.ErrTaskNotFound,
+	repository.ErrChecklistNotFound,
+	repository.ErrChecklistItemNotFound,
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		status = apiErr.status
+	} else {
+		for _, target := range notFoundErrors {
+			if errors.Is(err, target) {
+				status = http.StatusNotFound
+				break
+			}
+		}
+	}
+	writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// decode reads a JSON body into v, rejecting unknown fields so typos in a
+// client show up as errors instead of being silently ignored.
+func decode(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return badRequest("invalid request body: %v", err)
+	}
+	return nil
+}
+
+func pathID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		return 0, badRequest("invalid id %q", r.PathValue("id"))
+	}
+	return id, nil
+}
+
+func queryInt(r *http.Request, name string) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, badRequest("invalid %s %q", name, value)
+	}
+	return n, nil
+}
+
+// Page is one slice of a list endpoint's results. Total counts every match,
+// not just the ones in Items.
+type Page[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+func paginate[T any](r *http.Request, items []T) (*Page[T], error) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	offset, err := queryInt(r, "offset")
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page[T]{Items: []T{}, Total: len(items), Limit: limit, Offset: offset}
+	if offset < len(items) {
+		page.Items = items[offset:min(offset+limit, len(items))]
+	}
+	return page, nil
+}
+
+// matches reports whether any of fields contains q, ignoring case.
+func matches(q string, fields ...string) bool {
+	if q == "" {
+		return true
+	}
+	q = strings.ToLower(q)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), q) {
+			return true
+		}
+	}
+	return false
+}
+
+func filter[T any](items []T, keep func(T) bool) []T {
+	var kept []T
+	for _, item := range items {
+		if keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
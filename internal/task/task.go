@@ -5,6 +5,8 @@ import "time"
 type Task struct {
 	ID          int       `json:"id"`
 	ProjectID   int       `json:"project_id"`
+	ParentID    *int      `json:"parent_id,omitempty"` // nil para tarefas de primeiro nível
+	SourceNoteID *int      `json:"source_note_id,omitempty"` // nota de onde a tarefa foi extraída
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	Status      string    `json:"status"` // pending, in_progress, completed
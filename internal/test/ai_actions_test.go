@@ -0,0 +1,155 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/handler"
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/project"
+	"github.com/snip/internal/task"
+)
+
+func TestParseActionItems(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		expectError bool
+		errorMsg    string
+		items       int
+	}{
+		{
+			name: "valid items",
+			raw: `{"items": [
+				{"title": " Enviar proposta ", "owner": "Ana", "due": "2026-10-23"},
+				{"title": "Revisar contrato", "owner": "", "due": ""}
+			]}`,
+			items: 2,
+		},
+		{
+			name:  "no action items",
+			raw:   `{"items": []}`,
+			items: 0,
+		},
+		{
+			name:        "item without title",
+			raw:         `{"items": [{"title": "  ", "owner": "Ana"}]}`,
+			expectError: true,
+			errorMsg:    "has no title",
+		},
+		{
+			name:        "relative due date",
+			raw:         `{"items": [{"title": "Enviar proposta", "due": "next friday"}]}`,
+			expectError: true,
+			errorMsg:    "invalid due date",
+		},
+		{
+			name:        "unknown field",
+			raw:         `{"items": [{"title": "Enviar proposta", "priority": "high"}]}`,
+			expectError: true,
+			errorMsg:    "unknown field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ai.ParseActionItems(tt.raw)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+					return
+				}
+				if !errors.Is(err, ai.ErrInvalidActionItems) {
+					t.Errorf("Expected ErrInvalidActionItems, got %v", err)
+				}
+				if tt.errorMsg != "" && !contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.errorMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(items.Items) != tt.items {
+				t.Fatalf("Expected %d items, got %d", tt.items, len(items.Items))
+			}
+		})
+	}
+}
+
+func TestActionItemDueDate(t *testing.T) {
+	items, err := ai.ParseActionItems(`{"items": [{"title": "Enviar proposta", "due": "2026-10-23"}, {"title": "Revisar contrato"}]}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	due := items.Items[0].DueDate()
+	if due == nil || due.Format("2006-01-02") != "2026-10-23" {
+		t.Errorf("Expected due date 2026-10-23, got %v", due)
+	}
+	if items.Items[0].Title != "Enviar proposta" {
+		t.Errorf("Expected trimmed title, got %q", items.Items[0].Title)
+	}
+	if items.Items[1].DueDate() != nil {
+		t.Errorf("Expected no due date, got %v", items.Items[1].DueDate())
+	}
+}
+
+func TestActionItemHandler(t *testing.T) {
+	t.Run("invalid note ID", func(t *testing.T) {
+		h, _, _, _ := createTestActionItemHandler()
+
+		err := h.ExtractActionItems("abc", 1, false)
+		if err == nil || !strings.Contains(err.Error(), "invalid note ID") {
+			t.Errorf("expected invalid note ID error, got %v", err)
+		}
+	})
+
+	t.Run("missing project", func(t *testing.T) {
+		h, _, _, _ := createTestActionItemHandler()
+
+		err := h.ExtractActionItems("1", 0, false)
+		if err == nil || !strings.Contains(err.Error(), "--project is required") {
+			t.Errorf("expected project required error, got %v", err)
+		}
+	})
+
+	t.Run("no AI client", func(t *testing.T) {
+		t.Setenv("GROQ_API_KEY", "")
+		h, _, _, mockTaskRepo := createTestActionItemHandler()
+
+		err := h.ExtractActionItems("1", 1, true)
+		if err == nil || !strings.Contains(err.Error(), "AI client not available") {
+			t.Errorf("expected AI client error, got %v", err)
+		}
+		if len(mockTaskRepo.tasks) != 0 {
+			t.Errorf("expected no tasks, got %d", len(mockTaskRepo.tasks))
+		}
+	})
+	t.Run("existing items cannot be selected", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		newFakeGroq(t, fakeReply{content: `{"items": [{"title": "Enviar proposta"}, {"title": "Revisar contrato"}]}`})
+		handler.SetPromptInput(strings.NewReader("1\n2\n"))
+		t.Cleanup(func() { handler.SetPromptInput(os.Stdin) })
+
+		h, mockNoteRepo, mockProjectRepo, mockTaskRepo := createTestActionItemHandler()
+		mockNoteRepo.notesWithTags = []*note.NoteWithTags{{ID: 1, Title: "Reunião", Content: "Ana envia a proposta, Bruno revisa o contrato"}}
+		mockProjectRepo.projects = []*project.Project{{ID: 1, Name: "Vendas"}}
+		noteID := 1
+		existing := task.NewTask(1, "Enviar proposta", "", "medium")
+		existing.SourceNoteID = &noteID
+		mockTaskRepo.Create(existing)
+
+		if err := h.ExtractActionItems("1", 1, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(mockTaskRepo.tasks) != 2 || mockTaskRepo.tasks[1].Title != "Revisar contrato" {
+			t.Errorf("expected only the new item to become a task, got %d task(s)", len(mockTaskRepo.tasks))
+		}
+	})
+}
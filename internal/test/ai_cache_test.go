@@ -0,0 +1,126 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/cache"
+)
+
+func TestCacheKey(t *testing.T) {
+	base := ai.ChatRequest{
+		Model:       "openai/gpt-oss-120b",
+		Messages:    []ai.Message{{Role: "user", Content: "reverse a string in go"}},
+		MaxTokens:   2000,
+		Temperature: 0.3,
+	}
+	key := ai.CacheKey(base)
+
+	streamed := base
+	streamed.Stream = true
+	if ai.CacheKey(streamed) != key {
+		t.Error("Expected streamed and non-streamed requests to share a key")
+	}
+
+	tests := []struct {
+		name   string
+		modify func(r *ai.ChatRequest)
+	}{
+		{name: "model", modify: func(r *ai.ChatRequest) { r.Model = "llama-3.1-8b-instant" }},
+		{name: "temperature", modify: func(r *ai.ChatRequest) { r.Temperature = 0.7 }},
+		{name: "messages", modify: func(r *ai.ChatRequest) {
+			r.Messages = []ai.Message{{Role: "user", Content: "reverse a string in python"}}
+		}},
+		{name: "json mode", modify: func(r *ai.ChatRequest) { r.ResponseFormat = &ai.ResponseFormat{Type: "json_object"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := base
+			tt.modify(&changed)
+			if ai.CacheKey(changed) == key {
+				t.Errorf("Expected a different key when the %s changes", tt.name)
+			}
+		})
+	}
+}
+
+func TestCacheTTLConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected time.Duration
+	}{
+		{name: "default", expected: ai.DefaultCacheTTLHours * time.Hour},
+		{name: "custom", config: `{"cache_ttl_hours": 2}`, expected: 2 * time.Hour},
+		{name: "disabled", config: `{"cache_ttl_hours": -1}`, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			if tt.config != "" {
+				writeFile(t, filepath.Join(home, ".snip", "config.json"), tt.config)
+			}
+
+			cfg, err := ai.LoadConfig()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg.CacheTTL() != tt.expected {
+				t.Errorf("Expected TTL %s, got %s", tt.expected, cfg.CacheTTL())
+			}
+		})
+	}
+}
+
+func TestClearCache(t *testing.T) {
+	tests := []struct {
+		name        string
+		expiredOnly bool
+		remaining   int
+	}{
+		{name: "everything", expiredOnly: false, remaining: 0},
+		{name: "expired only", expiredOnly: true, remaining: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			h, _, mockCacheRepo := createTestAIHandlerWithCache()
+
+			fresh := cache.NewEntry("fresh", "openai/gpt-oss-120b", "answer")
+			expired := cache.NewEntry("expired", "openai/gpt-oss-120b", "old answer")
+			expired.CreatedAt = time.Now().Add(-(ai.DefaultCacheTTLHours + 1) * time.Hour)
+			mockCacheRepo.Put(fresh)
+			mockCacheRepo.Put(expired)
+
+			if err := h.CacheStats(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err := h.ClearCache(tt.expiredOnly); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(mockCacheRepo.entries) != tt.remaining {
+				t.Errorf("Expected %d cached entries left, got %d", tt.remaining, len(mockCacheRepo.entries))
+			}
+			if tt.expiredOnly && mockCacheRepo.entries["fresh"] == nil {
+				t.Error("Expected the fresh entry to be kept")
+			}
+		})
+	}
+}
+
+func TestCacheStatsError(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	h, _, mockCacheRepo := createTestAIHandlerWithCache()
+	mockCacheRepo.err = ErrDatabaseConnection
+
+	err := h.CacheStats()
+	if err == nil || !contains(err.Error(), "failed to read AI cache") {
+		t.Errorf("Expected cache read error, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/snip/internal/chat"
+)
+
+func TestChat(t *testing.T) {
+	t.Run("no AI client", func(t *testing.T) {
+		t.Setenv("GROQ_API_KEY", "")
+		h, _ := createTestChatHandler()
+
+		err := h.Chat(0)
+		if err == nil || !strings.Contains(err.Error(), "AI client not available") {
+			t.Errorf("expected AI client error, got %v", err)
+		}
+	})
+
+	t.Run("session not found", func(t *testing.T) {
+		t.Setenv("GROQ_API_KEY", "test-key")
+		t.Setenv("HOME", t.TempDir())
+		h, _ := createTestChatHandler()
+
+		err := h.Chat(3)
+		if err == nil || !strings.Contains(err.Error(), "chat session not found") {
+			t.Errorf("expected session not found error, got %v", err)
+		}
+	})
+}
+
+func TestListChatSessions(t *testing.T) {
+	t.Run("lists sessions", func(t *testing.T) {
+		h, mockChatRepo := createTestChatHandler()
+		mockChatRepo.sessions = []*chat.Session{chat.NewSession("deploy questions"), chat.NewSession("")}
+
+		if err := h.ListSessions(20); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		h, mockChatRepo := createTestChatHandler()
+		mockChatRepo.err = errors.New("database error")
+
+		err := h.ListSessions(20)
+		if err == nil || !strings.Contains(err.Error(), "failed to fetch chat sessions") {
+			t.Errorf("expected fetch error, got %v", err)
+		}
+	})
+}
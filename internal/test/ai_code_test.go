@@ -0,0 +1,124 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/snip/internal/ai"
+)
+
+const codeAnswer = "Here is the function:\n\n```go\nfunc Reverse(s string) string {\n\treturn s\n}\n```\n\nAnd a test:\n\n```bash\ngo test ./...\n```\n"
+
+func TestExtractCodeBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		expected []ai.CodeBlock
+	}{
+		{
+			name:     "several blocks",
+			markdown: codeAnswer,
+			expected: []ai.CodeBlock{
+				{Language: "go", Code: "func Reverse(s string) string {\n\treturn s\n}"},
+				{Language: "bash", Code: "go test ./..."},
+			},
+		},
+		{
+			name:     "tildes and no language",
+			markdown: "~~~\nSELECT 1;\n~~~",
+			expected: []ai.CodeBlock{{Code: "SELECT 1;"}},
+		},
+		{
+			name:     "longer fence around a fence",
+			markdown: "````markdown\n```go\nx := 1\n```\n````",
+			expected: []ai.CodeBlock{{Language: "markdown", Code: "```go\nx := 1\n```"}},
+		},
+		{
+			name:     "unclosed block",
+			markdown: "```Python\nprint('hi')\n",
+			expected: []ai.CodeBlock{{Language: "python", Code: "print('hi')"}},
+		},
+		{
+			name:     "no blocks",
+			markdown: "Just prose with `inline` code.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ai.ExtractCodeBlocks(tt.markdown)
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSelectCodeBlocks(t *testing.T) {
+	blocks := ai.ExtractCodeBlocks(codeAnswer)
+
+	if got := ai.SelectCodeBlocks(blocks, "golang", "main.go"); len(got) != 1 || got[0].Language != "go" {
+		t.Errorf("Expected only the go block, got %+v", got)
+	}
+	if got := ai.SelectCodeBlocks(blocks, "go", "deploy.sh"); len(got) != 2 {
+		t.Errorf("Expected the blocks matching the language or the extension, got %+v", got)
+	}
+	if got := ai.SelectCodeBlocks(blocks, "rust", "lib.rs"); len(got) != 2 {
+		t.Errorf("Expected every block when none matches, got %+v", got)
+	}
+}
+
+func TestGenerateCodeWithAISaveAndOut(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fake := newFakeGroq(t, fakeReply{content: codeAnswer})
+	h, mockNoteRepo, mockTagRepo := createTestHandler()
+
+	out := filepath.Join(t.TempDir(), "reverse.go")
+	tag := "strings"
+	if err := h.GenerateCodeWithAI("Golang", "reverse a string", "", false, true, &tag, out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(fake.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(fake.requests))
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Expected the code to be written: %v", err)
+	}
+	if string(data) != "func Reverse(s string) string {\n\treturn s\n}\n" {
+		t.Errorf("Expected only the go block in the file, got %q", data)
+	}
+
+	if len(mockNoteRepo.notes) != 1 {
+		t.Fatalf("Expected 1 note, got %d", len(mockNoteRepo.notes))
+	}
+	saved := mockNoteRepo.notes[0]
+	if saved.Title != "reverse a string" || saved.Content != codeAnswer || saved.Language != "go" {
+		t.Errorf("Expected the answer saved as a go snippet, got %+v", saved)
+	}
+	if !slices.Equal(mockTagRepo.requested, []string{"snippet", "go", "strings"}) {
+		t.Errorf("Expected tags snippet, go and strings, got %v", mockTagRepo.requested)
+	}
+}
+
+func TestGenerateCodeWithAIOutWithoutCode(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	newFakeGroq(t, fakeReply{content: "I cannot help with that."})
+	h, mockNoteRepo, _ := createTestHandler()
+
+	out := filepath.Join(t.TempDir(), "main.go")
+	err := h.GenerateCodeWithAI("go", "something", "", true, false, nil, out)
+	if err == nil || !contains(err.Error(), "no code block found") {
+		t.Errorf("Expected no code block error, got %v", err)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("Expected no file to be written, got %v", err)
+	}
+	if len(mockNoteRepo.notes) != 0 {
+		t.Errorf("Expected no note without --save, got %d", len(mockNoteRepo.notes))
+	}
+}
@@ -0,0 +1,184 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/note"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected int
+	}{
+		{name: "empty", text: "", expected: 0},
+		{name: "short words", text: "go is fun", expected: 3},
+		{name: "long word", text: "concurrency", expected: 3},
+		{name: "punctuation", text: "a, b.", expected: 4},
+		{name: "code", text: "x := f(y)", expected: 7},
+		{name: "accents count as letters", text: "ação", expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ai.EstimateTokens(tt.text); got != tt.expected {
+				t.Errorf("Expected %d tokens for %q, got %d", tt.expected, tt.text, got)
+			}
+		})
+	}
+}
+
+func TestTruncateTokens(t *testing.T) {
+	text := strings.Repeat("word ", 1000)
+
+	if got := ai.TruncateTokens("short text", 100); got != "short text" {
+		t.Errorf("Expected text within the limit to be unchanged, got %q", got)
+	}
+
+	got := ai.TruncateTokens(text, 100)
+	if tokens := ai.EstimateTokens(got); tokens > 100 {
+		t.Errorf("Expected at most 100 tokens, got %d", tokens)
+	}
+	if !strings.HasSuffix(got, "[...]") {
+		t.Errorf("Expected the cut to be marked, got %q", got)
+	}
+
+	for _, chunk := range ai.ChunkTokens(strings.Repeat("{}()[];", 500), 50) {
+		if tokens := ai.EstimateTokens(chunk); tokens > 50 {
+			t.Errorf("Expected dense chunks of at most 50 tokens, got %d", tokens)
+		}
+	}
+}
+
+func TestPackContext(t *testing.T) {
+	docs := []ai.ContextDocument{
+		{Title: "Groceries", Content: "Milk, bread and eggs."},
+		{Title: "Deploy", Content: "Run the deploy script after the tests pass."},
+		{Title: "Kubernetes", Content: strings.Repeat("Pods and services. ", 400) + "\n\nThe deploy uses a rolling update."},
+	}
+
+	t.Run("relevant notes first", func(t *testing.T) {
+		packed, omitted := ai.PackContext("how does the deploy work?", docs, 10000)
+		if omitted != 0 {
+			t.Errorf("Expected every note to fit, got %d omitted", omitted)
+		}
+		if len(packed) != 3 || !strings.HasPrefix(packed[1], "Deploy: Run the deploy script") {
+			t.Errorf("Expected notes in their original order, got %q", packed)
+		}
+	})
+
+	t.Run("budget keeps the best chunks", func(t *testing.T) {
+		packed, omitted := ai.PackContext("deploy rolling update", docs, 500)
+
+		total := 0
+		for _, p := range packed {
+			total += ai.EstimateTokens(p)
+		}
+		if total > 500 {
+			t.Errorf("Expected at most 500 tokens, got %d", total)
+		}
+		if omitted != 0 {
+			t.Errorf("Expected part of every note to fit, got %d omitted", omitted)
+		}
+
+		long := packed[len(packed)-1]
+		if !strings.HasPrefix(long, "Kubernetes: ") || !strings.HasSuffix(long, "The deploy uses a rolling update.") {
+			t.Errorf("Expected the matching chunk of the long note, got %q", long)
+		}
+		if !strings.Contains(long, "[...]") {
+			t.Errorf("Expected the skipped chunks to be marked, got %q", long)
+		}
+	})
+
+	t.Run("notes that do not fit are counted", func(t *testing.T) {
+		packed, omitted := ai.PackContext("milk", docs, 20)
+		if len(packed) != 1 || !strings.HasPrefix(packed[0], "Groceries: ") || omitted != 2 {
+			t.Errorf("Expected only the matching note, got %q and %d omitted", packed, omitted)
+		}
+	})
+}
+
+func TestContextBudgetConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeFile(t, filepath.Join(home, ".snip", "config.json"),
+		`{"context_budgets": {"openai/gpt-oss-120b": 60000, "local-model": 2000}}`)
+
+	cfg, err := ai.LoadConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		model    string
+		expected int
+	}{
+		{model: "openai/gpt-oss-120b", expected: 60000},
+		{model: "local-model", expected: 2000},
+		{model: "llama-3.1-8b-instant", expected: ai.DefaultContextBudgets["llama-3.1-8b-instant"]},
+		{model: "unknown", expected: ai.DefaultContextBudget},
+	}
+	for _, tt := range tests {
+		if got := cfg.ContextBudget(tt.model); got != tt.expected {
+			t.Errorf("Expected budget %d for %s, got %d", tt.expected, tt.model, got)
+		}
+	}
+}
+
+func TestAskAIContextBudget(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SNIP_AI_MODEL", "small-model")
+	t.Setenv("SNIP_AI_LANGUAGE", "en")
+	writeFile(t, filepath.Join(os.Getenv("HOME"), ".snip", "config.json"), `{"context_budgets": {"small-model": 1200}}`)
+	fake := newFakeGroq(t, fakeReply{content: "Use the backup script."})
+	h, mockNoteRepo, _ := createTestHandler()
+
+	for i := 1; i <= 20; i++ {
+		mockNoteRepo.notesWithTags = append(mockNoteRepo.notesWithTags, &note.NoteWithTags{
+			ID: i, Title: fmt.Sprintf("Note %d", i), Content: strings.Repeat("Unrelated filler text. ", 40),
+		})
+	}
+	mockNoteRepo.notesWithTags = append(mockNoteRepo.notesWithTags, &note.NoteWithTags{
+		ID: 21, Title: "Backups", Content: "Run the backup script every night.",
+	})
+
+	if err := h.AskAI("how do I run the backup?"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := fake.requests[0]
+	if tokens := ai.EstimateMessagesTokens(req.Messages); tokens > 1200 {
+		t.Errorf("Expected the prompt to fit the 1200 token budget, got ~%d", tokens)
+	}
+	prompt := fake.lastPrompt(0)
+	if !contains(prompt, "Backups: Run the backup script every night.") {
+		t.Errorf("Expected the relevant note in the prompt, got %q", prompt)
+	}
+	if !contains(prompt, "more notes") {
+		t.Errorf("Expected the left-out notes to be counted, got %q", prompt)
+	}
+}
+
+func TestPromptOverBudget(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SNIP_AI_MODEL", "small-model")
+	t.Setenv("SNIP_AI_LANGUAGE", "en")
+	writeFile(t, filepath.Join(os.Getenv("HOME"), ".snip", "config.json"), `{"context_budgets": {"small-model": 100}}`)
+	fake := newFakeGroq(t)
+	h, _, _ := createTestHandler()
+
+	err := h.AskAI(strings.Repeat("very long question ", 100))
+	if !errors.Is(err, ai.ErrContextTooLong) {
+		t.Fatalf("Expected %v, got %v", ai.ErrContextTooLong, err)
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("Expected no request to be sent, got %d", len(fake.requests))
+	}
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/snip/internal/diff"
+)
+
+func TestDiffUnified(t *testing.T) {
+	t.Run("identical texts have no hunks", func(t *testing.T) {
+		if hunks := diff.Unified("a\nb\n", "a\nb", 3); len(hunks) != 0 {
+			t.Errorf("expected no hunks, got %d", len(hunks))
+		}
+	})
+
+	t.Run("single change with context", func(t *testing.T) {
+		oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9"
+		newText := "1\n2\n3\n4\nfive\n6\n7\n8\n9"
+
+		hunks := diff.Unified(oldText, newText, 2)
+		if len(hunks) != 1 {
+			t.Fatalf("expected 1 hunk, got %d", len(hunks))
+		}
+		if got := hunks[0].Header(); got != "@@ -3,5 +3,5 @@" {
+			t.Errorf("expected header @@ -3,5 +3,5 @@, got %s", got)
+		}
+
+		var rendered []string
+		for _, l := range hunks[0].Lines {
+			prefix := " "
+			switch l.Op {
+			case diff.Insert:
+				prefix = "+"
+			case diff.Delete:
+				prefix = "-"
+			}
+			rendered = append(rendered, prefix+l.Text)
+		}
+		expected := " 3\n 4\n-5\n+five\n 6\n 7"
+		if got := strings.Join(rendered, "\n"); got != expected {
+			t.Errorf("unexpected hunk:\n%s\nexpected:\n%s", got, expected)
+		}
+	})
+
+	t.Run("distant changes are separate hunks", func(t *testing.T) {
+		oldText := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj"
+		newText := "A\nb\nc\nd\ne\nf\ng\nh\ni\nJ"
+
+		hunks := diff.Unified(oldText, newText, 1)
+		if len(hunks) != 2 {
+			t.Fatalf("expected 2 hunks, got %d", len(hunks))
+		}
+		if got := hunks[1].Header(); got != "@@ -9,2 +9,2 @@" {
+			t.Errorf("expected second header @@ -9,2 +9,2 @@, got %s", got)
+		}
+	})
+
+	t.Run("insertions into an empty note", func(t *testing.T) {
+		hunks := diff.Unified("", "# Runbook\nstep one", 3)
+		if len(hunks) != 1 {
+			t.Fatalf("expected 1 hunk, got %d", len(hunks))
+		}
+		if got := hunks[0].Header(); got != "@@ -0,0 +1,2 @@" {
+			t.Errorf("expected header @@ -0,0 +1,2 @@, got %s", got)
+		}
+
+		added, removed := diff.Stats(hunks)
+		if added != 2 || removed != 0 {
+			t.Errorf("expected 2 added and 0 removed, got %d and %d", added, removed)
+		}
+	})
+}
+
+func TestAIEditNote(t *testing.T) {
+	t.Run("invalid note ID", func(t *testing.T) {
+		h, _, _ := createTestHandler()
+
+		err := h.AIEditNote("abc", "make this a runbook", false)
+		if err == nil || !strings.Contains(err.Error(), "invalid note ID") {
+			t.Errorf("expected invalid note ID error, got %v", err)
+		}
+	})
+
+	t.Run("empty instruction", func(t *testing.T) {
+		h, _, _ := createTestHandler()
+
+		err := h.AIEditNote("1", "  ", false)
+		if err == nil || !strings.Contains(err.Error(), "instruction cannot be empty") {
+			t.Errorf("expected empty instruction error, got %v", err)
+		}
+	})
+
+	t.Run("no AI client", func(t *testing.T) {
+		t.Setenv("GROQ_API_KEY", "")
+		h, _, _ := createTestHandler()
+
+		err := h.AIEditNote("1", "make this a runbook", false)
+		if err == nil || !strings.Contains(err.Error(), "AI client not available") {
+			t.Errorf("expected AI client error, got %v", err)
+		}
+	})
+}
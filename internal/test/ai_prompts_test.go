@@ -0,0 +1,117 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/snip/internal/ai"
+)
+
+func TestPromptLanguagesMatch(t *testing.T) {
+	pt, err := ai.PromptNames("pt")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	en, err := ai.PromptNames("en")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if strings.Join(pt, ",") != strings.Join(en, ",") {
+		t.Errorf("Expected the same prompts in every language, got pt=%v en=%v", pt, en)
+	}
+}
+
+func TestPromptLanguageConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		env         string
+		expected    string
+		expectError bool
+	}{
+		{name: "default", expected: "pt"},
+		{name: "from config", config: `{"language": "EN"}`, expected: "en"},
+		{name: "environment wins", config: `{"language": "en"}`, env: "pt", expected: "pt"},
+		{name: "unsupported", config: `{"language": "es"}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("SNIP_AI_LANGUAGE", tt.env)
+			if tt.config != "" {
+				writeFile(t, filepath.Join(home, ".snip", "config.json"), tt.config)
+			}
+
+			cfg, err := ai.LoadConfig()
+			if tt.expectError {
+				if err == nil || !contains(err.Error(), "invalid language") {
+					t.Errorf("Expected invalid language error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg.Language != tt.expected {
+				t.Errorf("Expected language %s, got %s", tt.expected, cfg.Language)
+			}
+		})
+	}
+}
+
+func TestPromptOverrides(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".snip", "prompts")
+
+	writeFile(t, filepath.Join(dir, "en", "tips.tmpl"), `{{define "user"}}Tips about {{.Topic}}{{end}}`)
+	writeFile(t, filepath.Join(dir, "code.tmpl"), `{{define "user"}}{{.Language`)
+
+	infos, err := ai.DescribePrompts("en")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sources := make(map[string]ai.PromptInfo)
+	for _, info := range infos {
+		sources[info.Name] = info
+	}
+
+	if info := sources["tips"]; info.Source != ai.PromptSourceOverride || info.Err != nil {
+		t.Errorf("Expected a valid tips override, got %+v", info)
+	}
+	if info := sources["code"]; info.Source != ai.PromptSourceOverride || info.Err == nil {
+		t.Errorf("Expected an invalid code override, got %+v", info)
+	}
+	if info := sources["answer"]; info.Source != ai.PromptSourceDefault {
+		t.Errorf("Expected the default answer prompt, got %+v", info)
+	}
+}
+
+func TestExportPrompts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".snip", "prompts", "pt")
+
+	writeFile(t, filepath.Join(dir, "tips.tmpl"), "custom")
+
+	written, skipped, err := ai.ExportPrompts("pt")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	names, _ := ai.PromptNames("pt")
+	if len(written) != len(names)-1 || len(skipped) != 1 {
+		t.Errorf("Expected %d written and 1 skipped, got %d and %d", len(names)-1, len(written), len(skipped))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "tips.tmpl"))
+	if err != nil || string(data) != "custom" {
+		t.Errorf("Expected the existing override to be kept, got %q (%v)", data, err)
+	}
+}
@@ -0,0 +1,252 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/note"
+)
+
+func TestCreateNoteWithAI(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream bool
+	}{
+		{name: "complete response", stream: false},
+		{name: "streamed response", stream: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			fake := newFakeGroq(t, fakeReply{content: "# Goroutines\n\nLightweight threads managed by the Go runtime."})
+			h, mockNoteRepo, _ := createTestHandler()
+
+			tag := "go"
+			if err := h.CreateNoteWithAI("Goroutines", "for beginners", &tag, tt.stream); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(fake.requests) != 1 {
+				t.Fatalf("Expected 1 request, got %d", len(fake.requests))
+			}
+			if fake.requests[0].Stream != tt.stream {
+				t.Errorf("Expected stream=%v, got %v", tt.stream, fake.requests[0].Stream)
+			}
+			prompt := fake.lastPrompt(0)
+			if !contains(prompt, "Goroutines") || !contains(prompt, "for beginners") {
+				t.Errorf("Expected the topic and context in the prompt, got %q", prompt)
+			}
+
+			if len(mockNoteRepo.notes) != 1 {
+				t.Fatalf("Expected 1 note, got %d", len(mockNoteRepo.notes))
+			}
+			if got := mockNoteRepo.notes[0].Content; got != "# Goroutines\n\nLightweight threads managed by the Go runtime." {
+				t.Errorf("Expected the AI answer as content, got %q", got)
+			}
+		})
+	}
+}
+
+func TestAskAIPrompt(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	writeFile(t, filepath.Join(os.Getenv("HOME"), ".snip", "config.json"), `{"exclude_tags": ["private"]}`)
+	fake := newFakeGroq(t, fakeReply{content: "Use `go test ./...`."})
+	h, mockNoteRepo, _ := createTestHandler()
+	mockNoteRepo.notesWithTags = []*note.NoteWithTags{
+		{ID: 1, Title: "Testing", Content: "Run go test ./... before pushing", Tags: []string{"go"}},
+		{ID: 2, Title: "Salary", Content: "Confidential numbers", Tags: []string{"private"}},
+	}
+
+	if err := h.AskAI("how do I run the tests?"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(fake.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(fake.requests))
+	}
+	prompt := fake.lastPrompt(0)
+	if !contains(prompt, "how do I run the tests?") {
+		t.Errorf("Expected the question in the prompt, got %q", prompt)
+	}
+	if !contains(prompt, "Testing: Run go test ./... before pushing") {
+		t.Errorf("Expected the note as context, got %q", prompt)
+	}
+	if contains(prompt, "Confidential") {
+		t.Errorf("Expected the excluded note to be left out, got %q", prompt)
+	}
+}
+
+func TestCreateChecklistWithAI(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fake := newFakeGroq(t, fakeReply{content: "1. Revisar o código\n- Rodar os testes\n\nok\n* Atualizar o changelog\n• Publicar a versão"})
+	h, mockChecklistRepo, mockChecklistItemRepo := createTestChecklistHandler()
+
+	projectID := 3
+	if err := h.CreateChecklistWithAI("Release", "versão 1.2", 3, nil, &projectID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	prompt := fake.lastPrompt(0)
+	if !contains(prompt, "Release") || !contains(prompt, "versão 1.2") || !contains(prompt, "3") {
+		t.Errorf("Expected topic, context and item count in the prompt, got %q", prompt)
+	}
+
+	if len(mockChecklistRepo.checklists) != 1 || *mockChecklistRepo.checklists[0].ProjectID != projectID {
+		t.Fatalf("Expected 1 checklist for project %d, got %+v", projectID, mockChecklistRepo.checklists)
+	}
+
+	expected := []string{"Revisar o código", "Rodar os testes", "Atualizar o changelog"}
+	if len(mockChecklistItemRepo.items) != len(expected) {
+		t.Fatalf("Expected %d items, got %d", len(expected), len(mockChecklistItemRepo.items))
+	}
+	for i, item := range mockChecklistItemRepo.items {
+		if item.Title != expected[i] {
+			t.Errorf("Expected item %d to be %q, got %q", i+1, expected[i], item.Title)
+		}
+	}
+}
+
+func TestCreateProjectWithAI(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fake := newFakeGroq(t, fakeReply{content: `{"summary": "Blog pessoal em Go", "phases": [
+		{"name": "Setup", "tasks": [
+			{"title": "Criar repositório", "priority": "high", "due_in_days": 1, "checklist": ["git init", "README"]},
+			{"title": "Escolher tema"}
+		]}
+	]}`})
+	h, mockProjectRepo, mockTaskRepo, mockChecklistRepo, mockChecklistItemRepo := createTestProjectHandler()
+
+	if err := h.CreateProjectWithAI("Blog", "", true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := fake.requests[0]
+	if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_object" {
+		t.Errorf("Expected JSON mode, got %+v", req.ResponseFormat)
+	}
+	if !contains(fake.lastPrompt(0), "Blog") {
+		t.Errorf("Expected the project name in the prompt, got %q", fake.lastPrompt(0))
+	}
+
+	if len(mockProjectRepo.projects) != 1 || mockProjectRepo.projects[0].Description != "Blog pessoal em Go" {
+		t.Fatalf("Expected the project with the plan summary, got %+v", mockProjectRepo.projects)
+	}
+	if len(mockTaskRepo.tasks) != 2 {
+		t.Errorf("Expected 2 tasks, got %d", len(mockTaskRepo.tasks))
+	}
+	if len(mockChecklistRepo.checklists) != 1 || len(mockChecklistItemRepo.items) != 2 {
+		t.Errorf("Expected 1 checklist with 2 items, got %d and %d", len(mockChecklistRepo.checklists), len(mockChecklistItemRepo.items))
+	}
+}
+
+func TestAIErrorMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    fakeReply
+		expected error
+		hint     string
+	}{
+		{
+			name:     "invalid key",
+			reply:    fakeReply{status: 401, body: `{"error": {"message": "Invalid API Key", "type": "invalid_request_error", "code": "invalid_api_key"}}`},
+			expected: ai.ErrAuth,
+			hint:     "GROQ_API_KEY",
+		},
+		{
+			name: "rate limited",
+			reply: fakeReply{
+				status: 429,
+				body:   `{"error": {"message": "Rate limit reached", "type": "tokens", "code": "rate_limit_exceeded"}}`,
+				header: map[string]string{"Retry-After": "20"},
+			},
+			expected: ai.ErrRateLimited,
+			hint:     "try again in 20s",
+		},
+		{
+			name:     "context too long",
+			reply:    fakeReply{status: 400, body: `{"error": {"message": "Please reduce the length of the messages", "type": "invalid_request_error", "code": "context_length_exceeded"}}`},
+			expected: ai.ErrContextTooLong,
+			hint:     "too long",
+		},
+		{
+			name:     "provider down",
+			reply:    fakeReply{status: 503, body: `{"error": {"message": "Service Unavailable"}}`},
+			expected: ai.ErrUnavailable,
+			hint:     "try again later",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			newFakeGroq(t, tt.reply)
+			h, _, _ := createTestHandler()
+
+			err := h.AskAI("anything")
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, err)
+			}
+			if !contains(err.Error(), tt.hint) {
+				t.Errorf("Expected hint containing %q, got %q", tt.hint, err.Error())
+			}
+		})
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	fixtures := filepath.Join(home, "fixtures")
+
+	// Record against the fake server.
+	fake := newFakeGroq(t, fakeReply{content: "- Comprar pão\n- Comprar leite"})
+	t.Setenv("SNIP_AI_RECORD", fixtures)
+	h, _, recorded := createTestChecklistHandler()
+	if err := h.CreateChecklistWithAI("Mercado", "", 2, nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fake.server.Close()
+
+	// Replay without network or API key.
+	t.Setenv("SNIP_AI_RECORD", "")
+	t.Setenv("SNIP_AI_REPLAY", fixtures)
+	t.Setenv("GROQ_API_KEY", "")
+	h, _, replayed := createTestChecklistHandler()
+	if err := h.CreateChecklistWithAI("Mercado", "", 2, nil, nil); err != nil {
+		t.Fatalf("Unexpected error on replay: %v", err)
+	}
+	if len(replayed.items) != len(recorded.items) || replayed.items[1].Title != "Comprar leite" {
+		t.Errorf("Expected the recorded items, got %+v", replayed.items)
+	}
+
+	// A request that was never recorded fails instead of reaching the network.
+	err := h.CreateChecklistWithAI("Farmácia", "", 2, nil, nil)
+	if err == nil || !contains(err.Error(), "no recorded response") {
+		t.Errorf("Expected a replay miss, got %v", err)
+	}
+}
+
+func TestReplayFixturesFromDisk(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GROQ_API_KEY", "")
+	t.Setenv("SNIP_AI_REPLAY", filepath.Join("testdata", "fixtures"))
+	h, _, mockChecklistItemRepo := createTestChecklistHandler()
+
+	if err := h.CreateChecklistWithAI("Deploy", "aplicação Go", 3, nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"Rodar os testes", "Gerar o binário", "Publicar a release"}
+	if len(mockChecklistItemRepo.items) != len(expected) {
+		t.Fatalf("Expected %d items, got %d", len(expected), len(mockChecklistItemRepo.items))
+	}
+	for i, item := range mockChecklistItemRepo.items {
+		if item.Title != expected[i] {
+			t.Errorf("Expected item %d to be %q, got %q", i+1, expected[i], item.Title)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/snip/internal/ai"
+)
+
+func TestChatRetries(t *testing.T) {
+	unavailable := fakeReply{status: 503, body: `{"error": {"message": "over capacity"}}`}
+	rateLimited := func(wait string) fakeReply {
+		return fakeReply{status: 429, body: `{"error": {"message": "rate limit reached"}}`, header: map[string]string{"Retry-After": wait}}
+	}
+	ok := fakeReply{content: "done"}
+
+	tests := []struct {
+		name        string
+		maxAttempts string
+		replies     []fakeReply
+		timeout     time.Duration
+		requests    int
+		minElapsed  time.Duration
+		maxElapsed  time.Duration
+		expectedErr error
+	}{
+		{
+			name:        "503 then 200 succeeds after a backoff",
+			maxAttempts: "3",
+			replies:     []fakeReply{unavailable, ok},
+			requests:    2,
+			minElapsed:  250 * time.Millisecond,
+			maxElapsed:  2 * time.Second,
+		},
+		{
+			name:        "Retry-After is honoured",
+			maxAttempts: "3",
+			replies:     []fakeReply{rateLimited("0.3"), ok},
+			requests:    2,
+			minElapsed:  300 * time.Millisecond,
+			maxElapsed:  2 * time.Second,
+		},
+		{
+			name:        "rate limit reset header is honoured",
+			maxAttempts: "3",
+			replies: []fakeReply{
+				{status: 429, body: `{"error": {"message": "rate limit reached"}}`, header: map[string]string{"x-ratelimit-remaining-tokens": "0", "x-ratelimit-reset-tokens": "300ms"}},
+				ok,
+			},
+			requests:   2,
+			minElapsed: 300 * time.Millisecond,
+			maxElapsed: 2 * time.Second,
+		},
+		{
+			name:        "Retry-After over the limit returns immediately",
+			maxAttempts: "3",
+			replies:     []fakeReply{rateLimited("120"), ok},
+			requests:    1,
+			maxElapsed:  time.Second,
+			expectedErr: ai.ErrRateLimited,
+		},
+		{
+			name:        "max attempts is respected",
+			maxAttempts: "2",
+			replies:     []fakeReply{rateLimited("0.01"), rateLimited("0.01"), ok},
+			requests:    2,
+			maxElapsed:  time.Second,
+			expectedErr: ai.ErrRateLimited,
+		},
+		{
+			name:        "client errors are not retried",
+			maxAttempts: "3",
+			replies:     []fakeReply{{status: 401, body: `{"error": {"message": "invalid api key"}}`}, ok},
+			requests:    1,
+			maxElapsed:  time.Second,
+			expectedErr: ai.ErrAuth,
+		},
+		{
+			name:        "cancelling during the wait returns the context error",
+			maxAttempts: "3",
+			replies:     []fakeReply{rateLimited("30"), ok},
+			timeout:     200 * time.Millisecond,
+			requests:    1,
+			maxElapsed:  time.Second,
+			expectedErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			fake := newFakeGroq(t, tt.replies...)
+			t.Setenv("SNIP_AI_MAX_ATTEMPTS", tt.maxAttempts)
+
+			client, err := ai.NewGroqClient()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+			got, err := client.ChatContext(ctx, []ai.Message{{Role: "user", Content: "hello"}}, 50, 0)
+			elapsed := time.Since(start)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+				}
+			} else if err != nil || got != "done" {
+				t.Errorf("Expected the answer after retrying, got %q, %v", got, err)
+			}
+			if len(fake.requests) != tt.requests {
+				t.Errorf("Expected %d request(s), got %d", tt.requests, len(fake.requests))
+			}
+			if elapsed < tt.minElapsed || elapsed > tt.maxElapsed {
+				t.Errorf("Expected to take between %s and %s, took %s", tt.minElapsed, tt.maxElapsed, elapsed)
+			}
+		})
+	}
+}
+
+func TestInvalidConfigDisablesAI(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	writeFile(t, filepath.Join(os.Getenv("HOME"), ".snip", "config.json"), `{"max_attempts": "three"}`)
+	newFakeGroq(t)
+
+	if _, err := ai.NewGroqClient(); err == nil {
+		t.Fatal("Expected an error for an invalid config file")
+	}
+
+	h, _, _ := createTestHandler()
+	err := h.AskAI("what did we decide?")
+	if err == nil || !contains(err.Error(), "AI client not available") || !contains(err.Error(), "config.json") {
+		t.Errorf("Expected the config error to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/usage"
+)
+
+func TestChatStream(t *testing.T) {
+	usageChunk := `{"choices": [], "x_groq": {"usage": {"prompt_tokens": 42, "completion_tokens": 7, "total_tokens": 49}}}`
+
+	tests := []struct {
+		name         string
+		reply        fakeReply
+		cancelAfter  int // cancel the context after this many tokens
+		expected     string
+		expectedErr  string
+		status       string
+		promptTokens int // 0 means estimated from the messages
+	}{
+		{
+			name:         "CRLF line endings",
+			reply:        fakeReply{body: "data: " + streamChunk("Hello ") + "\r\n\r\ndata: " + streamChunk("world") + "\r\n\r\ndata: " + usageChunk + "\r\n\r\ndata: [DONE]\r\n\r\n"},
+			expected:     "Hello world",
+			status:       usage.StatusOK,
+			promptTokens: 42,
+		},
+		{
+			name:         "comment lines are ignored",
+			reply:        fakeReply{body: ": keep-alive\n\ndata: " + streamChunk("Hello ") + "\n\n: ping\n\ndata:" + streamChunk("world") + "\n\ndata: " + usageChunk + "\n\ndata: [DONE]\n\n"},
+			expected:     "Hello world",
+			status:       usage.StatusOK,
+			promptTokens: 42,
+		},
+		{
+			name:     "missing usage chunk is estimated",
+			reply:    fakeReply{body: "data: " + streamChunk("Hello ") + "\n\ndata: " + streamChunk("world") + "\n\ndata: [DONE]\n\n"},
+			expected: "Hello world",
+			status:   usage.StatusOK,
+		},
+		{
+			name:     "stream without [DONE] ends at EOF",
+			reply:    fakeReply{body: "data: " + streamChunk("Hello ") + "\n\ndata: " + streamChunk("world") + "\n\n"},
+			expected: "Hello world",
+			status:   usage.StatusOK,
+		},
+		{
+			name:        "error object mid-stream",
+			reply:       fakeReply{body: "data: " + streamChunk("Hello ") + "\n\ndata: {\"error\": {\"message\": \"model overloaded\", \"type\": \"server_error\"}}\n\n"},
+			expected:    "Hello ",
+			expectedErr: "model overloaded",
+			status:      usage.StatusError,
+		},
+		{
+			name:        "error status before the stream",
+			reply:       fakeReply{status: 401, body: `{"error": {"message": "invalid api key", "type": "invalid_request_error"}}`},
+			expectedErr: "invalid api key",
+			status:      usage.StatusError,
+		},
+		{
+			name:        "cancellation returns partial text",
+			reply:       fakeReply{body: "data: " + streamChunk("Hello ") + "\n\n", hang: true},
+			cancelAfter: 1,
+			expected:    "Hello ",
+			expectedErr: context.Canceled.Error(),
+			status:      usage.StatusCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			newFakeGroq(t, tt.reply)
+			ledger := &mockUsageRepository{}
+			ai.SetUsageLedger(ledger)
+			t.Cleanup(func() { ai.SetUsageLedger(nil) })
+
+			client, err := ai.NewGroqClient()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var tokens []string
+			messages := []ai.Message{{Role: "user", Content: "Say hello to the world"}}
+			got, err := client.ChatStream(ctx, messages, 100, 0.2, func(token string) {
+				tokens = append(tokens, token)
+				if len(tokens) == tt.cancelAfter {
+					cancel()
+				}
+			})
+
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectedErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected || strings.Join(tokens, "") != tt.expected {
+				t.Errorf("Expected %q streamed and returned, got %q and tokens %q", tt.expected, got, tokens)
+			}
+			if tt.cancelAfter > 0 && !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected context.Canceled, got %v", err)
+			}
+
+			if len(ledger.created) != 1 {
+				t.Fatalf("Expected 1 usage row, got %d", len(ledger.created))
+			}
+			row := ledger.created[0]
+			if row.Status != tt.status {
+				t.Errorf("Expected status %q, got %q", tt.status, row.Status)
+			}
+			promptTokens, completionTokens := tt.promptTokens, 7
+			if promptTokens == 0 {
+				promptTokens, completionTokens = ai.EstimateMessagesTokens(messages), ai.EstimateTokens(tt.expected)
+			}
+			if row.PromptTokens != promptTokens || row.CompletionTokens != completionTokens {
+				t.Errorf("Expected %d/%d tokens, got %d/%d", promptTokens, completionTokens, row.PromptTokens, row.CompletionTokens)
+			}
+		})
+	}
+}
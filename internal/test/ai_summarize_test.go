@@ -0,0 +1,102 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/project"
+	"github.com/snip/internal/repository"
+)
+
+func TestChunkText(t *testing.T) {
+	t.Run("short text is one chunk", func(t *testing.T) {
+		chunks := ai.ChunkText("  hello world  ", 100)
+		if len(chunks) != 1 || chunks[0] != "hello world" {
+			t.Errorf("expected [\"hello world\"], got %q", chunks)
+		}
+	})
+
+	t.Run("empty text has no chunks", func(t *testing.T) {
+		if chunks := ai.ChunkText(" \n ", 100); len(chunks) != 0 {
+			t.Errorf("expected no chunks, got %q", chunks)
+		}
+	})
+
+	t.Run("splits on paragraphs", func(t *testing.T) {
+		paragraph := strings.Repeat("word ", 15)
+		text := strings.Join([]string{paragraph, paragraph, paragraph, paragraph}, "\n\n")
+
+		chunks := ai.ChunkText(text, 160)
+		if len(chunks) < 2 {
+			t.Fatalf("expected several chunks, got %d", len(chunks))
+		}
+		for i, c := range chunks {
+			if len(c) > 160 {
+				t.Errorf("chunk %d has %d chars, expected at most 160", i, len(c))
+			}
+			if !strings.HasSuffix(c, "word") {
+				t.Errorf("chunk %d was not split at a word boundary: %q", i, c)
+			}
+		}
+	})
+
+	t.Run("does not split multi-byte runes", func(t *testing.T) {
+		text := strings.Repeat("ção", 50)
+
+		chunks := ai.ChunkText(text, 31)
+		if strings.Join(chunks, "") != text {
+			t.Error("expected chunks to cover the whole text")
+		}
+		for i, c := range chunks {
+			if len(c) > 31 {
+				t.Errorf("chunk %d has %d bytes, expected at most 31", i, len(c))
+			}
+			if !utf8.ValidString(c) {
+				t.Errorf("chunk %d is not valid UTF-8: %q", i, c)
+			}
+		}
+	})
+}
+
+func TestSummaryHandler(t *testing.T) {
+	t.Run("invalid note ID", func(t *testing.T) {
+		h, _, _, _, _ := createTestSummaryHandler()
+
+		err := h.SummarizeNote("abc", false)
+		if err == nil || !strings.Contains(err.Error(), "invalid note ID") {
+			t.Errorf("expected invalid note ID error, got %v", err)
+		}
+	})
+
+	t.Run("tag not found", func(t *testing.T) {
+		h, _, mockTagRepo, _, _ := createTestSummaryHandler()
+		mockTagRepo.err = repository.ErrTagNotFound
+
+		err := h.SummarizeTag("missing", false)
+		if err == nil || !strings.Contains(err.Error(), "no notes found for tag: missing") {
+			t.Errorf("expected no notes found error, got %v", err)
+		}
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		h, _, _, _, _ := createTestSummaryHandler()
+
+		err := h.SummarizeProject(7, false)
+		if err == nil || !strings.Contains(err.Error(), "project not found") {
+			t.Errorf("expected project not found error, got %v", err)
+		}
+	})
+
+	t.Run("no AI client", func(t *testing.T) {
+		t.Setenv("GROQ_API_KEY", "")
+		h, _, _, mockProjectRepo, _ := createTestSummaryHandler()
+		mockProjectRepo.projects = []*project.Project{{ID: 1, Name: "Website", Status: "active"}}
+
+		err := h.SummarizeProject(1, false)
+		if err == nil || !strings.Contains(err.Error(), "AI client not available") {
+			t.Errorf("expected AI client error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/snip/internal/ai"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := map[string]string{
+		"Go":               "go",
+		"#devops":          "devops",
+		"Machine Learning": "machine-learning",
+		"  c++ ":           "c++",
+		"ci/cd":            "ci-cd",
+		"node.js":          "node.js",
+		"---":              "",
+		"Configuração":     "configuração",
+	}
+
+	for input, expected := range tests {
+		if got := ai.NormalizeTag(input); got != expected {
+			t.Errorf("NormalizeTag(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestParseTagSuggestion(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		expectError bool
+		errorMsg    string
+		tags        []string
+		title       string
+	}{
+		{
+			name:  "tags and title",
+			raw:   `{"tags": ["Go", "testing", "go", "Unit Tests"], "title": "Table-driven tests in Go"}`,
+			tags:  []string{"go", "testing", "unit-tests"},
+			title: "Table-driven tests in Go",
+		},
+		{
+			name:  "tags only",
+			raw:   `{"tags": ["meeting"], "title": ""}`,
+			tags:  []string{"meeting"},
+			title: "",
+		},
+		{
+			name:  "too many tags are trimmed",
+			raw:   `{"tags": ["a1", "b2", "c3", "d4", "e5", "f6"], "title": ""}`,
+			tags:  []string{"a1", "b2", "c3", "d4"},
+			title: "",
+		},
+		{
+			name:        "empty suggestion",
+			raw:         `{"tags": [], "title": ""}`,
+			expectError: true,
+			errorMsg:    "no tags or title",
+		},
+		{
+			name:        "unknown field",
+			raw:         `{"tags": ["go"], "category": "dev"}`,
+			expectError: true,
+			errorMsg:    "unknown field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion, err := ai.ParseTagSuggestion(tt.raw, ai.DefaultMaxTags)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+					return
+				}
+				if !errors.Is(err, ai.ErrInvalidTagSuggestion) {
+					t.Errorf("Expected ErrInvalidTagSuggestion, got %v", err)
+				}
+				if tt.errorMsg != "" && !contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.errorMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if len(suggestion.Tags) != len(tt.tags) {
+				t.Fatalf("Expected tags %q, got %q", tt.tags, suggestion.Tags)
+			}
+			for i := range tt.tags {
+				if suggestion.Tags[i] != tt.tags[i] {
+					t.Errorf("Expected tags %q, got %q", tt.tags, suggestion.Tags)
+					break
+				}
+			}
+			if suggestion.Title != tt.title {
+				t.Errorf("Expected title %q, got %q", tt.title, suggestion.Title)
+			}
+		})
+	}
+}
+
+func TestAITagNotes_NoAIClient(t *testing.T) {
+	t.Setenv("GROQ_API_KEY", "")
+	h, _, _ := createTestHandler()
+
+	err := h.AITagNotes("1", false, true)
+	if err == nil || !contains(err.Error(), "AI client not available") {
+		t.Errorf("Expected 'AI client not available' error, got %v", err)
+	}
+}
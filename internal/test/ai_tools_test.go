@@ -0,0 +1,185 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/snip/internal/action"
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/handler"
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/task"
+)
+
+func toolCall(name, arguments string) ai.ToolCall {
+	return ai.ToolCall{
+		ID:       "call_1",
+		Type:     "function",
+		Function: ai.ToolCallFunction{Name: name, Arguments: arguments},
+	}
+}
+
+func TestToolRunnerTools(t *testing.T) {
+	r, _, _, _ := createTestToolRunner()
+
+	expected := []string{"search_notes", "list_projects", "list_tasks", "create_task", "update_task_status", "toggle_checklist_item"}
+	tools := r.Tools()
+	if len(tools) != len(expected) {
+		t.Fatalf("expected %d tools, got %d", len(expected), len(tools))
+	}
+	for i, tool := range tools {
+		if tool.Type != "function" || tool.Function.Name != expected[i] {
+			t.Errorf("tool %d: expected function %s, got %s %s", i, expected[i], tool.Type, tool.Function.Name)
+		}
+		if !json.Valid(tool.Function.Parameters) {
+			t.Errorf("tool %s has invalid parameters schema", tool.Function.Name)
+		}
+	}
+}
+
+func TestToolRunnerRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		call      ai.ToolCall
+		setupMock func(*mockNoteRepository, *mockTaskRepository)
+		status    string
+		contains  string
+	}{
+		{
+			name: "search notes",
+			call: toolCall("search_notes", `{"query": "deploy"}`),
+			setupMock: func(noteRepo *mockNoteRepository, taskRepo *mockTaskRepository) {
+				noteRepo.notesWithTags = []*note.NoteWithTags{
+					{ID: 7, Title: "Deploy steps", Content: "run make deploy"},
+					{ID: 8, Title: "Groceries", Content: "milk"},
+				}
+			},
+			status:   action.StatusExecuted,
+			contains: `"title":"Deploy steps"`,
+		},
+		{
+			name: "list tasks",
+			call: toolCall("list_tasks", `{"project_id": 2}`),
+			setupMock: func(noteRepo *mockNoteRepository, taskRepo *mockTaskRepository) {
+				taskRepo.tasks = []*task.Task{
+					{ID: 1, ProjectID: 2, Title: "Write docs", Status: "pending", Priority: "high"},
+					{ID: 2, ProjectID: 3, Title: "Other project", Status: "pending", Priority: "low"},
+				}
+			},
+			status:   action.StatusExecuted,
+			contains: `"title":"Write docs"`,
+		},
+		{
+			name:     "unknown tool",
+			call:     toolCall("delete_everything", `{}`),
+			status:   action.StatusFailed,
+			contains: "unknown tool: delete_everything",
+		},
+		{
+			name:     "invalid arguments",
+			call:     toolCall("search_notes", `{"query": 3}`),
+			status:   action.StatusFailed,
+			contains: "invalid arguments",
+		},
+		{
+			name:     "invalid task status",
+			call:     toolCall("update_task_status", `{"task_id": 1, "status": "done"}`),
+			status:   action.StatusFailed,
+			contains: "invalid status: done",
+		},
+		{
+			name:     "task not found",
+			call:     toolCall("update_task_status", `{"task_id": 9, "status": "completed"}`),
+			status:   action.StatusFailed,
+			contains: "task not found",
+		},
+		{
+			name:     "create task in missing project",
+			call:     toolCall("create_task", `{"project_id": 4, "title": "Ship it"}`),
+			status:   action.StatusFailed,
+			contains: "project not found",
+		},
+		{
+			name:     "checklist item not found",
+			call:     toolCall("toggle_checklist_item", `{"item_id": 5}`),
+			status:   action.StatusFailed,
+			contains: "checklist item not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mockNoteRepo, mockTaskRepo, mockActionRepo := createTestToolRunner()
+			if tt.setupMock != nil {
+				tt.setupMock(mockNoteRepo, mockTaskRepo)
+			}
+
+			sessionID := 3
+			result := r.Run(tt.call, &sessionID)
+			if !strings.Contains(result, tt.contains) {
+				t.Errorf("expected result to contain %q, got %s", tt.contains, result)
+			}
+
+			if len(mockActionRepo.actions) != 1 {
+				t.Fatalf("expected 1 logged action, got %d", len(mockActionRepo.actions))
+			}
+			logged := mockActionRepo.actions[0]
+			if logged.Status != tt.status {
+				t.Errorf("expected status %s, got %s", tt.status, logged.Status)
+			}
+			if logged.Tool != tt.call.Function.Name || logged.SessionID == nil || *logged.SessionID != 3 {
+				t.Errorf("unexpected logged action: %+v", logged)
+			}
+			if logged.Result != result {
+				t.Errorf("expected logged result to match the returned result")
+			}
+		})
+	}
+}
+
+func TestToolRunnerSnippet(t *testing.T) {
+	r, mockNoteRepo, _, _ := createTestToolRunner()
+	// 59 words of 5 runes, then a token that straddles the 300 rune limit.
+	content := strings.Repeat("ação ", 59) + "ghp_" + strings.Repeat("x", 36)
+	mockNoteRepo.notesWithTags = []*note.NoteWithTags{{ID: 1, Title: "Configuração", Content: content}}
+
+	result := r.Run(toolCall("search_notes", `{"query": "ação"}`), nil)
+	var found struct {
+		Result []struct {
+			Snippet string `json:"snippet"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(result), &found); err != nil || len(found.Result) != 1 {
+		t.Fatalf("expected one search result, got %s (%v)", result, err)
+	}
+	snippet := found.Result[0].Snippet
+	if !utf8.ValidString(snippet) || strings.ContainsRune(snippet, utf8.RuneError) {
+		t.Errorf("expected the snippet to be cut on a rune, got %q", snippet)
+	}
+	if strings.Contains(snippet, "ghp_") || snippet != strings.TrimSpace(strings.Repeat("ação ", 59))+"..." {
+		t.Errorf("expected the snippet to stop before the cut token, got %q", snippet)
+	}
+}
+
+func TestCreateTaskReturnsTask(t *testing.T) {
+	mockTaskRepo := &mockTaskRepository{tasks: []*task.Task{{ID: 1, ProjectID: 2, Title: "Ship it"}}}
+	h := handler.NewTaskHandler(mockTaskRepo, &mockProjectRepository{}, nil, &mockChecklistItemRepository{})
+
+	created, err := h.CreateTask(2, "Ship it", "", "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ID != 2 || created.Priority != "medium" || mockTaskRepo.tasks[1] != created {
+		t.Errorf("expected the new task with its own ID, got %+v", created)
+	}
+}
+
+func TestActionLog(t *testing.T) {
+	h, _ := createTestAIHandler()
+
+	if err := h.ActionLog(20); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
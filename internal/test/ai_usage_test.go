@@ -0,0 +1,176 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/usage"
+)
+
+func TestUsageReport(t *testing.T) {
+	tests := []struct {
+		name        string
+		since       string
+		groupBy     string
+		setupMocks  func(*mockUsageRepository)
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:    "report by model",
+			since:   "30d",
+			groupBy: "model",
+			setupMocks: func(repo *mockUsageRepository) {
+				repo.summaries = []*usage.Summary{
+					{Group: "openai/gpt-oss-120b", Model: "openai/gpt-oss-120b", Calls: 3, PromptTokens: 1200, CompletionTokens: 800, AvgLatencyMs: 900},
+				}
+			},
+			expectError: false,
+		},
+		{
+			name:    "report by command",
+			since:   "2025-01-01",
+			groupBy: "command",
+			setupMocks: func(repo *mockUsageRepository) {
+				repo.summaries = []*usage.Summary{
+					{Group: "ai-ask", Model: "openai/gpt-oss-120b", Calls: 1, PromptTokens: 100, CompletionTokens: 50},
+					{Group: "ai-ask", Model: "unknown-model", Calls: 1, Errors: 1},
+				}
+			},
+			expectError: false,
+		},
+		{
+			name:        "empty ledger",
+			since:       "7d",
+			groupBy:     "model",
+			setupMocks:  func(repo *mockUsageRepository) {},
+			expectError: false,
+		},
+		{
+			name:        "invalid group",
+			since:       "30d",
+			groupBy:     "day",
+			setupMocks:  func(repo *mockUsageRepository) {},
+			expectError: true,
+			errorMsg:    "invalid --by value",
+		},
+		{
+			name:        "invalid since",
+			since:       "soon",
+			groupBy:     "model",
+			setupMocks:  func(repo *mockUsageRepository) {},
+			expectError: true,
+			errorMsg:    "invalid --since value",
+		},
+		{
+			name:    "repository error",
+			since:   "30d",
+			groupBy: "model",
+			setupMocks: func(repo *mockUsageRepository) {
+				repo.err = ErrDatabaseConnection
+			},
+			expectError: true,
+			errorMsg:    "failed to load usage",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			h, mockUsageRepo := createTestAIHandler()
+			tt.setupMocks(mockUsageRepo)
+
+			err := h.UsageReport(tt.since, tt.groupBy)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+					return
+				}
+				if tt.errorMsg != "" && !contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.errorMsg, err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				if mockUsageRepo.groupBy != tt.groupBy {
+					t.Errorf("Expected usage grouped by %s, got %s", tt.groupBy, mockUsageRepo.groupBy)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigCost(t *testing.T) {
+	cfg := &ai.Config{
+		Prices: map[string]ai.Price{
+			"custom-model": {Input: 1, Output: 2},
+		},
+	}
+
+	if got := cfg.Cost("custom-model", 1_000_000, 500_000); got != 2 {
+		t.Errorf("Expected cost 2, got %v", got)
+	}
+	if got := cfg.Cost("openai/gpt-oss-120b", 1_000_000, 0); got != ai.DefaultPrices["openai/gpt-oss-120b"].Input {
+		t.Errorf("Expected default input price, got %v", got)
+	}
+	if got := cfg.Cost("unknown-model", 1_000_000, 1_000_000); got != 0 {
+		t.Errorf("Expected unknown models to cost nothing, got %v", got)
+	}
+}
+
+func TestMonthlyBudget(t *testing.T) {
+	tests := []struct {
+		name     string
+		spent    int // completion tokens this month, at $1 per million
+		stream   bool
+		exceeded bool
+	}{
+		{name: "under budget", spent: 1_000_000},
+		{name: "over budget", spent: 3_000_000, exceeded: true},
+		{name: "over budget while streaming", spent: 3_000_000, stream: true, exceeded: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			writeFile(t, filepath.Join(os.Getenv("HOME"), ".snip", "config.json"),
+				`{"model": "custom-model", "monthly_budget_usd": 2, "prices": {"custom-model": {"input": 1, "output": 1}}}`)
+			fake := newFakeGroq(t, fakeReply{content: "Hello"})
+			ledger := &mockUsageRepository{summaries: []*usage.Summary{
+				{Group: "custom-model", Model: "custom-model", Calls: 10, CompletionTokens: tt.spent},
+			}}
+			ai.SetUsageLedger(ledger)
+			t.Cleanup(func() { ai.SetUsageLedger(nil) })
+
+			client, err := ai.NewGroqClient()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			messages := []ai.Message{{Role: "user", Content: "hello"}}
+			if tt.stream {
+				_, err = client.ChatStream(context.Background(), messages, 50, 0, func(string) {})
+			} else {
+				_, err = client.ChatContext(context.Background(), messages, 50, 0)
+			}
+
+			if !tt.exceeded {
+				if err != nil || len(fake.requests) != 1 || len(ledger.created) != 1 {
+					t.Errorf("Expected the call to go through and be recorded, got %v with %d request(s)", err, len(fake.requests))
+				}
+				return
+			}
+			if !errors.Is(err, ai.ErrBudgetExceeded) || !contains(err.Error(), "$3.0000 of $2.00") {
+				t.Errorf("Expected the budget to be exceeded, got %v", err)
+			}
+			if len(fake.requests) != 0 || len(ledger.created) != 0 {
+				t.Errorf("Expected no request to be sent, got %d request(s) and %d usage row(s)", len(fake.requests), len(ledger.created))
+			}
+		})
+	}
+}
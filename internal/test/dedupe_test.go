@@ -0,0 +1,177 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/dedupe"
+	"github.com/snip/internal/note"
+)
+
+const deployNote = `To deploy the API, build the Docker image, push it to the registry,
+then run the migration job and roll out the new version with kubectl. Watch the
+logs for five minutes and roll back with kubectl rollout undo if errors show up.`
+
+func TestFindPairs(t *testing.T) {
+	docs := []dedupe.Document{
+		{ID: 1, Text: deployNote},
+		{ID: 2, Text: "Groceries: milk, bread, eggs and coffee for the week."},
+		{ID: 3, Text: deployNote + " Ask the on-call engineer before deploying on Fridays."},
+		{ID: 4, Text: "milk bread"},
+		{ID: 5, Text: "Milk, bread!"},
+		{ID: 6, Text: ""},
+	}
+
+	pairs := dedupe.FindPairs(docs, 0.5)
+	if len(pairs) != 2 {
+		t.Fatalf("Expected 2 pairs, got %+v", pairs)
+	}
+	if pairs[0].A != 4 || pairs[0].B != 5 || pairs[0].Similarity != 1 {
+		t.Errorf("Expected the identical short notes first, got %+v", pairs[0])
+	}
+	if pairs[1].A != 1 || pairs[1].B != 3 || pairs[1].Similarity < 0.7 {
+		t.Errorf("Expected the deploy notes as near-duplicates, got %+v", pairs[1])
+	}
+
+	if pairs := dedupe.FindPairs(docs, 0.95); len(pairs) != 1 {
+		t.Errorf("Expected only the identical pair at 95%%, got %+v", pairs)
+	}
+}
+
+func TestFindPairsAtOtherThresholds(t *testing.T) {
+	// Variants of one text with a growing tail of words replaced give pairs
+	// across the whole similarity range.
+	var docs []dedupe.Document
+	for k := 0; k < 12; k++ {
+		words := make([]string, 60)
+		for i := range words {
+			words[i] = fmt.Sprintf("word%d", i)
+			if i >= len(words)-4*k {
+				words[i] = fmt.Sprintf("other%d_%d", k, i)
+			}
+		}
+		docs = append(docs, dedupe.Document{ID: k + 1, Text: strings.Join(words, " ")})
+	}
+
+	for _, threshold := range []float64{0.05, 0.2, 0.3, 0.7} {
+		var expected []dedupe.Pair
+		for i := range docs {
+			for j := i + 1; j < len(docs); j++ {
+				if s := dedupe.Jaccard(dedupe.Shingles(docs[i].Text), dedupe.Shingles(docs[j].Text)); s >= threshold {
+					expected = append(expected, dedupe.Pair{A: docs[i].ID, B: docs[j].ID, Similarity: s})
+				}
+			}
+		}
+		if len(expected) == 0 {
+			t.Fatalf("Expected the test texts to have pairs at %.2f", threshold)
+		}
+
+		pairs := dedupe.FindPairs(docs, threshold)
+		if len(pairs) != len(expected) {
+			t.Errorf("Expected %d pair(s) at %.2f, got %d", len(expected), threshold, len(pairs))
+		}
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	a := dedupe.Shingles("one two three four")
+	b := dedupe.Shingles("two three four five")
+
+	if len(a) != 2 {
+		t.Fatalf("Expected 2 shingles, got %d", len(a))
+	}
+	if got := dedupe.Jaccard(a, b); got != 1.0/3 {
+		t.Errorf("Expected similarity 1/3, got %v", got)
+	}
+	if got := dedupe.Jaccard(a, dedupe.Shingles("")); got != 0 {
+		t.Errorf("Expected similarity 0 with an empty text, got %v", got)
+	}
+}
+
+func TestParseDuplicateVerdict(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		duplicate bool
+		wantErr   bool
+	}{
+		{name: "duplicate", raw: `{"duplicate": true, "reason": "Both describe the deploy."}`, duplicate: true},
+		{name: "different", raw: "```json\n{\"duplicate\": false, \"reason\": \"Different purpose.\"}\n```"},
+		{name: "missing reason", raw: `{"duplicate": true, "reason": " "}`, wantErr: true},
+		{name: "unknown field", raw: `{"duplicate": true, "reason": "x", "score": 0.9}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := ai.ParseDuplicateVerdict(tt.raw)
+			if tt.wantErr {
+				if !errors.Is(err, ai.ErrInvalidDuplicateVerdict) {
+					t.Errorf("Expected ErrInvalidDuplicateVerdict, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if verdict.Duplicate != tt.duplicate {
+				t.Errorf("Expected duplicate=%v, got %v", tt.duplicate, verdict.Duplicate)
+			}
+		})
+	}
+}
+
+func TestFindDuplicatesWithAI(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fake := newFakeGroq(t, fakeReply{content: `{"duplicate": false, "reason": "One is a checklist, the other a postmortem."}`})
+	h, mockNoteRepo, _ := createTestDedupeHandler()
+	mockNoteRepo.notesWithTags = []*note.NoteWithTags{
+		{ID: 1, Title: "Deploy", Content: deployNote},
+		{ID: 2, Title: "Deploy postmortem", Content: deployNote},
+		{ID: 3, Title: "Groceries", Content: "milk, bread"},
+	}
+
+	if err := h.FindDuplicates(dedupe.DefaultThreshold, true, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(fake.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(fake.requests))
+	}
+	prompt := fake.lastPrompt(0)
+	if !contains(prompt, "Deploy postmortem") || !contains(prompt, "roll out the new version") {
+		t.Errorf("Expected both notes in the prompt, got %q", prompt)
+	}
+	if len(mockNoteRepo.notesWithTags) != 3 {
+		t.Errorf("Expected no note to be merged, got %d notes", len(mockNoteRepo.notesWithTags))
+	}
+}
+
+func TestFindDuplicatesSkipsIgnoredPairs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fake := newFakeGroq(t)
+	h, mockNoteRepo, mockDedupeRepo := createTestDedupeHandler()
+	mockNoteRepo.notesWithTags = []*note.NoteWithTags{
+		{ID: 1, Title: "Deploy", Content: deployNote},
+		{ID: 2, Title: "Deploy again", Content: deployNote},
+	}
+	mockDedupeRepo.ignored = []*dedupe.IgnoredPair{dedupe.NewIgnoredPair(2, 1)}
+
+	if err := h.FindDuplicates(dedupe.DefaultThreshold, true, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("Expected the ignored pair to be skipped, got %d requests", len(fake.requests))
+	}
+}
+
+func TestFindDuplicatesInvalidThreshold(t *testing.T) {
+	h, _, _ := createTestDedupeHandler()
+
+	err := h.FindDuplicates(1.5, false, true)
+	if err == nil || !contains(err.Error(), "threshold must be between 0 and 1") {
+		t.Errorf("Expected invalid threshold error, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/snip/cmd"
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/apperr"
+	"github.com/snip/internal/database"
+	"github.com/snip/internal/handler"
+	"github.com/snip/internal/output"
+	"github.com/snip/internal/repository"
+	"github.com/snip/internal/validation"
+)
+
+func TestErrorKinds(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		kind     apperr.Kind
+		exitCode int
+	}{
+		{"nil", nil, "", 0},
+		{"plain", errors.New("boom"), apperr.KindInternal, 1},
+		{"usage", apperr.New(apperr.KindUsage, errors.New("missing argument")), apperr.KindUsage, 2},
+		{"invalid ID", apperr.Invalid("ID inválido: %s", "x"), apperr.KindValidation, 3},
+		{"validator", fmt.Errorf("failed to create note: %w", validation.NewValidator().ValidateNote(" ")), apperr.KindValidation, 3},
+		{"note not found", fmt.Errorf("failed to fetch note -> %w", repository.ErrNoteNotFound), apperr.KindNotFound, 4},
+		{"task not found", fmt.Errorf("failed to fetch task: %w", repository.ErrTaskNotFound), apperr.KindNotFound, 4},
+		{"no AI client", ai.ErrNoClient, apperr.KindAIUnavailable, 5},
+		{"rate limited", fmt.Errorf("ask: %w", &ai.APIError{StatusCode: 429}), apperr.KindAIUnavailable, 5},
+		{"database", fmt.Errorf("failed to list: %w", sqlite3.Error{Code: sqlite3.ErrBusy}), apperr.KindDatabase, 6},
+		{"tag wins", apperr.New(apperr.KindDatabase, errors.New("failed to connect to database")), apperr.KindDatabase, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err != nil {
+				if kind := apperr.KindOf(tt.err); kind != tt.kind {
+					t.Errorf("Expected kind %q, got %q", tt.kind, kind)
+				}
+			}
+			if code := apperr.ExitCode(tt.err); code != tt.exitCode {
+				t.Errorf("Expected exit code %d, got %d", tt.exitCode, code)
+			}
+		})
+	}
+}
+
+func TestRequiredFlagIsUsageError(t *testing.T) {
+	// Cobra checks required flags after the pre-run hook, where failures
+	// stop being usage errors; the hook has to check them first.
+	t.Setenv("HOME", t.TempDir())
+	saved := os.Args
+	os.Args = []string{"snip", "ai-actions", "1"}
+	t.Cleanup(func() { os.Args = saved })
+
+	if code := cmd.Execute(); code != apperr.KindUsage.ExitCode() {
+		t.Errorf("Expected exit code %d for a missing --project, got %d", apperr.KindUsage.ExitCode(), code)
+	}
+}
+
+func TestHandlerErrorKinds(t *testing.T) {
+	h := handler.NewHandler(&mockNoteRepository{notesWithTags: createTestNotes()}, &mockTagRepository{}, nil)
+
+	if err := h.GetNote("abc", false, false); apperr.KindOf(err) != apperr.KindValidation {
+		t.Errorf("Expected a validation error for a non-numeric ID, got %v", err)
+	}
+	if err := h.AIEditNote("1", "shorter", true); apperr.KindOf(err) != apperr.KindAIUnavailable || !contains(err.Error(), "AI client not available") {
+		t.Errorf("Expected the AI to be unavailable without a client, got %v", err)
+	}
+	tag := "missing"
+	if err := handler.NewHandler(&mockNoteRepository{}, &mockTagRepository{err: repository.ErrTagNotFound}, nil).ListNotes(true, false, &tag); apperr.KindOf(err) != apperr.KindNotFound {
+		t.Errorf("Expected a not-found error for an unknown tag, got %v", err)
+	}
+	t.Setenv("HOME", t.TempDir())
+	if err := h.BackupDatabase(); apperr.KindOf(err) != apperr.KindNotFound {
+		t.Errorf("Expected a not-found error without a database, got %v", err)
+	}
+
+	reports, _, _, _, _ := createTestReportHandler()
+	if err := reports.Report("monthly", 0, false, false); apperr.KindOf(err) != apperr.KindValidation {
+		t.Errorf("Expected a validation error for an unknown report kind, got %v", err)
+	}
+	tasks := handler.NewTaskHandler(&mockTaskRepository{}, &mockProjectRepository{}, nil, &mockChecklistItemRepository{})
+	if err := tasks.BreakdownTaskWithAI(1, "steps", 5, true); apperr.KindOf(err) != apperr.KindValidation {
+		t.Errorf("Expected a validation error for an unknown --as, got %v", err)
+	}
+	actions, _, _, _ := createTestActionItemHandler()
+	if err := actions.ExtractActionItems("1", 0, true); apperr.KindOf(err) != apperr.KindValidation {
+		t.Errorf("Expected a validation error without a project, got %v", err)
+	}
+}
+
+func TestMissingRecordErrorKinds(t *testing.T) {
+	// Update and delete go straight to the database, so run them against a
+	// real, empty one: a statement that changes no row must be "not found".
+	t.Setenv("HOME", t.TempDir())
+	db, err := database.Connect()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	projectRepo, _ := repository.NewProjectRepository(db)
+	taskRepo, _ := repository.NewTaskRepository(db)
+	checklistRepo, _ := repository.NewChecklistRepository(db)
+	itemRepo, _ := repository.NewChecklistItemRepository(db)
+	projects := handler.NewProjectHandler(projectRepo, taskRepo, checklistRepo, itemRepo)
+	tasks := handler.NewTaskHandler(taskRepo, projectRepo, checklistRepo, itemRepo)
+	checklists := handler.NewChecklistHandler(checklistRepo, itemRepo)
+
+	tests := []struct {
+		name     string
+		run      func() error
+		expected error
+	}{
+		{"project update", func() error { return projects.UpdateProject(999, "x", "", "active") }, repository.ErrProjectNotFound},
+		{"project delete", func() error { return projects.DeleteProject(999) }, repository.ErrProjectNotFound},
+		{"task update", func() error { return tasks.UpdateTask(999, "x", "", "pending", "medium", nil) }, repository.ErrTaskNotFound},
+		{"task delete", func() error { return tasks.DeleteTask(999) }, repository.ErrTaskNotFound},
+		{"task toggle", func() error { return tasks.ToggleTaskComplete(999) }, repository.ErrTaskNotFound},
+		{"checklist delete", func() error { return checklists.DeleteChecklist(999) }, repository.ErrChecklistNotFound},
+		{"checklist item delete", func() error { return checklists.DeleteChecklistItem(999) }, repository.ErrChecklistItemNotFound},
+		{"checklist item toggle", func() error { return checklists.ToggleChecklistItem(999) }, repository.ErrChecklistItemNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, err)
+			}
+			if code := apperr.ExitCode(err); code != 4 {
+				t.Errorf("Expected exit code 4, got %d", code)
+			}
+		})
+	}
+
+	if err := projects.CreateProject("Site", ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := projects.UpdateProject(1, "Site", "", "active"); err != nil {
+		t.Errorf("Expected an existing project to update, got %v", err)
+	}
+	if err := projects.DeleteProject(1); err != nil {
+		t.Errorf("Expected an existing project to be deleted, got %v", err)
+	}
+}
+
+func TestErrorReport(t *testing.T) {
+	err := fmt.Errorf("failed to fetch note -> %w", repository.ErrNoteNotFound)
+
+	var buf bytes.Buffer
+	if werr := output.Write(&buf, output.FormatJSON, apperr.Describe(err), nil); werr != nil {
+		t.Fatalf("Unexpected error: %v", werr)
+	}
+	expected := "{\n  \"error\": \"failed to fetch note -> note not found\",\n  \"kind\": \"not_found\",\n  \"exit_code\": 4\n}\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
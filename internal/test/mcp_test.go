@@ -0,0 +1,274 @@
+package test
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/checklist"
+	"github.com/snip/internal/mcp"
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/project"
+)
+
+type mcpResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type mcpToolResult struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+	IsError bool `json:"isError"`
+}
+
+type testMCP struct {
+	server         *mcp.Server
+	notes          *mockNoteRepository
+	tags           *mockTagRepository
+	projects       *mockProjectRepository
+	tasks          *mockTaskRepository
+	checklistItems *mockChecklistItemRepository
+	actions        *mockActionRepository
+}
+
+func newTestMCP(readOnly bool) *testMCP {
+	m := &testMCP{
+		notes:          &mockNoteRepository{},
+		tags:           &mockTagRepository{},
+		projects:       &mockProjectRepository{},
+		tasks:          &mockTaskRepository{},
+		checklistItems: &mockChecklistItemRepository{},
+		actions:        &mockActionRepository{},
+	}
+	m.notes.notesWithTags = []*note.NoteWithTags{
+		{ID: 1, Title: "Deploy", Content: "Run make deploy. password=hunter22", Tags: []string{"ops"}},
+		{ID: 2, Title: "Deploy salaries", Content: "Confidential deploy numbers", Tags: []string{"private"}},
+	}
+	m.server = mcp.New(mcp.Repositories{
+		Notes:          m.notes,
+		Tags:           m.tags,
+		Projects:       m.projects,
+		Tasks:          m.tasks,
+		ChecklistItems: m.checklistItems,
+		Actions:        m.actions,
+	}, &ai.Config{ExcludeTags: []string{"private"}}, readOnly)
+	return m
+}
+
+// run sends messages, one per line, and returns the responses by id.
+func (m *testMCP) run(t *testing.T, messages ...string) map[string]mcpResponse {
+	t.Helper()
+	var out strings.Builder
+	if err := m.server.Serve(strings.NewReader(strings.Join(messages, "\n")), &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	responses := map[string]mcpResponse{}
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var resp mcpResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("Invalid response %q: %v", scanner.Text(), err)
+		}
+		responses[string(resp.ID)] = resp
+	}
+	return responses
+}
+
+// callTool runs one tool and returns its text and whether it failed.
+func (m *testMCP) callTool(t *testing.T, name, arguments string) (string, bool) {
+	t.Helper()
+	resp := m.run(t, `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "`+name+`", "arguments": `+arguments+`}}`)["1"]
+	if resp.Error != nil {
+		t.Fatalf("Unexpected protocol error: %s", resp.Error.Message)
+	}
+	var result mcpToolResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil || len(result.Content) != 1 {
+		t.Fatalf("Invalid tool result %s: %v", resp.Result, err)
+	}
+	return result.Content[0].Text, result.IsError
+}
+
+func TestMCPProtocol(t *testing.T) {
+	m := newTestMCP(false)
+
+	responses := m.run(t,
+		`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2024-11-05", "clientInfo": {"name": "agent", "version": "1"}}}`,
+		`{"jsonrpc": "2.0", "method": "notifications/initialized"}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "initialize", "params": {"protocolVersion": "1999-01-01"}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "ping"}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "prompts/list"}`,
+		`{"jsonrpc": "2.0", "id": "five", "method": "tools/call", "params": {"name": "drop_database"}}`,
+		`not json`,
+	)
+
+	if len(responses) != 6 {
+		t.Fatalf("Expected 6 responses (none for the notification), got %d", len(responses))
+	}
+
+	var init struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ServerInfo      struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+	}
+	json.Unmarshal(responses["1"].Result, &init)
+	if init.ProtocolVersion != "2024-11-05" || init.ServerInfo.Name != "snip" {
+		t.Errorf("Expected the client's protocol version and the server name, got %+v", init)
+	}
+	json.Unmarshal(responses["2"].Result, &init)
+	if init.ProtocolVersion != mcp.LatestProtocolVersion {
+		t.Errorf("Expected the latest version for an unknown one, got %s", init.ProtocolVersion)
+	}
+
+	if string(responses["3"].Result) != "{}" {
+		t.Errorf("Expected an empty ping result, got %s", responses["3"].Result)
+	}
+
+	expected := map[string]int{"4": -32601, `"five"`: -32602, "null": -32700}
+	for id, code := range expected {
+		if resp := responses[id]; resp.Error == nil || resp.Error.Code != code {
+			t.Errorf("Expected error %d for id %s, got %+v", code, id, resp.Error)
+		}
+	}
+}
+
+func TestMCPNoteTools(t *testing.T) {
+	m := newTestMCP(false)
+
+	text, failed := m.callTool(t, "search_notes", `{"query": "deploy"}`)
+	if failed {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+	var found []struct {
+		ID      int    `json:"id"`
+		URI     string `json:"uri"`
+		Snippet string `json:"snippet"`
+	}
+	json.Unmarshal([]byte(text), &found)
+	if len(found) != 1 || found[0].URI != "snip://note/1" {
+		t.Fatalf("Expected only the note without an excluded tag, got %s", text)
+	}
+	if contains(found[0].Snippet, "hunter22") {
+		t.Errorf("Expected the password to be masked, got %q", found[0].Snippet)
+	}
+
+	if text, failed := m.callTool(t, "get_note", `{"id": 2}`); !failed || !contains(text, "excluded tag") {
+		t.Errorf("Expected the excluded note to be refused, got %q", text)
+	}
+	if text, failed := m.callTool(t, "get_note", `{"id": 9}`); !failed || !contains(text, "note #9") {
+		t.Errorf("Expected a missing note error, got %q", text)
+	}
+
+	if text, failed := m.callTool(t, "create_note", `{"title": "Rollback", "content": "git revert", "tags": ["ops", "git ops"]}`); failed {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+	if len(m.notes.notes) != 1 || m.notes.notes[0].Title != "Rollback" {
+		t.Errorf("Expected the note to be created, got %+v", m.notes.notes)
+	}
+	if strings.Join(m.tags.requested, ",") != "ops,git" {
+		t.Errorf("Expected tags ops and git, got %v", m.tags.requested)
+	}
+
+	if len(m.actions.actions) != 4 || m.actions.actions[1].Status != "failed" || m.actions.actions[3].Tool != "create_note" {
+		t.Errorf("Expected every call to be logged, got %d actions", len(m.actions.actions))
+	}
+}
+
+func TestMCPTaskTools(t *testing.T) {
+	m := newTestMCP(false)
+	m.projects.projects = []*project.Project{{ID: 1, Name: "Site", Status: "active"}}
+	m.checklistItems.items = []*checklist.ChecklistItem{{ID: 7, ChecklistID: 1, Title: "Backup"}}
+
+	if text, failed := m.callTool(t, "create_task", `{"project_id": 1, "title": "Ship", "priority": "urgent"}`); !failed || !contains(text, "invalid priority") {
+		t.Errorf("Expected an invalid priority error, got %q", text)
+	}
+	if text, failed := m.callTool(t, "create_task", `{"project_id": 1, "title": "Ship", "due_date": "2026-11-01"}`); failed {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+	if len(m.tasks.tasks) != 1 || m.tasks.tasks[0].Priority != "medium" || m.tasks.tasks[0].DueDate == nil {
+		t.Fatalf("Expected a medium priority task with a due date, got %+v", m.tasks.tasks)
+	}
+
+	text, _ := m.callTool(t, "list_tasks", `{"project_id": 1}`)
+	if !contains(text, `"title": "Ship"`) || !contains(text, `"due_date": "2026-11-01"`) {
+		t.Errorf("Expected the task in the list, got %s", text)
+	}
+
+	text, failed := m.callTool(t, "toggle_checklist_item", `{"item_id": 7}`)
+	if failed || !contains(text, `"completed": true`) {
+		t.Errorf("Expected the item to be marked done, got %s", text)
+	}
+}
+
+func TestMCPReadOnly(t *testing.T) {
+	m := newTestMCP(true)
+
+	responses := m.run(t,
+		`{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "create_note", "arguments": {"title": "x", "content": "y"}}}`,
+	)
+
+	var list struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+	}
+	json.Unmarshal(responses["1"].Result, &list)
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != "search_notes,get_note,list_tasks" {
+		t.Errorf("Expected only the read tools, got %v", names)
+	}
+	if responses["2"].Error == nil || len(m.notes.notes) != 0 {
+		t.Errorf("Expected write tools to be unavailable, got %s", responses["2"].Result)
+	}
+}
+
+func TestMCPResources(t *testing.T) {
+	m := newTestMCP(false)
+
+	responses := m.run(t,
+		`{"jsonrpc": "2.0", "id": 1, "method": "resources/list"}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "resources/read", "params": {"uri": "snip://note/1"}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "resources/read", "params": {"uri": "snip://note/2"}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "resources/read", "params": {"uri": "file:///etc/passwd"}}`,
+	)
+
+	var list struct {
+		Resources []struct {
+			URI  string `json:"uri"`
+			Name string `json:"name"`
+		} `json:"resources"`
+	}
+	json.Unmarshal(responses["1"].Result, &list)
+	if len(list.Resources) != 1 || list.Resources[0].URI != "snip://note/1" || list.Resources[0].Name != "Deploy" {
+		t.Errorf("Expected only note 1 as a resource, got %+v", list.Resources)
+	}
+
+	var read struct {
+		Contents []struct {
+			Text string `json:"text"`
+		} `json:"contents"`
+	}
+	json.Unmarshal(responses["2"].Result, &read)
+	if len(read.Contents) != 1 || !strings.HasPrefix(read.Contents[0].Text, "# Deploy\n") || contains(read.Contents[0].Text, "hunter22") {
+		t.Errorf("Expected the masked note as Markdown, got %+v", read.Contents)
+	}
+
+	for _, id := range []string{"3", "4"} {
+		if resp := responses[id]; resp.Error == nil || resp.Error.Code != -32602 {
+			t.Errorf("Expected request %s to be refused, got %s", id, resp.Result)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/snip/internal/handler"
+	"github.com/snip/internal/output"
+	"github.com/snip/internal/project"
+	"github.com/snip/internal/task"
+)
+
+// withOutput selects format for the rest of the test and captures what
+// output.Print writes.
+func withOutput(t *testing.T, format output.Format) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	output.SetFormat(format)
+	output.SetWriter(&buf)
+	t.Cleanup(func() {
+		output.SetFormat(output.FormatText)
+		output.SetWriter(os.Stdout)
+	})
+	return &buf
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := map[string]output.Format{"": output.FormatText, "json": output.FormatJSON, "YAML": output.FormatYAML, "table": output.FormatTable}
+	for value, expected := range tests {
+		if f, err := output.ParseFormat(value); err != nil || f != expected {
+			t.Errorf("ParseFormat(%q) = %q, %v; expected %q", value, f, err, expected)
+		}
+	}
+	if _, err := output.ParseFormat("xml"); err == nil || !contains(err.Error(), "json, yaml, table") {
+		t.Errorf("Expected an error listing the formats, got %v", err)
+	}
+}
+
+func TestOutputWrite(t *testing.T) {
+	notes := createTestNotes()[:2]
+	table := output.NewTable("ID", "TITLE", "PARENT")
+	var parent *int
+	table.Add(notes[0].ID, notes[0].Title, parent)
+	table.Add(notes[1].ID, "Multi\nline", 3)
+
+	var buf bytes.Buffer
+	if err := output.Write(&buf, output.FormatTable, notes, table); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "ID  TITLE       PARENT\n1   First Note\n2   Multi line  3\n"
+	if buf.String() != expected {
+		t.Errorf("Expected aligned table:\n%q\ngot:\n%q", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := output.Write(&buf, output.FormatYAML, map[string]any{"title": "true", "tags": []string{"a: b"}, "id": 1}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "id: 1\ntags:\n  - \"a: b\"\ntitle: \"true\"\n" {
+		t.Errorf("Expected block YAML with ambiguous strings quoted, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	var none []*task.Task
+	if err := output.Write(&buf, output.FormatJSON, none, nil); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("Expected an empty JSON list for no results, got %q (%v)", buf.String(), err)
+	}
+}
+
+func TestOutputHandlers(t *testing.T) {
+	projects := &mockProjectRepository{projects: []*project.Project{{ID: 1, Name: "Site", Status: "active"}}}
+	tasks := &mockTaskRepository{tasks: []*task.Task{{ID: 4, ProjectID: 1, Title: "Ship", Status: "pending", Priority: "high"}}}
+	h := handler.NewProjectHandler(projects, tasks, &mockChecklistRepository{}, &mockChecklistItemRepository{})
+
+	buf := withOutput(t, output.FormatJSON)
+	if err := h.ShowProject(1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var shown struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Tasks []struct {
+			ID       int    `json:"id"`
+			Priority string `json:"priority"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &shown); err != nil {
+		t.Fatalf("Expected JSON, got %q: %v", buf.String(), err)
+	}
+	if shown.Name != "Site" || len(shown.Tasks) != 1 || shown.Tasks[0].Priority != "high" {
+		t.Errorf("Expected the project with its task, got %+v", shown)
+	}
+
+	buf = withOutput(t, output.FormatYAML)
+	noteHandler := handler.NewHandler(&mockNoteRepository{notesWithTags: createTestNotes()}, &mockTagRepository{}, nil)
+	if err := noteHandler.GetRecentNotes(1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "- id: 3\n  title: Third Note\n") || !contains(buf.String(), "  tags:\n    - work\n    - meeting\n") {
+		t.Errorf("Expected the notes as YAML, got:\n%s", buf.String())
+	}
+}
+
+func TestOutputSemanticFindIsValidJSON(t *testing.T) {
+	// Everything the command writes to stdout must parse, including while it
+	// indexes notes created elsewhere (mcp, serve, tui) that have no vector yet.
+	stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	saved := os.Stdout
+	os.Stdout = stdout
+	t.Cleanup(func() { os.Stdout = saved })
+	withOutput(t, output.FormatJSON)
+	output.SetWriter(stdout)
+
+	h, mockNoteRepo, _, mockEmbeddingRepo := createTestHandlerWithEmbeddings()
+	mockNoteRepo.notesWithTags = createTestNotes()
+	if err := h.SemanticFindNotes("note content", 5); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(mockEmbeddingRepo.embeddings) != len(mockNoteRepo.notesWithTags) {
+		t.Fatalf("Expected the unindexed notes to be indexed, got %d", len(mockEmbeddingRepo.embeddings))
+	}
+
+	written, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var found []map[string]any
+	if err := json.Unmarshal(written, &found); err != nil || len(found) == 0 {
+		t.Errorf("Expected only JSON on stdout, got %q: %v", written, err)
+	}
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/snip/internal/ai"
+)
+
+func TestParseProjectPlan(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		expectError bool
+		errorMsg    string
+		tasks       int
+	}{
+		{
+			name: "valid plan",
+			raw: `{"summary": "Blog em Go", "phases": [
+				{"name": "Setup", "tasks": [
+					{"title": "Criar repositório", "priority": "HIGH", "due_in_days": 1, "checklist": ["git init", " "]},
+					{"title": "Escolher tema", "description": "Minimalista"}
+				]},
+				{"name": "Conteúdo", "tasks": [{"title": "Primeiro post", "priority": "medium", "due_in_days": null}]}
+			]}`,
+			expectError: false,
+			tasks:       3,
+		},
+		{
+			name:        "plan wrapped in markdown fence",
+			raw:         "Aqui está:\n```json\n{\"phases\": [{\"name\": \"A\", \"tasks\": [{\"title\": \"T\"}]}]}\n```",
+			expectError: false,
+			tasks:       1,
+		},
+		{
+			name:        "no JSON",
+			raw:         "Desculpe, não consigo ajudar.",
+			expectError: true,
+			errorMsg:    "no JSON object",
+		},
+		{
+			name:        "unknown field",
+			raw:         `{"phases": [{"name": "A", "tasks": [{"title": "T", "owner": "ana"}]}]}`,
+			expectError: true,
+			errorMsg:    "unknown field",
+		},
+		{
+			name:        "no phases",
+			raw:         `{"summary": "x", "phases": []}`,
+			expectError: true,
+			errorMsg:    "no phases",
+		},
+		{
+			name:        "task without title",
+			raw:         `{"phases": [{"name": "A", "tasks": [{"title": "  "}]}]}`,
+			expectError: true,
+			errorMsg:    "has no title",
+		},
+		{
+			name:        "invalid priority",
+			raw:         `{"phases": [{"name": "A", "tasks": [{"title": "T", "priority": "urgent"}]}]}`,
+			expectError: true,
+			errorMsg:    "invalid priority",
+		},
+		{
+			name:        "negative due date",
+			raw:         `{"phases": [{"name": "A", "tasks": [{"title": "T", "due_in_days": -3}]}]}`,
+			expectError: true,
+			errorMsg:    "invalid due_in_days",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := ai.ParseProjectPlan(tt.raw)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+					return
+				}
+				if !errors.Is(err, ai.ErrInvalidPlan) {
+					t.Errorf("Expected ErrInvalidPlan, got %v", err)
+				}
+				if tt.errorMsg != "" && !contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.errorMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			count := 0
+			for _, phase := range plan.Phases {
+				for _, task := range phase.Tasks {
+					count++
+					if task.Priority != "low" && task.Priority != "medium" && task.Priority != "high" {
+						t.Errorf("Expected normalized priority, got %q", task.Priority)
+					}
+				}
+			}
+			if count != tt.tasks {
+				t.Errorf("Expected %d tasks, got %d", tt.tasks, count)
+			}
+		})
+	}
+}
+
+func TestParseProjectPlan_CleansChecklist(t *testing.T) {
+	plan, err := ai.ParseProjectPlan(`{"phases": [{"name": "A", "tasks": [{"title": "T", "checklist": ["um", "", "  dois  "]}]}]}`)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	items := plan.Phases[0].Tasks[0].Checklist
+	if len(items) != 2 || items[0] != "um" || items[1] != "dois" {
+		t.Errorf("Expected cleaned checklist [um dois], got %q", items)
+	}
+}
@@ -0,0 +1,124 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/snip/internal/activity"
+	"github.com/snip/internal/handler"
+	"github.com/snip/internal/project"
+	"github.com/snip/internal/task"
+)
+
+func TestReport(t *testing.T) {
+	now := time.Now()
+	overdue := now.AddDate(0, 0, -10)
+	future := now.AddDate(0, 0, 10)
+
+	setup := func(projectRepo *mockProjectRepository, taskRepo *mockTaskRepository, activityRepo *mockActivityRepository) {
+		projectRepo.projects = []*project.Project{
+			{ID: 1, Name: "Site", Status: "active"},
+			{ID: 2, Name: "Infra", Status: "active"},
+		}
+		taskRepo.tasks = []*task.Task{
+			{ID: 1, ProjectID: 1, Title: "Write docs", Status: "completed", Priority: "medium"},
+			{ID: 2, ProjectID: 1, Title: "Ship release", Status: "in_progress", Priority: "high", DueDate: &overdue},
+			{ID: 3, ProjectID: 2, Title: "Rotate keys", Status: "pending", Priority: "low", DueDate: &future},
+			{ID: 4, ProjectID: 2, Title: "Old migration", Status: "completed", Priority: "low", DueDate: &overdue},
+		}
+		activityRepo.entries = []*activity.TaskActivity{
+			{TaskID: 1, ProjectID: 1, TaskTitle: "Write docs", FromStatus: "pending", ToStatus: "completed", CreatedAt: now.Add(-time.Minute)},
+			{TaskID: 2, ProjectID: 1, TaskTitle: "Ship release", FromStatus: "pending", ToStatus: "in_progress", CreatedAt: now.Add(-time.Minute)},
+			// Completed and reopened again: not reported.
+			{TaskID: 3, ProjectID: 2, TaskTitle: "Rotate keys", FromStatus: "pending", ToStatus: "completed", CreatedAt: now.Add(-2 * time.Minute)},
+			{TaskID: 3, ProjectID: 2, TaskTitle: "Rotate keys", FromStatus: "completed", ToStatus: "pending", CreatedAt: now.Add(-time.Minute)},
+			// Outside the weekly window.
+			{TaskID: 4, ProjectID: 2, TaskTitle: "Old migration", FromStatus: "pending", ToStatus: "completed", CreatedAt: now.AddDate(0, 0, -30)},
+		}
+	}
+
+	t.Run("weekly report saved as note", func(t *testing.T) {
+		h, mockNoteRepo, mockProjectRepo, mockTaskRepo, mockActivityRepo := createTestReportHandler()
+		setup(mockProjectRepo, mockTaskRepo, mockActivityRepo)
+
+		if err := h.Report(handler.ReportWeekly, 0, false, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(mockNoteRepo.notes) != 1 {
+			t.Fatalf("expected 1 saved note, got %d", len(mockNoteRepo.notes))
+		}
+
+		content := mockNoteRepo.notes[0].Content
+		for _, expected := range []string{
+			"## Concluídas (1)\n\n- #1 Write docs — Site",
+			"## Iniciadas (1)\n\n- #2 Ship release — Site",
+			"## Atrasadas (1)\n\n- #2 Ship release [high] — Site",
+		} {
+			if !strings.Contains(content, expected) {
+				t.Errorf("expected report to contain %q, got:\n%s", expected, content)
+			}
+		}
+		for _, unexpected := range []string{"Rotate keys", "Old migration"} {
+			if strings.Contains(content, unexpected) {
+				t.Errorf("expected report not to mention %q, got:\n%s", unexpected, content)
+			}
+		}
+	})
+
+	t.Run("project filter", func(t *testing.T) {
+		h, mockNoteRepo, mockProjectRepo, mockTaskRepo, mockActivityRepo := createTestReportHandler()
+		setup(mockProjectRepo, mockTaskRepo, mockActivityRepo)
+
+		if err := h.Report(handler.ReportStandup, 2, false, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		saved := mockNoteRepo.notes[0]
+		if !strings.HasSuffix(saved.Title, "— Infra") {
+			t.Errorf("expected title to name the project, got %q", saved.Title)
+		}
+		if strings.Contains(saved.Content, "Write docs") || strings.Count(saved.Content, "- Nenhuma") != 3 {
+			t.Errorf("expected an empty report for project 2, got:\n%s", saved.Content)
+		}
+	})
+
+	t.Run("invalid kind", func(t *testing.T) {
+		h, _, _, _, _ := createTestReportHandler()
+
+		err := h.Report("monthly", 0, false, false)
+		if err == nil || !strings.Contains(err.Error(), "invalid report kind") {
+			t.Errorf("expected invalid kind error, got %v", err)
+		}
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		h, _, _, _, _ := createTestReportHandler()
+
+		err := h.Report(handler.ReportWeekly, 9, false, false)
+		if err == nil || !strings.Contains(err.Error(), "project not found") {
+			t.Errorf("expected project not found error, got %v", err)
+		}
+	})
+
+	t.Run("activity error", func(t *testing.T) {
+		h, _, _, _, mockActivityRepo := createTestReportHandler()
+		mockActivityRepo.err = errors.New("database error")
+
+		err := h.Report(handler.ReportStandup, 0, false, false)
+		if err == nil || !strings.Contains(err.Error(), "failed to fetch task activity") {
+			t.Errorf("expected activity error, got %v", err)
+		}
+	})
+
+	t.Run("no AI client", func(t *testing.T) {
+		t.Setenv("GROQ_API_KEY", "")
+		h, _, _, _, _ := createTestReportHandler()
+
+		err := h.Report(handler.ReportStandup, 0, true, false)
+		if err == nil || !strings.Contains(err.Error(), "AI client not available") {
+			t.Errorf("expected AI client error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,298 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/embedding"
+	"github.com/snip/internal/note"
+)
+
+var semanticResultID = regexp.MustCompile(`(?m)^● #(\d+) `)
+
+// captureStdout runs fn and returns everything it printed to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- string(out)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+func semanticResultIDs(out string) []int {
+	var ids []int
+	for _, m := range semanticResultID.FindAllStringSubmatch(out, -1) {
+		id, _ := strconv.Atoi(m[1])
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestSemanticFindNotes(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		setupMocks  func(*mockNoteRepository, *mockEmbeddingRepository)
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:  "successful semantic search",
+			query: "how do we rotate certs",
+			setupMocks: func(noteRepo *mockNoteRepository, embeddingRepo *mockEmbeddingRepository) {
+				noteRepo.notesWithTags = createTestNotes()
+			},
+			expectError: false,
+		},
+		{
+			name:  "empty database",
+			query: "anything",
+			setupMocks: func(noteRepo *mockNoteRepository, embeddingRepo *mockEmbeddingRepository) {
+				noteRepo.notesWithTags = []*note.NoteWithTags{}
+			},
+			expectError: false,
+		},
+		{
+			name:  "note repository error",
+			query: "test",
+			setupMocks: func(noteRepo *mockNoteRepository, embeddingRepo *mockEmbeddingRepository) {
+				noteRepo.err = ErrDatabaseConnection
+			},
+			expectError: true,
+			errorMsg:    "failed to fetch notes",
+		},
+		{
+			name:  "embedding repository error",
+			query: "test",
+			setupMocks: func(noteRepo *mockNoteRepository, embeddingRepo *mockEmbeddingRepository) {
+				noteRepo.notesWithTags = createTestNotes()
+				embeddingRepo.err = ErrDatabaseConnection
+			},
+			expectError: true,
+			errorMsg:    "failed to load embeddings",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mockNoteRepo, _, mockEmbeddingRepo := createTestHandlerWithEmbeddings()
+			tt.setupMocks(mockNoteRepo, mockEmbeddingRepo)
+
+			err := h.SemanticFindNotes(tt.query, 10)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+					return
+				}
+				if tt.errorMsg != "" && !contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.errorMsg, err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestSemanticFindNotes_RanksResults(t *testing.T) {
+	h, mockNoteRepo, _, _ := createTestHandlerWithEmbeddings()
+	mockNoteRepo.notesWithTags = []*note.NoteWithTags{
+		{ID: 1, Title: "Groceries", Content: "apples, bananas and milk"},
+		{ID: 2, Title: "Certificate rotation", Content: "Rotate the TLS certificates on the load balancer every quarter"},
+		{ID: 3, Title: "Rotating certs", Content: "Rotating TLS certs on the load balancer"},
+		{ID: 4, Title: "Lunch", Content: "Pizza, salad, soda, cookies, sandwiches, coffee, donuts and bagels for the office party; receipts are filed next to the gift certificates"},
+	}
+
+	var err error
+	out := captureStdout(t, func() {
+		err = h.SemanticFindNotes("rotate certificates", 10)
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	// Note 4 is less similar than note 3 but also matches the keyword search,
+	// so the merged ranking puts it ahead; the groceries never show up.
+	expected := []int{2, 4, 3}
+	ids := semanticResultIDs(out)
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected results %v, got %v\n%s", expected, ids, out)
+	}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Fatalf("Expected results %v, got %v\n%s", expected, ids, out)
+		}
+	}
+}
+
+func TestUpdateNote_ReindexesNote(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho 'Rotate the TLS certificates' > \"$1\"\n"), 0o755); err != nil {
+		t.Fatalf("Failed to write editor script: %v", err)
+	}
+	t.Setenv("EDITOR", script)
+
+	h, mockNoteRepo, _, mockEmbeddingRepo := createTestHandlerWithEmbeddings()
+	mockNoteRepo.notesWithTags = createTestNotes()
+	if err := h.SemanticFindNotes("note content", 5); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	before := mockEmbeddingRepo.embeddings[1].ContentHash
+
+	if err := h.UpdateNote("1", "Certificates"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	after := mockEmbeddingRepo.embeddings[1]
+	if after.ContentHash == before {
+		t.Fatalf("Expected note 1 to be re-embedded after update")
+	}
+
+	out := captureStdout(t, func() {
+		err := h.SemanticFindNotes("rotate certificates", 1)
+		if err != nil {
+			t.Errorf("Expected no error but got: %v", err)
+		}
+	})
+	if ids := semanticResultIDs(out); len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("Expected the updated note to be the top match, got %v\n%s", ids, out)
+	}
+}
+
+func TestSemanticFindNotes_IndexesMissingNotes(t *testing.T) {
+	h, mockNoteRepo, _, mockEmbeddingRepo := createTestHandlerWithEmbeddings()
+	mockNoteRepo.notesWithTags = createTestNotes()
+
+	if err := h.SemanticFindNotes("note content", 5); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(mockEmbeddingRepo.embeddings) != len(mockNoteRepo.notesWithTags) {
+		t.Errorf("Expected %d embeddings, got %d", len(mockNoteRepo.notesWithTags), len(mockEmbeddingRepo.embeddings))
+	}
+}
+
+// newFakeEmbedder starts an embeddings endpoint that answers every input
+// with the same vector, points SNIP_EMBEDDING_URL at it and returns the
+// texts it received.
+func newFakeEmbedder(t *testing.T) *[]string {
+	t.Helper()
+	var inputs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		inputs = append(inputs, req.Input...)
+
+		data := make([]map[string]any, len(req.Input))
+		for i := range req.Input {
+			data[i] = map[string]any{"index": i, "embedding": []float32{1, 0}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("SNIP_EMBEDDING_URL", server.URL)
+	return &inputs
+}
+
+func TestSemanticFindNotes_RemoteSkipsExcludedNotes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	writeFile(t, filepath.Join(os.Getenv("HOME"), ".snip", "config.json"), `{"exclude_tags": ["private"]}`)
+	inputs := newFakeEmbedder(t)
+
+	h, mockNoteRepo, _, mockEmbeddingRepo := createTestHandlerWithEmbeddings()
+	mockNoteRepo.notesWithTags = []*note.NoteWithTags{
+		{ID: 1, Title: "Launch plan", Content: "Ship the beta in May", Tags: []string{"work"}},
+		{ID: 2, Title: "Salary plan", Content: "Ask for a raise", Tags: []string{"Private"}},
+	}
+
+	var err error
+	out := captureStdout(t, func() {
+		err = h.SemanticFindNotes("plan", 10)
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	for _, in := range *inputs {
+		if strings.Contains(in, "raise") {
+			t.Errorf("Expected the excluded note never to reach the embedder, sent %q", in)
+		}
+	}
+	if _, ok := mockEmbeddingRepo.embeddings[2]; ok || len(mockEmbeddingRepo.embeddings) != 1 {
+		t.Errorf("Expected only the allowed note to be indexed, got %v", mockEmbeddingRepo.embeddings)
+	}
+	if ids := semanticResultIDs(out); len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("Expected only note #1 in the results, got %v\n%s", ids, out)
+	}
+}
+
+func TestIndexNote_RemoteDropsExcludedNote(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	writeFile(t, filepath.Join(os.Getenv("HOME"), ".snip", "config.json"), `{"exclude_tags": ["private"]}`)
+	inputs := newFakeEmbedder(t)
+
+	h, mockNoteRepo, _, mockEmbeddingRepo := createTestHandlerWithEmbeddings()
+	mockNoteRepo.notesWithTags = []*note.NoteWithTags{{ID: 1, Title: "Salary plan", Content: "Ask for a raise", Tags: []string{"private"}}}
+	mockEmbeddingRepo.embeddings = map[int]*embedding.Embedding{1: {NoteID: 1, Vector: []float32{1, 0}}}
+
+	if err := h.PatchNote("1", stringPtr("Salary"), nil); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(*inputs) != 0 {
+		t.Errorf("Expected no embedding request for an excluded note, sent %q", *inputs)
+	}
+	if _, ok := mockEmbeddingRepo.embeddings[1]; ok {
+		t.Errorf("Expected the stored vector to be dropped")
+	}
+}
+
+func TestHashEmbedder(t *testing.T) {
+	embedder := ai.NewHashEmbedder(ai.HashEmbeddingDims)
+
+	vectors, err := embedder.Embed([]string{
+		"how do we rotate certs",
+		"Rotating TLS certificates on the load balancer",
+		"Grocery list: apples, bananas and milk",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	related := ai.CosineSimilarity(vectors[0], vectors[1])
+	unrelated := ai.CosineSimilarity(vectors[0], vectors[2])
+	if related <= unrelated {
+		t.Errorf("Expected related text to score higher (%.3f) than unrelated text (%.3f)", related, unrelated)
+	}
+
+	again, _ := embedder.Embed([]string{"how do we rotate certs"})
+	if ai.CosineSimilarity(vectors[0], again[0]) < 0.999 {
+		t.Errorf("Expected deterministic embeddings for identical input")
+	}
+}
@@ -0,0 +1,295 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/snip/internal/note"
+	"github.com/snip/internal/project"
+	"github.com/snip/internal/server"
+)
+
+const testToken = "secret-token"
+
+type testAPI struct {
+	url            string
+	notes          *mockNoteRepository
+	tags           *mockTagRepository
+	projects       *mockProjectRepository
+	tasks          *mockTaskRepository
+	checklists     *mockChecklistRepository
+	checklistItems *mockChecklistItemRepository
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	api := &testAPI{
+		notes:          &mockNoteRepository{},
+		tags:           &mockTagRepository{},
+		projects:       &mockProjectRepository{},
+		tasks:          &mockTaskRepository{},
+		checklists:     &mockChecklistRepository{},
+		checklistItems: &mockChecklistItemRepository{},
+	}
+	s := server.New(server.Repositories{
+		Notes:          api.notes,
+		Tags:           api.tags,
+		Projects:       api.projects,
+		Tasks:          api.tasks,
+		Checklists:     api.checklists,
+		ChecklistItems: api.checklistItems,
+	}, testToken)
+
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	api.url = ts.URL
+	return api
+}
+
+// do sends an authenticated request and decodes the JSON answer into out,
+// when given, returning the status code.
+func (api *testAPI) do(t *testing.T, method, path, body string, out any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, api.url+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("Failed to decode %q: %v", data, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestServerAuth(t *testing.T) {
+	api := newTestAPI(t)
+
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		expected int
+	}{
+		{name: "no token", path: "/notes", expected: http.StatusUnauthorized},
+		{name: "wrong token", path: "/notes", header: "Bearer nope", expected: http.StatusUnauthorized},
+		{name: "valid token", path: "/notes", header: "Bearer " + testToken, expected: http.StatusOK},
+		{name: "public health", path: "/health", expected: http.StatusOK},
+		{name: "public openapi", path: "/openapi.json", expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", api.url+tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServerNotes(t *testing.T) {
+	api := newTestAPI(t)
+
+	var created note.NoteWithTags
+	status := api.do(t, "POST", "/notes", `{"title": "Docker", "content": "docker compose up", "tags": ["ops", "docker", "ops"]}`, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", status)
+	}
+	if created.ID != 1 || created.Title != "Docker" || len(created.Tags) != 2 {
+		t.Errorf("Expected note #1 with 2 tags, got %+v", created)
+	}
+	if len(api.notes.notes) != 1 || api.notes.notes[0].Content != "docker compose up" {
+		t.Errorf("Expected the note to be stored, got %+v", api.notes.notes)
+	}
+	if strings.Join(api.tags.requested, ",") != "ops,docker" {
+		t.Errorf("Expected tags ops and docker, got %v", api.tags.requested)
+	}
+
+	var failed server.ErrorResponse
+	if status := api.do(t, "POST", "/notes", `{"content": "no title"}`, &failed); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a title, got %d", status)
+	}
+	if !contains(failed.Error, "title is required") {
+		t.Errorf("Expected a title error, got %q", failed.Error)
+	}
+	if status := api.do(t, "POST", "/notes", `{"title": "x", "colour": "red"}`, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown field, got %d", status)
+	}
+}
+
+func TestServerPagination(t *testing.T) {
+	api := newTestAPI(t)
+	api.notes.notesWithTags = createTestNotes()
+
+	tests := []struct {
+		query    string
+		expected []int
+		status   int
+	}{
+		{query: "", expected: []int{1, 2, 3}, status: http.StatusOK},
+		{query: "?limit=2", expected: []int{1, 2}, status: http.StatusOK},
+		{query: "?limit=2&offset=2", expected: []int{3}, status: http.StatusOK},
+		{query: "?offset=10", expected: []int{}, status: http.StatusOK},
+		{query: "?limit=abc", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var page server.Page[note.NoteWithTags]
+			status := api.do(t, "GET", "/notes"+tt.query, "", &page)
+			if status != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, status)
+			}
+			if status != http.StatusOK {
+				return
+			}
+
+			if page.Total != len(api.notes.notesWithTags) {
+				t.Errorf("Expected total %d, got %d", len(api.notes.notesWithTags), page.Total)
+			}
+			if len(page.Items) != len(tt.expected) {
+				t.Fatalf("Expected %d items, got %d", len(tt.expected), len(page.Items))
+			}
+			for i, id := range tt.expected {
+				if page.Items[i].ID != id {
+					t.Errorf("Expected item %d to be note #%d, got #%d", i, id, page.Items[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestServerProjectsAndTasks(t *testing.T) {
+	api := newTestAPI(t)
+
+	var p project.Project
+	if status := api.do(t, "POST", "/projects", `{"name": "Blog", "description": "Go blog"}`, &p); status != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", status)
+	}
+	if p.ID != 1 || p.Status != "active" {
+		t.Errorf("Expected an active project #1, got %+v", p)
+	}
+
+	if status := api.do(t, "GET", "/projects/9", "", nil); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing project, got %d", status)
+	}
+	if status := api.do(t, "PATCH", "/projects/1", `{"status": "done"}`, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid status, got %d", status)
+	}
+
+	if status := api.do(t, "POST", "/tasks", `{"project_id": 9, "title": "Orphan"}`, nil); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for a task in a missing project, got %d", status)
+	}
+	if status := api.do(t, "POST", "/tasks", `{"project_id": 1, "title": "Write", "due_date": "tomorrow"}`, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid due date, got %d", status)
+	}
+	if status := api.do(t, "POST", "/tasks", `{"project_id": 1, "title": "Write", "priority": "high", "due_date": "2026-11-01"}`, nil); status != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", status)
+	}
+	if len(api.tasks.tasks) != 1 || api.tasks.tasks[0].DueDate == nil || api.tasks.tasks[0].Priority != "high" {
+		t.Fatalf("Expected a high priority task with a due date, got %+v", api.tasks.tasks)
+	}
+
+	var page server.Page[map[string]any]
+	if status := api.do(t, "GET", "/projects/1/tasks?status=pending", "", &page); status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+	if page.Total != 1 || page.Items[0]["title"] != "Write" {
+		t.Errorf("Expected the project's task, got %+v", page)
+	}
+	if status := api.do(t, "GET", "/tasks?priority=low", "", &page); status != http.StatusOK || page.Total != 0 {
+		t.Errorf("Expected no low priority tasks, got %d with status %d", page.Total, status)
+	}
+}
+
+func TestServerChecklists(t *testing.T) {
+	api := newTestAPI(t)
+
+	status := api.do(t, "POST", "/checklists", `{"title": "Release", "items": ["Tag", "", "Publish"]}`, nil)
+	if status != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", status)
+	}
+	if status := api.do(t, "POST", "/checklists/1/items", `{"title": "Announce"}`, nil); status != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", status)
+	}
+
+	var c struct {
+		Title string `json:"title"`
+		Items []struct {
+			Title string `json:"title"`
+			Order int    `json:"order"`
+		} `json:"items"`
+	}
+	if status := api.do(t, "GET", "/checklists/1", "", &c); status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+	if len(c.Items) != 3 || c.Items[2].Title != "Announce" || c.Items[2].Order != 3 {
+		t.Errorf("Expected 3 items ending with Announce, got %+v", c.Items)
+	}
+
+	if status := api.do(t, "DELETE", "/checklist-items/7", "", nil); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing item, got %d", status)
+	}
+	if status := api.do(t, "DELETE", "/checklists/1", "", nil); status != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", status)
+	}
+}
+
+func TestServerOpenAPI(t *testing.T) {
+	api := newTestAPI(t)
+
+	var doc struct {
+		OpenAPI    string                               `json:"openapi"`
+		Paths      map[string]map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]any `json:"properties"`
+				Required   []string       `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if status := api.do(t, "GET", "/openapi.json", "", &doc); status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+
+	s := server.New(server.Repositories{}, "")
+	for _, route := range s.Routes() {
+		if _, ok := doc.Paths[route.Path][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("Expected %s %s in the document", route.Method, route.Path)
+		}
+	}
+
+	page, ok := doc.Components.Schemas["NoteWithTagsPage"]
+	if !ok || page.Properties["items"] == nil {
+		t.Errorf("Expected a NoteWithTagsPage schema with items, got %+v", page)
+	}
+	input := doc.Components.Schemas["TaskInput"]
+	if strings.Join(input.Required, ",") != "project_id,title" {
+		t.Errorf("Expected project_id and title to be required, got %v", input.Required)
+	}
+	if security, ok := doc.Paths["/health"]["get"]["security"].([]any); !ok || len(security) != 0 {
+		t.Errorf("Expected /health to need no token, got %v", doc.Paths["/health"]["get"]["security"])
+	}
+}
@@ -0,0 +1,178 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/checklist"
+	"github.com/snip/internal/database"
+	"github.com/snip/internal/handler"
+	"github.com/snip/internal/project"
+	"github.com/snip/internal/repository"
+	"github.com/snip/internal/task"
+)
+
+func TestParseTaskBreakdown(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		maxSteps    int
+		expectError bool
+		errorMsg    string
+		steps       int
+	}{
+		{
+			name: "valid breakdown",
+			raw: `{"steps": [
+				{"title": "Mapear endpoints", "description": "Listar rotas atuais", "priority": "High"},
+				{"title": "Escrever testes", "priority": ""},
+				{"title": "Migrar handlers", "priority": "low"}
+			]}`,
+			maxSteps: 8,
+			steps:    3,
+		},
+		{
+			name:     "steps beyond the limit are dropped",
+			raw:      `{"steps": [{"title": "a"}, {"title": "b"}, {"title": "c"}]}`,
+			maxSteps: 2,
+			steps:    2,
+		},
+		{
+			name:        "no steps",
+			raw:         `{"steps": []}`,
+			maxSteps:    8,
+			expectError: true,
+			errorMsg:    "no steps",
+		},
+		{
+			name:        "step without title",
+			raw:         `{"steps": [{"title": ""}]}`,
+			maxSteps:    8,
+			expectError: true,
+			errorMsg:    "has no title",
+		},
+		{
+			name:        "invalid priority",
+			raw:         `{"steps": [{"title": "a", "priority": "asap"}]}`,
+			maxSteps:    8,
+			expectError: true,
+			errorMsg:    "invalid priority",
+		},
+		{
+			name:        "plain text answer",
+			raw:         "1. Fazer isso\n2. Fazer aquilo",
+			maxSteps:    8,
+			expectError: true,
+			errorMsg:    "no JSON object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown, err := ai.ParseTaskBreakdown(tt.raw, tt.maxSteps)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+					return
+				}
+				if !errors.Is(err, ai.ErrInvalidBreakdown) {
+					t.Errorf("Expected ErrInvalidBreakdown, got %v", err)
+				}
+				if tt.errorMsg != "" && !contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.errorMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if len(breakdown.Steps) != tt.steps {
+				t.Errorf("Expected %d steps, got %d", tt.steps, len(breakdown.Steps))
+			}
+			for _, step := range breakdown.Steps {
+				if step.Priority != "low" && step.Priority != "medium" && step.Priority != "high" {
+					t.Errorf("Expected normalized priority, got %q", step.Priority)
+				}
+			}
+		})
+	}
+}
+
+// failingTaskRepository fails every Create after the first allow calls.
+type failingTaskRepository struct {
+	repository.TaskRepository
+	allow int
+}
+
+func (r *failingTaskRepository) Create(t *task.Task) error {
+	if r.allow == 0 {
+		return ErrDatabaseConnection
+	}
+	r.allow--
+	return r.TaskRepository.Create(t)
+}
+
+// failingChecklistItemRepository fails every Create after the first allow
+// calls.
+type failingChecklistItemRepository struct {
+	repository.ChecklistItemRepository
+	allow int
+}
+
+func (r *failingChecklistItemRepository) Create(item *checklist.ChecklistItem) error {
+	if r.allow == 0 {
+		return ErrDatabaseConnection
+	}
+	r.allow--
+	return r.ChecklistItemRepository.Create(item)
+}
+
+func TestBreakdownTaskWithAI_RollsBackOnFailure(t *testing.T) {
+	for _, as := range []string{"tasks", "checklist"} {
+		t.Run(as, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			newFakeGroq(t, fakeReply{content: `{"steps": [{"title": "Mapear endpoints"}, {"title": "Escrever testes"}, {"title": "Migrar handlers"}]}`})
+
+			db, err := database.Connect()
+			if err != nil {
+				t.Fatalf("Failed to open database: %v", err)
+			}
+			defer db.Close()
+			projectRepo, _ := repository.NewProjectRepository(db)
+			taskRepo, _ := repository.NewTaskRepository(db)
+			checklistRepo, _ := repository.NewChecklistRepository(db)
+			itemRepo, _ := repository.NewChecklistItemRepository(db)
+
+			p := project.NewProject("API", "")
+			if err := projectRepo.Create(p); err != nil {
+				t.Fatalf("Failed to create project: %v", err)
+			}
+			parent := task.NewTask(p.ID, "Migrar API", "", "high")
+			if err := taskRepo.Create(parent); err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+
+			h := handler.NewTaskHandler(&failingTaskRepository{TaskRepository: taskRepo, allow: 2}, projectRepo, checklistRepo, &failingChecklistItemRepository{ChecklistItemRepository: itemRepo, allow: 2})
+			if err := h.BreakdownTaskWithAI(parent.ID, as, 8, true); err == nil {
+				t.Fatal("Expected the third create to fail")
+			}
+
+			children, err := taskRepo.GetChildren(parent.ID)
+			if err != nil || len(children) != 0 {
+				t.Errorf("Expected the created subtasks to be removed, got %d (%v)", len(children), err)
+			}
+			checklists, err := checklistRepo.GetAll()
+			if err != nil || len(checklists) != 0 {
+				t.Errorf("Expected the checklist to be removed, got %d (%v)", len(checklists), err)
+			}
+			var items int
+			db.QueryRow(`SELECT COUNT(*) FROM checklist_items`).Scan(&items)
+			if items != 0 {
+				t.Errorf("Expected the checklist items to be removed, got %d", items)
+			}
+		})
+	}
+}
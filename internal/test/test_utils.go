@@ -1,14 +1,34 @@
 package test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"testing"
 	"time"
 
+	"github.com/snip/internal/ai"
+	"github.com/snip/internal/embedding"
+	"github.com/snip/internal/action"
+	"github.com/snip/internal/activity"
+	"github.com/snip/internal/chat"
+	"github.com/snip/internal/cache"
+	"github.com/snip/internal/checklist"
+	"github.com/snip/internal/dedupe"
 	"github.com/snip/internal/handler"
+	"github.com/snip/internal/link"
 	"github.com/snip/internal/note"
+	"github.com/snip/internal/project"
+	"github.com/snip/internal/repository"
 	"github.com/snip/internal/tag"
+	"github.com/snip/internal/task"
+	"github.com/snip/internal/usage"
 )
 
 type mockNoteRepository struct {
@@ -83,8 +103,7 @@ func (m *mockNoteRepository) Search(term string) ([]*note.Note, error) {
 
 	var results []*note.Note
 	for _, noteWithTags := range m.notesWithTags {
-		if strings.Contains(strings.ToLower(noteWithTags.Title), strings.ToLower(term)) ||
-			strings.Contains(strings.ToLower(noteWithTags.Content), strings.ToLower(term)) {
+		if matchesAnyTerm(noteWithTags, term) {
 			results = append(results, &note.Note{
 				ID:        noteWithTags.ID,
 				Title:     noteWithTags.Title,
@@ -97,6 +116,17 @@ func (m *mockNoteRepository) Search(term string) ([]*note.Note, error) {
 	return results, nil
 }
 
+// matchesAnyTerm mimics the FTS "a OR b" queries built by the handlers.
+func matchesAnyTerm(n *note.NoteWithTags, term string) bool {
+	for _, t := range strings.Split(term, " OR ") {
+		t = strings.ToLower(t)
+		if strings.Contains(strings.ToLower(n.Title), t) || strings.Contains(strings.ToLower(n.Content), t) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *mockNoteRepository) CheckByID(id int) error {
 	if m.err != nil {
 		return m.err
@@ -142,6 +172,33 @@ func (m *mockNoteRepository) GetRecent(limit int) ([]*note.NoteWithTags, error)
 	return m.notesWithTags[start:], nil
 }
 
+func (m *mockNoteRepository) Merge(keepID, dropID int, content string) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	var keep, drop *note.NoteWithTags
+	for _, note := range m.notesWithTags {
+		switch note.ID {
+		case keepID:
+			keep = note
+		case dropID:
+			drop = note
+		}
+	}
+	if keep == nil || drop == nil {
+		return ErrNoteNotFound
+	}
+
+	keep.Content = content
+	for _, t := range drop.Tags {
+		if !slices.Contains(keep.Tags, t) {
+			keep.Tags = append(keep.Tags, t)
+		}
+	}
+	return m.Delete(dropID)
+}
+
 func (m *mockNoteRepository) ExportNotes(exportDir string, since *time.Time, format string) error {
 	if m.err != nil {
 		return m.err
@@ -163,7 +220,16 @@ func (m *mockNoteRepository) RemoveTagFromNote(noteID int) error {
 }
 
 func (m *mockNoteRepository) GetTagsByNote(noteID int) ([]*tag.Tag, error) {
-	return nil, nil
+	var tags []*tag.Tag
+	for _, n := range m.notesWithTags {
+		if n.ID != noteID {
+			continue
+		}
+		for i, name := range n.Tags {
+			tags = append(tags, &tag.Tag{ID: i + 1, Name: name})
+		}
+	}
+	return tags, nil
 }
 
 func (m *mockNoteRepository) Close() error {
@@ -171,7 +237,8 @@ func (m *mockNoteRepository) Close() error {
 }
 
 type mockTagRepository struct {
-	err error
+	requested []string // names passed to GetOrCreate
+	err       error
 }
 
 func (m *mockTagRepository) Create(tag *tag.Tag) error {
@@ -218,6 +285,7 @@ func (m *mockTagRepository) GetOrCreate(name string) (*tag.Tag, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	m.requested = append(m.requested, name)
 	return &tag.Tag{
 		ID:   1,
 		Name: name,
@@ -228,14 +296,524 @@ func (m *mockTagRepository) Close() error {
 	return nil
 }
 
-func createTestHandler() (handler.Handler, *mockNoteRepository, *mockTagRepository) {
+type mockEmbeddingRepository struct {
+	embeddings map[int]*embedding.Embedding
+	err        error
+}
+
+func (m *mockEmbeddingRepository) Upsert(e *embedding.Embedding) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.embeddings == nil {
+		m.embeddings = make(map[int]*embedding.Embedding)
+	}
+	m.embeddings[e.NoteID] = e
+	return nil
+}
+
+func (m *mockEmbeddingRepository) GetAll(provider string) ([]*embedding.Embedding, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var result []*embedding.Embedding
+	for _, e := range m.embeddings {
+		if e.Provider == provider {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockEmbeddingRepository) Delete(noteID int) error {
+	if m.err != nil {
+		return m.err
+	}
+	delete(m.embeddings, noteID)
+	return nil
+}
+
+func (m *mockEmbeddingRepository) Close() error {
+	return nil
+}
+
+type mockUsageRepository struct {
+	summaries []*usage.Summary
+	created   []*usage.Usage
+	groupBy   string
+	err       error
+}
+
+func (m *mockUsageRepository) Create(u *usage.Usage) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.created = append(m.created, u)
+	return nil
+}
+
+func (m *mockUsageRepository) Summarize(since time.Time, groupBy string) ([]*usage.Summary, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.groupBy = groupBy
+	return m.summaries, nil
+}
+
+func (m *mockUsageRepository) Close() error {
+	return nil
+}
+
+type mockProjectRepository struct {
+	projects []*project.Project
+	err      error
+}
+
+func (m *mockProjectRepository) Create(p *project.Project) error {
+	if m.err != nil {
+		return m.err
+	}
+	p.ID = len(m.projects) + 1
+	m.projects = append(m.projects, p)
+	return nil
+}
+
+func (m *mockProjectRepository) GetByID(id int) (*project.Project, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, p := range m.projects {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, repository.ErrProjectNotFound
+}
+
+func (m *mockProjectRepository) GetAll(status string) ([]*project.Project, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*project.Project
+	for _, p := range m.projects {
+		if status == "" || p.Status == status {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockProjectRepository) Update(id int, name, description, status string) error {
+	return m.err
+}
+
+func (m *mockProjectRepository) Delete(id int) error {
+	return m.err
+}
+
+func (m *mockProjectRepository) Close() error {
+	return nil
+}
+
+type mockTaskRepository struct {
+	tasks []*task.Task
+	err   error
+}
+
+func (m *mockTaskRepository) Create(t *task.Task) error {
+	if m.err != nil {
+		return m.err
+	}
+	t.ID = len(m.tasks) + 1
+	m.tasks = append(m.tasks, t)
+	return nil
+}
+
+func (m *mockTaskRepository) GetByID(id int) (*task.Task, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, t := range m.tasks {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, repository.ErrTaskNotFound
+}
+
+func (m *mockTaskRepository) GetByProjectID(projectID int, status string) ([]*task.Task, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*task.Task
+	for _, t := range m.tasks {
+		if t.ProjectID == projectID && (status == "" || t.Status == status) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockTaskRepository) GetAll(status string) ([]*task.Task, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*task.Task
+	for _, t := range m.tasks {
+		if status == "" || t.Status == status {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockTaskRepository) Update(id int, title, description, status, priority string, dueDate *time.Time) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, t := range m.tasks {
+		if t.ID == id {
+			t.Title, t.Description, t.Status, t.Priority, t.DueDate = title, description, status, priority, dueDate
+		}
+	}
+	return nil
+}
+
+func (m *mockTaskRepository) Delete(id int) error {
+	if m.err != nil {
+		return m.err
+	}
+	for i, t := range m.tasks {
+		if t.ID == id {
+			m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *mockTaskRepository) ToggleComplete(id int) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, t := range m.tasks {
+		if t.ID == id {
+			if t.Status == "completed" {
+				t.Status = "pending"
+			} else {
+				t.Status = "completed"
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockTaskRepository) GetChildren(parentID int) ([]*task.Task, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*task.Task
+	for _, t := range m.tasks {
+		if t.ParentID != nil && *t.ParentID == parentID {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockTaskRepository) Close() error {
+	return nil
+}
+
+type mockLinkRepository struct {
+	links []*link.Link
+	err   error
+}
+
+func (m *mockLinkRepository) Create(l *link.Link) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.links = append(m.links, l)
+	return nil
+}
+
+func (m *mockLinkRepository) GetByNote(noteID int) ([]*link.Link, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*link.Link
+	for _, l := range m.links {
+		if l.NoteID == noteID {
+			result = append(result, l)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockLinkRepository) GetBacklinks(targetType string, targetID int) ([]*link.Link, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*link.Link
+	for _, l := range m.links {
+		if l.TargetType == targetType && l.TargetID == targetID {
+			result = append(result, l)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockLinkRepository) Close() error {
+	return nil
+}
+
+type mockChatRepository struct {
+	sessions []*chat.Session
+	messages []*chat.Message
+	err      error
+}
+
+func (m *mockChatRepository) CreateSession(s *chat.Session) error {
+	if m.err != nil {
+		return m.err
+	}
+	s.ID = len(m.sessions) + 1
+	m.sessions = append(m.sessions, s)
+	return nil
+}
+
+func (m *mockChatRepository) GetSession(id int) (*chat.Session, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, s := range m.sessions {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, repository.ErrSessionNotFound
+}
+
+func (m *mockChatRepository) ListSessions(limit int) ([]*chat.Session, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if len(m.sessions) > limit {
+		return m.sessions[:limit], nil
+	}
+	return m.sessions, nil
+}
+
+func (m *mockChatRepository) UpdateSession(id int, title string) error {
+	return m.err
+}
+
+func (m *mockChatRepository) AddMessage(msg *chat.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	msg.ID = len(m.messages) + 1
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func (m *mockChatRepository) GetMessages(sessionID int) ([]*chat.Message, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*chat.Message
+	for _, msg := range m.messages {
+		if msg.SessionID == sessionID {
+			result = append(result, msg)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockChatRepository) Close() error {
+	return nil
+}
+
+type mockActionRepository struct {
+	actions []*action.Action
+	err     error
+}
+
+func (m *mockActionRepository) Create(a *action.Action) error {
+	if m.err != nil {
+		return m.err
+	}
+	a.ID = len(m.actions) + 1
+	m.actions = append(m.actions, a)
+	return nil
+}
+
+func (m *mockActionRepository) GetRecent(limit int) ([]*action.Action, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if len(m.actions) > limit {
+		return m.actions[:limit], nil
+	}
+	return m.actions, nil
+}
+
+func (m *mockActionRepository) Close() error {
+	return nil
+}
+
+type mockChecklistItemRepository struct {
+	items []*checklist.ChecklistItem
+	err   error
+}
+
+func (m *mockChecklistItemRepository) Create(item *checklist.ChecklistItem) error {
+	if m.err != nil {
+		return m.err
+	}
+	item.ID = len(m.items) + 1
+	m.items = append(m.items, item)
+	return nil
+}
+
+func (m *mockChecklistItemRepository) GetByID(id int) (*checklist.ChecklistItem, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, item := range m.items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return nil, repository.ErrChecklistItemNotFound
+}
+
+func (m *mockChecklistItemRepository) GetByChecklistID(checklistID int) ([]*checklist.ChecklistItem, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*checklist.ChecklistItem
+	for _, item := range m.items {
+		if item.ChecklistID == checklistID {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockChecklistItemRepository) Update(id int, title, description string, completed bool) error {
+	return m.err
+}
+
+func (m *mockChecklistItemRepository) ToggleComplete(id int) error {
+	return m.err
+}
+
+func (m *mockChecklistItemRepository) Delete(id int) error {
+	return m.err
+}
+
+func (m *mockChecklistItemRepository) Close() error {
+	return nil
+}
+
+type mockActivityRepository struct {
+	entries []*activity.TaskActivity
+	err     error
+}
+
+func (m *mockActivityRepository) GetSince(since time.Time, projectID int) ([]*activity.TaskActivity, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*activity.TaskActivity
+	for _, e := range m.entries {
+		if !e.CreatedAt.Before(since) && (projectID == 0 || e.ProjectID == projectID) {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockActivityRepository) Close() error {
+	return nil
+}
+
+func createTestReportHandler() (handler.ReportHandler, *mockNoteRepository, *mockProjectRepository, *mockTaskRepository, *mockActivityRepository) {
+	mockNoteRepo := &mockNoteRepository{}
+	mockProjectRepo := &mockProjectRepository{}
+	mockTaskRepo := &mockTaskRepository{}
+	mockActivityRepo := &mockActivityRepository{}
+
+	h := handler.NewReportHandler(mockTaskRepo, mockProjectRepo, mockActivityRepo, mockNoteRepo, &mockTagRepository{})
+	return h, mockNoteRepo, mockProjectRepo, mockTaskRepo, mockActivityRepo
+}
+
+func createTestToolRunner() (*handler.ToolRunner, *mockNoteRepository, *mockTaskRepository, *mockActionRepository) {
+	mockNoteRepo := &mockNoteRepository{}
+	mockProjectRepo := &mockProjectRepository{}
+	mockTaskRepo := &mockTaskRepository{}
+	mockChecklistItemRepo := &mockChecklistItemRepository{}
+	mockActionRepo := &mockActionRepository{}
+
+	taskHandler := handler.NewTaskHandler(mockTaskRepo, mockProjectRepo, nil, mockChecklistItemRepo)
+	r := handler.NewToolRunner(mockNoteRepo, mockProjectRepo, mockTaskRepo, mockChecklistItemRepo, mockActionRepo, taskHandler, nil)
+	return r, mockNoteRepo, mockTaskRepo, mockActionRepo
+}
+
+func createTestChatHandler() (handler.ChatHandler, *mockChatRepository) {
+	mockChatRepo := &mockChatRepository{}
+
+	h := handler.NewChatHandler(&mockNoteRepository{}, &mockTagRepository{}, mockChatRepo, &mockLinkRepository{}, nil)
+	return h, mockChatRepo
+}
+
+func createTestActionItemHandler() (handler.ActionItemHandler, *mockNoteRepository, *mockProjectRepository, *mockTaskRepository) {
+	mockNoteRepo := &mockNoteRepository{}
+	mockProjectRepo := &mockProjectRepository{}
+	mockTaskRepo := &mockTaskRepository{}
+
+	h := handler.NewActionItemHandler(mockNoteRepo, mockProjectRepo, mockTaskRepo)
+	return h, mockNoteRepo, mockProjectRepo, mockTaskRepo
+}
+
+func createTestSummaryHandler() (handler.SummaryHandler, *mockNoteRepository, *mockTagRepository, *mockProjectRepository, *mockTaskRepository) {
 	mockNoteRepo := &mockNoteRepository{}
 	mockTagRepo := &mockTagRepository{}
+	mockProjectRepo := &mockProjectRepository{}
+	mockTaskRepo := &mockTaskRepository{}
 
-	h := handler.NewHandler(mockNoteRepo, mockTagRepo)
+	h := handler.NewSummaryHandler(mockNoteRepo, mockTagRepo, mockProjectRepo, mockTaskRepo, &mockLinkRepository{})
+	return h, mockNoteRepo, mockTagRepo, mockProjectRepo, mockTaskRepo
+}
+
+func createTestAIHandler() (handler.AIHandler, *mockUsageRepository) {
+	h, mockUsageRepo, _ := createTestAIHandlerWithCache()
+	return h, mockUsageRepo
+}
+
+func createTestAIHandlerWithCache() (handler.AIHandler, *mockUsageRepository, *mockCacheRepository) {
+	mockUsageRepo := &mockUsageRepository{}
+	mockCacheRepo := &mockCacheRepository{}
+	return handler.NewAIHandler(mockUsageRepo, &mockActionRepository{}, mockCacheRepo), mockUsageRepo, mockCacheRepo
+}
+
+func createTestHandler() (handler.Handler, *mockNoteRepository, *mockTagRepository) {
+	h, mockNoteRepo, mockTagRepo, _ := createTestHandlerWithEmbeddings()
 	return h, mockNoteRepo, mockTagRepo
 }
 
+func createTestHandlerWithEmbeddings() (handler.Handler, *mockNoteRepository, *mockTagRepository, *mockEmbeddingRepository) {
+	mockNoteRepo := &mockNoteRepository{}
+	mockTagRepo := &mockTagRepository{}
+	mockEmbeddingRepo := &mockEmbeddingRepository{}
+
+	h := handler.NewHandler(mockNoteRepo, mockTagRepo, mockEmbeddingRepo)
+	return h, mockNoteRepo, mockTagRepo, mockEmbeddingRepo
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -311,3 +889,285 @@ func createTestNotesWithTag(tagName string) []*note.NoteWithTags {
 
 	return filtered
 }
+
+// fakeGroq is an httptest server speaking the chat completions API. It
+// answers with its replies in order, as server-sent events when the request
+// asks for a stream, and keeps every request it received.
+type fakeGroq struct {
+	server   *httptest.Server
+	requests []ai.ChatRequest
+	replies  []fakeReply
+}
+
+type fakeReply struct {
+	status  int    // defaults to 200
+	content string // assistant message content
+	body    string // raw response body, used instead of content
+	header  map[string]string
+	hang    bool // keep the connection open after body until the client cancels
+}
+
+// newFakeGroq starts the server and points new AI clients at it. Create
+// handlers after calling it: they build their client on construction.
+func newFakeGroq(t *testing.T, replies ...fakeReply) *fakeGroq {
+	t.Helper()
+	f := &fakeGroq{replies: replies}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.server.Close)
+
+	t.Setenv("GROQ_API_KEY", "test-key")
+	t.Setenv("GROQ_API_URL", f.server.URL)
+	t.Setenv("SNIP_AI_MAX_ATTEMPTS", "1")
+	return f
+}
+
+func (f *fakeGroq) serve(w http.ResponseWriter, r *http.Request) {
+	var req ai.ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": {"message": "invalid request body"}}`, http.StatusBadRequest)
+		return
+	}
+	f.requests = append(f.requests, req)
+
+	if len(f.requests) > len(f.replies) {
+		http.Error(w, `{"error": {"message": "unexpected request"}}`, http.StatusBadRequest)
+		return
+	}
+	reply := f.replies[len(f.requests)-1]
+
+	for k, v := range reply.header {
+		w.Header().Set(k, v)
+	}
+	status := reply.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	switch {
+	case reply.body != "":
+		w.WriteHeader(status)
+		fmt.Fprint(w, reply.body)
+		if reply.hang {
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	case req.Stream:
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(status)
+		for _, word := range strings.SplitAfter(reply.content, " ") {
+			fmt.Fprintf(w, "data: %s\n\n", streamChunk(word))
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []any{map[string]any{"index": 0, "message": map[string]string{"role": "assistant", "content": reply.content}}},
+			"usage":   map[string]int{"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15},
+		})
+	}
+}
+
+// streamChunk is one "data:" payload of a streamed completion.
+func streamChunk(content string) string {
+	chunk, _ := json.Marshal(map[string]any{
+		"choices": []any{map[string]any{"index": 0, "delta": map[string]string{"content": content}}},
+	})
+	return string(chunk)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type mockCacheRepository struct {
+	entries map[string]*cache.Entry
+	err     error
+}
+
+func (m *mockCacheRepository) Get(key string, since time.Time) (*cache.Entry, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	e, ok := m.entries[key]
+	if !ok || e.CreatedAt.Before(since) {
+		return nil, repository.ErrCacheMiss
+	}
+	e.Hits++
+	return e, nil
+}
+
+func (m *mockCacheRepository) Put(e *cache.Entry) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.entries == nil {
+		m.entries = make(map[string]*cache.Entry)
+	}
+	m.entries[e.Key] = e
+	return nil
+}
+
+func (m *mockCacheRepository) Stats(since time.Time) (*cache.Stats, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	s := &cache.Stats{}
+	for _, e := range m.entries {
+		s.Entries++
+		if !e.CreatedAt.Before(since) {
+			s.Fresh++
+		}
+		s.Hits += e.Hits
+		s.Bytes += int64(len(e.Response))
+	}
+	return s, nil
+}
+
+func (m *mockCacheRepository) Clear(before time.Time) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	var deleted int64
+	for key, e := range m.entries {
+		if before.IsZero() || e.CreatedAt.Before(before) {
+			delete(m.entries, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *mockCacheRepository) Close() error {
+	return nil
+}
+
+type mockChecklistRepository struct {
+	checklists []*checklist.Checklist
+	err        error
+}
+
+func (m *mockChecklistRepository) Create(c *checklist.Checklist) error {
+	if m.err != nil {
+		return m.err
+	}
+	c.ID = len(m.checklists) + 1
+	m.checklists = append(m.checklists, c)
+	return nil
+}
+
+func (m *mockChecklistRepository) GetByID(id int) (*checklist.Checklist, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, c := range m.checklists {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, repository.ErrChecklistNotFound
+}
+
+func (m *mockChecklistRepository) GetByTaskID(taskID int) ([]*checklist.Checklist, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*checklist.Checklist
+	for _, c := range m.checklists {
+		if c.TaskID != nil && *c.TaskID == taskID {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockChecklistRepository) GetByProjectID(projectID int) ([]*checklist.Checklist, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []*checklist.Checklist
+	for _, c := range m.checklists {
+		if c.ProjectID != nil && *c.ProjectID == projectID {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockChecklistRepository) GetAll() ([]*checklist.Checklist, error) {
+	return m.checklists, m.err
+}
+
+func (m *mockChecklistRepository) Update(id int, title, description string) error {
+	return m.err
+}
+
+func (m *mockChecklistRepository) Delete(id int) error {
+	return m.err
+}
+
+func (m *mockChecklistRepository) Close() error {
+	return nil
+}
+
+func createTestChecklistHandler() (handler.ChecklistHandler, *mockChecklistRepository, *mockChecklistItemRepository) {
+	mockChecklistRepo := &mockChecklistRepository{}
+	mockChecklistItemRepo := &mockChecklistItemRepository{}
+
+	h := handler.NewChecklistHandler(mockChecklistRepo, mockChecklistItemRepo)
+	return h, mockChecklistRepo, mockChecklistItemRepo
+}
+
+func createTestProjectHandler() (handler.ProjectHandler, *mockProjectRepository, *mockTaskRepository, *mockChecklistRepository, *mockChecklistItemRepository) {
+	mockProjectRepo := &mockProjectRepository{}
+	mockTaskRepo := &mockTaskRepository{}
+	mockChecklistRepo := &mockChecklistRepository{}
+	mockChecklistItemRepo := &mockChecklistItemRepository{}
+
+	h := handler.NewProjectHandler(mockProjectRepo, mockTaskRepo, mockChecklistRepo, mockChecklistItemRepo)
+	return h, mockProjectRepo, mockTaskRepo, mockChecklistRepo, mockChecklistItemRepo
+}
+
+// lastPrompt is the content of the last message of request i.
+func (f *fakeGroq) lastPrompt(i int) string {
+	messages := f.requests[i].Messages
+	return messages[len(messages)-1].Content
+}
+
+type mockDedupeRepository struct {
+	ignored []*dedupe.IgnoredPair
+	err     error
+}
+
+func (m *mockDedupeRepository) Ignore(p *dedupe.IgnoredPair) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.ignored = append(m.ignored, p)
+	return nil
+}
+
+func (m *mockDedupeRepository) GetIgnored() ([]*dedupe.IgnoredPair, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.ignored, nil
+}
+
+func (m *mockDedupeRepository) Close() error {
+	return nil
+}
+
+func createTestDedupeHandler() (handler.DedupeHandler, *mockNoteRepository, *mockDedupeRepository) {
+	mockNoteRepo := &mockNoteRepository{}
+	mockDedupeRepo := &mockDedupeRepository{}
+
+	h := handler.NewDedupeHandler(mockNoteRepo, mockDedupeRepo)
+	return h, mockNoteRepo, mockDedupeRepo
+}
@@ -0,0 +1,139 @@
+package test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/snip/internal/handler"
+	"github.com/snip/internal/project"
+	"github.com/snip/internal/task"
+	"github.com/snip/internal/tui"
+)
+
+type testTUI struct {
+	model    *tui.Model
+	notes    *mockNoteRepository
+	projects *mockProjectRepository
+	tasks    *mockTaskRepository
+}
+
+func newTestTUI() *testTUI {
+	ui := &testTUI{
+		notes:    &mockNoteRepository{notesWithTags: createTestNotes()},
+		projects: &mockProjectRepository{projects: []*project.Project{{ID: 1, Name: "Site", Status: "active"}}},
+		tasks: &mockTaskRepository{tasks: []*task.Task{
+			{ID: 1, ProjectID: 1, Title: "Ship", Status: "pending", Priority: "high"},
+			{ID: 2, ProjectID: 1, Title: "Review", Status: "in_progress", Priority: "medium"},
+		}},
+	}
+	ui.model = tui.NewModel(tui.Repositories{
+		Notes:    ui.notes,
+		Tags:     &mockTagRepository{},
+		Projects: ui.projects,
+		Tasks:    ui.tasks,
+	}, handler.NewEditorHandler())
+	ui.model.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	return ui
+}
+
+// press sends keys one at a time; anything that is not a named key is typed
+// as text.
+func (ui *testTUI) press(keys ...string) string {
+	named := map[string]tea.KeyType{
+		"enter":  tea.KeyEnter,
+		"esc":    tea.KeyEsc,
+		"tab":    tea.KeyTab,
+		"space":  tea.KeySpace,
+		"ctrl+u": tea.KeyCtrlU,
+	}
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if keyType, ok := named[key]; ok {
+			msg = tea.KeyMsg{Type: keyType}
+		}
+		ui.model.Update(msg)
+	}
+	return ui.model.View()
+}
+
+func TestTUINotesSearchAndTags(t *testing.T) {
+	ui := newTestTUI()
+
+	view := ui.press()
+	for _, expected := range []string{"3 of 3 notes", "First Note", "Third Note", "#meeting"} {
+		if !contains(view, expected) {
+			t.Errorf("Expected %q in the notes screen, got:\n%s", expected, view)
+		}
+	}
+
+	view = ui.press("/", "meet")
+	if !contains(view, "1 of 3 notes") || !contains(view, "Third Note") || contains(view, "Second Note") {
+		t.Errorf("Expected only the meeting note while searching, got:\n%s", view)
+	}
+	view = ui.press("esc")
+	if !contains(view, "3 of 3 notes") {
+		t.Errorf("Expected esc to clear the search, got:\n%s", view)
+	}
+
+	// Tags are sorted by name: All, important, meeting, personal, work.
+	view = ui.press("t", "j", "j", "j")
+	if !contains(view, "1 of 3 notes · #personal") || !contains(view, "Second Note") || contains(view, "First Note") {
+		t.Errorf("Expected the personal tag filter, got:\n%s", view)
+	}
+	view = ui.press("g")
+	if !contains(view, "3 of 3 notes") {
+		t.Errorf("Expected All to clear the tag filter, got:\n%s", view)
+	}
+}
+
+func TestTUIRenameAndDeleteNote(t *testing.T) {
+	ui := newTestTUI()
+
+	ui.press("r", "ctrl+u", "Renamed", "enter")
+	if ui.notes.notesWithTags[0].Title != "Renamed" {
+		t.Errorf("Expected note 1 to be renamed, got %q", ui.notes.notesWithTags[0].Title)
+	}
+
+	if view := ui.press("d"); !contains(view, `Delete note #1 "Renamed"? (y/N)`) {
+		t.Errorf("Expected a confirmation, got:\n%s", view)
+	}
+	if view := ui.press("n"); !contains(view, "Cancelled.") || len(ui.notes.notesWithTags) != 3 {
+		t.Errorf("Expected the delete to be cancelled, got:\n%s", view)
+	}
+
+	view := ui.press("d", "y")
+	if len(ui.notes.notesWithTags) != 2 || !contains(view, "Note #1 deleted.") || !contains(view, "2 of 2 notes") {
+		t.Errorf("Expected note 1 to be deleted, got:\n%s", view)
+	}
+}
+
+func TestTUIBoard(t *testing.T) {
+	ui := newTestTUI()
+
+	view := ui.press("tab")
+	for _, expected := range []string{"Projeto #1 Site", "Pendentes (1)", "Em andamento (1)", "Concluídas (0)", "#1 Ship"} {
+		if !contains(view, expected) {
+			t.Errorf("Expected %q on the board, got:\n%s", expected, view)
+		}
+	}
+
+	// The selection follows the task to its new column.
+	view = ui.press("space")
+	if ui.tasks.tasks[0].Status != "completed" || !contains(view, "Concluídas (1)") {
+		t.Errorf("Expected task 1 to be completed, got:\n%s", view)
+	}
+	ui.press("<")
+	if ui.tasks.tasks[0].Status != "in_progress" {
+		t.Errorf("Expected task 1 to move back to in_progress, got %s", ui.tasks.tasks[0].Status)
+	}
+
+	view = ui.press("n", "Deploy", "enter")
+	if len(ui.tasks.tasks) != 3 || ui.tasks.tasks[2].Status != "in_progress" || !contains(view, "Em andamento (3)") {
+		t.Errorf("Expected a new task in the current column, got %+v", ui.tasks.tasks[len(ui.tasks.tasks)-1])
+	}
+
+	view = ui.press("d", "s")
+	if len(ui.tasks.tasks) != 2 || !contains(view, "Tarefa #3 excluída.") {
+		t.Errorf("Expected the new task to be deleted, got:\n%s", view)
+	}
+}
@@ -0,0 +1,307 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/snip/internal/project"
+	"github.com/snip/internal/task"
+)
+
+// The board has one column per task status, in this order.
+var (
+	boardStatuses = [...]string{"pending", "in_progress", "completed"}
+	boardLabels   = [...]string{"Pendentes", "Em andamento", "Concluídas"}
+)
+
+type boardState struct {
+	projects []*project.Project
+	project  int
+	columns  [len(boardStatuses)][]*task.Task
+	column   int
+	cursors  [len(boardStatuses)]int
+	offsets  [len(boardStatuses)]int
+	loadErr  error
+}
+
+// reloadBoard fetches the projects and the tasks of the current one,
+// keeping the same project and task selected when they still exist.
+func (m *Model) reloadBoard() {
+	b := &m.board
+	projectID, taskID := 0, 0
+	if p := b.currentProject(); p != nil {
+		projectID = p.ID
+	}
+	if t := b.selected(); t != nil {
+		taskID = t.ID
+	}
+
+	projects, err := m.repos.Projects.GetAll("")
+	if err != nil {
+		b.loadErr = fmt.Errorf("failed to fetch projects: %w", err)
+		return
+	}
+	b.loadErr = nil
+	b.projects = projects
+	b.project = 0
+	for i, p := range projects {
+		if p.ID == projectID {
+			b.project = i
+		}
+	}
+	m.loadTasks(taskID)
+}
+
+// loadTasks fills the columns with the current project's tasks and
+// selects taskID, moving to its column.
+func (m *Model) loadTasks(taskID int) {
+	b := &m.board
+	b.columns = [len(boardStatuses)][]*task.Task{}
+	p := b.currentProject()
+	if p == nil {
+		return
+	}
+
+	tasks, err := m.repos.Tasks.GetByProjectID(p.ID, "")
+	if err != nil {
+		b.loadErr = fmt.Errorf("failed to fetch tasks: %w", err)
+		return
+	}
+	for _, t := range tasks {
+		col := statusColumn(t.Status)
+		b.columns[col] = append(b.columns[col], t)
+		if t.ID == taskID {
+			b.column, b.cursors[col] = col, len(b.columns[col])-1
+		}
+	}
+	for col := range b.columns {
+		b.cursors[col] = max(min(b.cursors[col], len(b.columns[col])-1), 0)
+	}
+}
+
+// statusColumn is the column for status; unknown statuses count as pending.
+func statusColumn(status string) int {
+	for i, s := range boardStatuses {
+		if s == status {
+			return i
+		}
+	}
+	return 0
+}
+
+func (b *boardState) currentProject() *project.Project {
+	if b.project < 0 || b.project >= len(b.projects) {
+		return nil
+	}
+	return b.projects[b.project]
+}
+
+func (b *boardState) selected() *task.Task {
+	tasks := b.columns[b.column]
+	if c := b.cursors[b.column]; c < len(tasks) {
+		return tasks[c]
+	}
+	return nil
+}
+
+func (b *boardState) summary() string {
+	p := b.currentProject()
+	if p == nil {
+		return ""
+	}
+	total := 0
+	for _, tasks := range b.columns {
+		total += len(tasks)
+	}
+	return fmt.Sprintf("Projeto %d/%d · %d tarefa(s)", b.project+1, len(b.projects), total)
+}
+
+var boardKeys = [][2]string{
+	{"h/l", "column"},
+	{"j/k", "move"},
+	{"space", "done"},
+	{"</>", "status"},
+	{"n", "new"},
+	{"tab", "notes"},
+	{"[/]", "project"},
+	{"e", "edit"},
+	{"d", "delete"},
+	{"q", "quit"},
+}
+
+func (m *Model) handleBoardKey(key string) tea.Cmd {
+	b := &m.board
+	switch key {
+	case "[", "]":
+		if len(b.projects) == 0 {
+			return nil
+		}
+		if key == "]" {
+			b.project = (b.project + 1) % len(b.projects)
+		} else {
+			b.project = (b.project + len(b.projects) - 1) % len(b.projects)
+		}
+		b.column, b.cursors, b.offsets = 0, [len(boardStatuses)]int{}, [len(boardStatuses)]int{}
+		m.loadTasks(0)
+	case "h", "left":
+		b.column = max(b.column-1, 0)
+	case "l", "right":
+		b.column = min(b.column+1, len(boardStatuses)-1)
+	case "j", "down":
+		b.cursors[b.column] = min(b.cursors[b.column]+1, max(len(b.columns[b.column])-1, 0))
+	case "k", "up":
+		b.cursors[b.column] = max(b.cursors[b.column]-1, 0)
+	case " ", "x":
+		t := b.selected()
+		if t == nil {
+			return nil
+		}
+		if err := m.repos.Tasks.ToggleComplete(t.ID); err != nil {
+			m.setError(err)
+			return nil
+		}
+		m.reloadBoard()
+	case "<", ">":
+		t := b.selected()
+		if t == nil {
+			return nil
+		}
+		col := statusColumn(t.Status) + 1
+		if key == "<" {
+			col -= 2
+		}
+		if col < 0 || col >= len(boardStatuses) {
+			return nil
+		}
+		if err := m.repos.Tasks.Update(t.ID, t.Title, t.Description, boardStatuses[col], t.Priority, t.DueDate); err != nil {
+			m.setError(err)
+			return nil
+		}
+		m.reloadBoard()
+	case "n":
+		p := b.currentProject()
+		if p == nil {
+			m.setStatus("Nenhum projeto encontrado. Crie um com snip project create.")
+			return nil
+		}
+		status := boardStatuses[b.column]
+		return m.ask("Título", "", func(title string) tea.Cmd {
+			if title == "" {
+				m.setStatus("Cancelado.")
+				return nil
+			}
+			t := task.NewTask(p.ID, title, "", "medium")
+			t.Status = status
+			if err := m.repos.Tasks.Create(t); err != nil {
+				m.setError(fmt.Errorf("failed to create task: %w", err))
+				return nil
+			}
+			m.loadTasks(t.ID)
+			m.setStatus(fmt.Sprintf("Tarefa #%d criada.", t.ID))
+			return nil
+		})
+	case "e", "enter":
+		t := b.selected()
+		if t == nil {
+			return nil
+		}
+		return m.edit(t.Description, func(content string) error {
+			description := strings.TrimSpace(content)
+			if err := m.repos.Tasks.Update(t.ID, t.Title, description, t.Status, t.Priority, t.DueDate); err != nil {
+				return fmt.Errorf("failed to update task: %w", err)
+			}
+			m.reloadBoard()
+			m.setStatus(fmt.Sprintf("Tarefa #%d atualizada.", t.ID))
+			return nil
+		})
+	case "d", "delete":
+		t := b.selected()
+		if t == nil {
+			return nil
+		}
+		m.askConfirm(fmt.Sprintf("Excluir tarefa #%d %q? (s/N)", t.ID, t.Title), func() tea.Cmd {
+			if err := m.repos.Tasks.Delete(t.ID); err != nil {
+				m.setError(err)
+				return nil
+			}
+			m.reloadBoard()
+			m.setStatus(fmt.Sprintf("Tarefa #%d excluída.", t.ID))
+			return nil
+		})
+	}
+	return nil
+}
+
+func (m *Model) boardView() string {
+	b := &m.board
+	height := max(m.height-2, 4)
+
+	if b.loadErr != nil {
+		return panel("Board", errorStyle.Render(b.loadErr.Error()), m.width, height, true)
+	}
+	p := b.currentProject()
+	if p == nil {
+		return panel("Board", mutedStyle.Render("Nenhum projeto encontrado. Crie um com snip project create."), m.width, height, false)
+	}
+
+	// One line for the project name, the rest split between the columns.
+	projectLine := headingStyle.Render(fit(fmt.Sprintf("Projeto #%d %s", p.ID, p.Name), m.width))
+	if p.Description != "" {
+		projectLine += mutedStyle.Render(fit("  "+p.Description, m.width-lipgloss.Width(projectLine)))
+	}
+
+	width := m.width / len(boardStatuses)
+	rows := max(height-4, 1)
+	columns := make([]string, len(boardStatuses))
+	for col, tasks := range b.columns {
+		w := width
+		if col == len(boardStatuses)-1 {
+			w = m.width - width*(len(boardStatuses)-1)
+		}
+		b.offsets[col] = scroll(b.offsets[col], b.cursors[col], rows)
+
+		var lines []string
+		for i := b.offsets[col]; i < len(tasks) && i < b.offsets[col]+rows; i++ {
+			lines = append(lines, taskLine(tasks[i], col == b.column && i == b.cursors[col], w-2))
+		}
+		title := fmt.Sprintf("%s (%d)", boardLabels[col], len(tasks))
+		columns[col] = panel(title, strings.Join(lines, "\n"), w, height-1, col == b.column)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, projectLine, lipgloss.JoinHorizontal(lipgloss.Top, columns...))
+}
+
+func taskLine(t *task.Task, selected bool, width int) string {
+	icon := "○"
+	switch t.Status {
+	case "completed":
+		icon = "✓"
+	case "in_progress":
+		icon = "◐"
+	}
+	prefix := "  "
+	if selected {
+		prefix = "› "
+	}
+	if t.ParentID != nil {
+		prefix += "↳ "
+	}
+
+	text := fmt.Sprintf("%s%s #%d %s", prefix, icon, t.ID, t.Title)
+	if t.Priority == "high" {
+		text += " !"
+	}
+	if t.DueDate != nil {
+		text += " " + t.DueDate.Format("2006-01-02")
+	}
+	text = fit(text, width)
+
+	switch {
+	case selected:
+		return selectedStyle.Render(text)
+	case t.Status == "completed":
+		return doneStyle.Render(text)
+	}
+	return text
+}
@@ -0,0 +1,461 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	markdown "github.com/MichaelMure/go-term-markdown"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/snip/internal/note"
+)
+
+const markdownPad = 1
+
+type notesFocus int
+
+const (
+	focusList notesFocus = iota
+	focusTags
+)
+
+type tagCount struct {
+	name  string
+	count int
+}
+
+type notesState struct {
+	all     []*note.NoteWithTags
+	visible []*note.NoteWithTags
+	tags    []tagCount
+	loadErr error
+
+	cursor    int
+	offset    int
+	tagCursor int // 0 is "All"
+	focus     notesFocus
+
+	search    textinput.Model
+	searching bool
+
+	preview     viewport.Model
+	previewFor  previewKey
+	listRows    int
+	previewRows int
+}
+
+// previewKey identifies what the preview was last rendered from, so the
+// Markdown is only rendered again when the note or the width changes.
+type previewKey struct {
+	id      int
+	content string
+	width   int
+}
+
+// notesLayout is the size of each panel on the notes screen.
+type notesLayout struct {
+	height       int
+	tagsWidth    int
+	listWidth    int
+	previewWidth int
+}
+
+func newNotesState() notesState {
+	search := textinput.New()
+	search.Prompt = "/"
+	search.Placeholder = "search title, content or tags"
+	return notesState{search: search, preview: viewport.New(0, 0)}
+}
+
+func (m *Model) notesLayout() notesLayout {
+	l := notesLayout{height: max(m.height-2, 4)}
+	l.tagsWidth = min(22, m.width/5)
+	l.listWidth = max((m.width-l.tagsWidth)*2/5, 20)
+	l.previewWidth = max(m.width-l.tagsWidth-l.listWidth, 10)
+	return l
+}
+
+func (s *notesState) resize(l notesLayout) {
+	// Panels lose two rows to the border and one to the title.
+	s.listRows = max(l.height-3, 1)
+	s.previewRows = s.listRows
+	s.preview.Width = max(l.previewWidth-2, 1)
+	s.preview.Height = s.previewRows
+	s.previewFor = previewKey{}
+	s.clamp()
+}
+
+// reloadNotes fetches every note again and reapplies the tag filter and
+// search, keeping the selection on the same note when it still shows.
+func (m *Model) reloadNotes() {
+	s := &m.notes
+	notes, err := m.repos.Notes.GetAll(false, 0)
+	if err != nil {
+		s.loadErr = fmt.Errorf("failed to fetch notes: %w", err)
+		return
+	}
+	s.loadErr = nil
+	s.all = notes
+
+	activeTag := s.activeTag()
+	counts := map[string]int{}
+	for _, n := range notes {
+		for _, t := range n.Tags {
+			counts[t]++
+		}
+	}
+	s.tags = s.tags[:0]
+	for name, count := range counts {
+		s.tags = append(s.tags, tagCount{name: name, count: count})
+	}
+	slices.SortFunc(s.tags, func(a, b tagCount) int { return strings.Compare(a.name, b.name) })
+
+	s.tagCursor = 0
+	for i, t := range s.tags {
+		if t.name == activeTag {
+			s.tagCursor = i + 1
+		}
+	}
+	s.applyFilter()
+}
+
+// activeTag is the tag notes are filtered by, or "" for all notes.
+func (s *notesState) activeTag() string {
+	if s.tagCursor == 0 || s.tagCursor > len(s.tags) {
+		return ""
+	}
+	return s.tags[s.tagCursor-1].name
+}
+
+// applyFilter keeps the notes with the active tag in which every search
+// term appears in the title, the content or a tag.
+func (s *notesState) applyFilter() {
+	selectedID := 0
+	if n := s.selected(); n != nil {
+		selectedID = n.ID
+	}
+
+	tag := s.activeTag()
+	terms := strings.Fields(strings.ToLower(s.search.Value()))
+	s.visible = s.visible[:0]
+	for _, n := range s.all {
+		if tag != "" && !slices.Contains(n.Tags, tag) {
+			continue
+		}
+		text := strings.ToLower(n.Title + "\n" + n.Content + "\n" + strings.Join(n.Tags, " "))
+		matches := true
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			s.visible = append(s.visible, n)
+		}
+	}
+
+	s.cursor = 0
+	for i, n := range s.visible {
+		if n.ID == selectedID {
+			s.cursor = i
+		}
+	}
+	s.clamp()
+}
+
+func (s *notesState) clamp() {
+	s.cursor = max(min(s.cursor, len(s.visible)-1), 0)
+	s.offset = scroll(s.offset, s.cursor, s.listRows)
+}
+
+func (s *notesState) selected() *note.NoteWithTags {
+	if s.cursor < 0 || s.cursor >= len(s.visible) {
+		return nil
+	}
+	return s.visible[s.cursor]
+}
+
+func (s *notesState) move(delta int) {
+	if s.focus == focusTags {
+		s.tagCursor = max(min(s.tagCursor+delta, len(s.tags)), 0)
+		s.applyFilter()
+		return
+	}
+	s.cursor += delta
+	s.clamp()
+}
+
+func (s *notesState) summary() string {
+	if s.loadErr != nil {
+		return ""
+	}
+	text := fmt.Sprintf("%d of %d notes", len(s.visible), len(s.all))
+	if tag := s.activeTag(); tag != "" {
+		text += " · #" + tag
+	}
+	if q := strings.TrimSpace(s.search.Value()); q != "" {
+		text += fmt.Sprintf(" · %q", q)
+	}
+	return text
+}
+
+var notesKeys = [][2]string{
+	{"j/k", "move"},
+	{"/", "search"},
+	{"n", "new"},
+	{"e", "edit"},
+	{"d", "delete"},
+	{"tab", "board"},
+	{"t", "tags"},
+	{"r", "rename"},
+	{"ctrl+d/u", "scroll preview"},
+	{"g/G", "top/bottom"},
+	{"q", "quit"},
+}
+
+func (m *Model) handleNotesKey(key string) tea.Cmd {
+	s := &m.notes
+	switch key {
+	case "j", "down":
+		s.move(1)
+	case "k", "up":
+		s.move(-1)
+	case "g", "home":
+		s.move(-len(s.all) - 1)
+	case "G", "end":
+		s.move(len(s.all) + 1)
+	case "t":
+		if s.focus == focusTags {
+			s.focus = focusList
+		} else {
+			s.focus = focusTags
+		}
+	case "h", "left":
+		s.focus = focusTags
+	case "l", "right":
+		s.focus = focusList
+	case "ctrl+d", "pgdown":
+		s.preview.HalfPageDown()
+	case "ctrl+u", "pgup":
+		s.preview.HalfPageUp()
+	case "/":
+		s.searching = true
+		s.focus = focusList
+		return s.search.Focus()
+	case "esc":
+		if s.search.Value() != "" {
+			s.search.SetValue("")
+			s.applyFilter()
+		}
+	case "n":
+		return m.ask("Title", "", m.newNote)
+	case "enter":
+		if s.focus == focusTags {
+			s.focus = focusList
+			return nil
+		}
+		return m.editNote()
+	case "e":
+		return m.editNote()
+	case "r":
+		n := s.selected()
+		if n == nil {
+			return nil
+		}
+		return m.ask("Title", n.Title, func(title string) tea.Cmd {
+			if title == "" || title == n.Title {
+				m.setStatus("Cancelled.")
+				return nil
+			}
+			if err := m.repos.Notes.Patch(n.ID, title); err != nil {
+				m.setError(err)
+				return nil
+			}
+			m.reloadNotes()
+			m.setStatus(fmt.Sprintf("Note #%d renamed.", n.ID))
+			return nil
+		})
+	case "d", "delete":
+		n := s.selected()
+		if n == nil {
+			return nil
+		}
+		m.askConfirm(fmt.Sprintf("Delete note #%d %q? (y/N)", n.ID, n.Title), func() tea.Cmd {
+			if err := m.repos.Notes.Delete(n.ID); err != nil {
+				m.setError(err)
+				return nil
+			}
+			m.reloadNotes()
+			m.setStatus(fmt.Sprintf("Note #%d deleted.", n.ID))
+			return nil
+		})
+	}
+	return nil
+}
+
+// handleSearchKey filters the list as the query is typed. Enter keeps the
+// query and returns to the list; esc clears it.
+func (m *Model) handleSearchKey(msg tea.KeyMsg) tea.Cmd {
+	s := &m.notes
+	switch msg.String() {
+	case "esc":
+		s.search.SetValue("")
+		fallthrough
+	case "enter":
+		s.searching = false
+		s.search.Blur()
+		s.applyFilter()
+		return nil
+	case "down", "ctrl+n":
+		s.move(1)
+		return nil
+	case "up", "ctrl+p":
+		s.move(-1)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	s.search, cmd = s.search.Update(msg)
+	s.applyFilter()
+	return cmd
+}
+
+// newNote asks for the content of a note titled title in the editor. A
+// note created while a tag filter is active gets that tag.
+func (m *Model) newNote(title string) tea.Cmd {
+	if title == "" {
+		m.setStatus("Cancelled.")
+		return nil
+	}
+	tag := m.notes.activeTag()
+	return m.edit("", func(content string) error {
+		if strings.TrimSpace(content) == "" {
+			m.setStatus("Empty note discarded.")
+			return nil
+		}
+		n := note.NewNote(title, content)
+		if err := m.repos.Notes.Create(n); err != nil {
+			return fmt.Errorf("failed to create note: %w", err)
+		}
+		if tag != "" {
+			t, err := m.repos.Tags.GetOrCreate(tag)
+			if err != nil {
+				return err
+			}
+			if err := m.repos.Notes.AddTagToNote(n.ID, t.ID); err != nil {
+				return err
+			}
+		}
+		m.reloadNotes()
+		m.selectNote(n.ID)
+		m.setStatus(fmt.Sprintf("Note #%d created.", n.ID))
+		return nil
+	})
+}
+
+func (m *Model) editNote() tea.Cmd {
+	n := m.notes.selected()
+	if n == nil {
+		return nil
+	}
+	return m.edit(n.Content, func(content string) error {
+		if err := m.repos.Notes.Update(n.ID, content, ""); err != nil {
+			return fmt.Errorf("failed to update note: %w", err)
+		}
+		m.reloadNotes()
+		m.setStatus(fmt.Sprintf("Note #%d updated.", n.ID))
+		return nil
+	})
+}
+
+func (m *Model) selectNote(id int) {
+	for i, n := range m.notes.visible {
+		if n.ID == id {
+			m.notes.cursor = i
+			m.notes.clamp()
+		}
+	}
+}
+
+func (m *Model) notesView() string {
+	s := &m.notes
+	l := m.notesLayout()
+
+	if s.loadErr != nil {
+		return panel("Notes", errorStyle.Render(s.loadErr.Error()), m.width, l.height, true)
+	}
+
+	tags := make([]string, 0, len(s.tags)+1)
+	tags = append(tags, listLine("All", len(s.all), s.tagCursor == 0, l.tagsWidth-2))
+	for i, t := range s.tags {
+		tags = append(tags, listLine("#"+t.name, t.count, s.tagCursor == i+1, l.tagsWidth-2))
+	}
+	tagsPanel := panel("Tags", strings.Join(tags, "\n"), l.tagsWidth, l.height, s.focus == focusTags)
+
+	var rows []string
+	if s.searching || s.search.Value() != "" {
+		rows = append(rows, fitANSI(s.search.View(), l.listWidth-2))
+	}
+	listRows := s.listRows - len(rows)
+	s.offset = scroll(s.offset, s.cursor, listRows)
+	for i := s.offset; i < len(s.visible) && i < s.offset+listRows; i++ {
+		rows = append(rows, noteLine(s.visible[i], i == s.cursor, l.listWidth-2))
+	}
+	if len(s.visible) == 0 {
+		rows = append(rows, mutedStyle.Render("No notes found."))
+	}
+	listPanel := panel("Notes", strings.Join(rows, "\n"), l.listWidth, l.height, s.focus == focusList)
+
+	title, body := "Preview", ""
+	if n := s.selected(); n != nil {
+		title = fmt.Sprintf("#%d %s", n.ID, n.Title)
+		m.renderPreview(n)
+		body = s.preview.View()
+	}
+	previewPanel := panel(title, body, l.previewWidth, l.height, false)
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, tagsPanel, listPanel, previewPanel)
+}
+
+// renderPreview renders n as Markdown into the preview, unless it already
+// shows n at the current width.
+func (m *Model) renderPreview(n *note.NoteWithTags) {
+	s := &m.notes
+	key := previewKey{id: n.ID, content: n.Content, width: s.preview.Width}
+	if key == s.previewFor {
+		return
+	}
+	s.previewFor = key
+
+	var header string
+	if len(n.Tags) > 0 {
+		header = mutedStyle.Render(fit("#"+strings.Join(n.Tags, " #"), s.preview.Width)) + "\n"
+	}
+	rendered := strings.TrimRight(string(markdown.Render(n.Content, max(s.preview.Width-1, 10), markdownPad)), "\n")
+	s.preview.SetContent(header + rendered)
+	s.preview.GotoTop()
+}
+
+func noteLine(n *note.NoteWithTags, selected bool, width int) string {
+	if selected {
+		return selectedStyle.Render(fit("› "+n.Title, width))
+	}
+	line := fit("  "+n.Title, width)
+	if room := width - lipgloss.Width(line) - 1; room > 4 && len(n.Tags) > 0 {
+		line += " " + mutedStyle.Render(fit("#"+strings.Join(n.Tags, " #"), room))
+	}
+	return line
+}
+
+func listLine(label string, count int, selected bool, width int) string {
+	countText := fmt.Sprintf(" %d", count)
+	line := fit(label, width-len(countText)-2)
+	if selected {
+		return selectedStyle.Render("› "+line) + mutedStyle.Render(countText)
+	}
+	return "  " + line + mutedStyle.Render(countText)
+}
@@ -0,0 +1,371 @@
+// Package tui is the full-screen interface started by snip tui: a notes
+// browser with live search, tag filters and a Markdown preview, and a
+// project board with one column per task status. Notes and tasks are
+// picked from lists, so no command needs an ID typed in.
+package tui
+
+import (
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/snip/internal/handler"
+	"github.com/snip/internal/repository"
+)
+
+// Repositories are the stores the interface reads and writes.
+type Repositories struct {
+	Notes    repository.NoteRepository
+	Tags     repository.TagRepository
+	Projects repository.ProjectRepository
+	Tasks    repository.TaskRepository
+}
+
+type screen int
+
+const (
+	notesScreen screen = iota
+	boardScreen
+)
+
+// Model is the bubbletea model behind snip tui.
+type Model struct {
+	repos  Repositories
+	editor *handler.EditorHandler
+
+	width  int
+	height int
+	screen screen
+	notes  notesState
+	board  boardState
+
+	prompt   *inputPrompt
+	confirm  *confirmPrompt
+	status   string
+	isError  bool
+	showHelp bool
+}
+
+// inputPrompt asks for one line of text at the bottom of the screen.
+type inputPrompt struct {
+	input  textinput.Model
+	submit func(value string) tea.Cmd
+}
+
+// confirmPrompt asks a yes/no question; anything but yes cancels.
+type confirmPrompt struct {
+	question string
+	yes      func() tea.Cmd
+}
+
+// NewModel loads notes and projects and returns the model, ready for
+// tea.NewProgram. editor opens notes and task descriptions for editing.
+func NewModel(repos Repositories, editor *handler.EditorHandler) *Model {
+	m := &Model{repos: repos, editor: editor}
+	m.notes = newNotesState()
+	m.reloadNotes()
+	m.reloadBoard()
+	return m
+}
+
+// Run shows m in the terminal's alternate screen and returns when the user
+// quits.
+func Run(m *Model) error {
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.notes.resize(m.notesLayout())
+		return m, nil
+	case editedMsg:
+		m.finishEdit(msg)
+		return m, nil
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+	if key == "ctrl+c" {
+		return tea.Quit
+	}
+
+	if m.confirm != nil {
+		confirm := m.confirm
+		m.confirm = nil
+		// The board asks in Portuguese, so "s" (sim) confirms too.
+		switch key {
+		case "y", "Y", "s", "S":
+			return confirm.yes()
+		}
+		if m.screen == boardScreen {
+			m.setStatus("Cancelado.")
+		} else {
+			m.setStatus("Cancelled.")
+		}
+		return nil
+	}
+
+	if m.prompt != nil {
+		switch key {
+		case "esc":
+			m.prompt = nil
+			return nil
+		case "enter":
+			prompt := m.prompt
+			m.prompt = nil
+			return prompt.submit(strings.TrimSpace(prompt.input.Value()))
+		}
+		var cmd tea.Cmd
+		m.prompt.input, cmd = m.prompt.input.Update(msg)
+		return cmd
+	}
+
+	if m.screen == notesScreen && m.notes.searching {
+		return m.handleSearchKey(msg)
+	}
+
+	m.status = ""
+	switch key {
+	case "q":
+		return tea.Quit
+	case "tab":
+		if m.screen == notesScreen {
+			m.screen = boardScreen
+			m.reloadBoard()
+		} else {
+			m.screen = notesScreen
+			m.reloadNotes()
+		}
+		return nil
+	case "?":
+		m.showHelp = !m.showHelp
+		return nil
+	}
+
+	if m.screen == boardScreen {
+		return m.handleBoardKey(key)
+	}
+	return m.handleNotesKey(key)
+}
+
+// ask opens a prompt; submit gets the trimmed value when enter is pressed.
+func (m *Model) ask(label, value string, submit func(string) tea.Cmd) tea.Cmd {
+	input := textinput.New()
+	input.Prompt = label + ": "
+	input.SetValue(value)
+	input.CursorEnd()
+	cmd := input.Focus()
+	m.prompt = &inputPrompt{input: input, submit: submit}
+	return cmd
+}
+
+func (m *Model) askConfirm(question string, yes func() tea.Cmd) {
+	m.confirm = &confirmPrompt{question: question, yes: yes}
+}
+
+func (m *Model) setStatus(status string) {
+	m.status, m.isError = status, false
+}
+
+// setError shows err the way the matching commands print it: project and
+// task errors in Portuguese, note errors in English.
+func (m *Model) setError(err error) {
+	prefix := "Error: "
+	if m.screen == boardScreen {
+		prefix = "Erro: "
+	}
+	m.status, m.isError = prefix+err.Error(), true
+}
+
+// editorCommand runs EditorHandler as a tea.ExecCommand: the program hands
+// the terminal over to the editor and takes it back when the editor exits.
+type editorCommand struct {
+	editor  *handler.EditorHandler
+	content string
+	result  string
+}
+
+func (c *editorCommand) Run() error {
+	file, err := c.editor.HandleEditor(c.content)
+	if err != nil {
+		return err
+	}
+	defer c.editor.RemoveTempFile(file)
+
+	c.result, err = c.editor.ReadTempFile(file)
+	return err
+}
+
+// The editor talks to the terminal directly.
+func (c *editorCommand) SetStdin(io.Reader)  {}
+func (c *editorCommand) SetStdout(io.Writer) {}
+func (c *editorCommand) SetStderr(io.Writer) {}
+
+// editedMsg carries the editor's result back to the model, which passes it
+// to apply.
+type editedMsg struct {
+	original string
+	content  string
+	err      error
+	apply    func(content string) error
+}
+
+// edit opens content in the editor; apply runs with the new content unless
+// the editor failed or nothing changed.
+func (m *Model) edit(content string, apply func(content string) error) tea.Cmd {
+	c := &editorCommand{editor: m.editor, content: content}
+	return tea.Exec(c, func(err error) tea.Msg {
+		return editedMsg{original: content, content: c.result, err: err, apply: apply}
+	})
+}
+
+func (m *Model) finishEdit(msg editedMsg) {
+	switch {
+	case msg.err != nil:
+		m.setError(msg.err)
+	case msg.content == msg.original && msg.original != "":
+		m.setStatus("No changes.")
+	default:
+		if err := msg.apply(msg.content); err != nil {
+			m.setError(err)
+		}
+	}
+}
+
+var (
+	accentColor = lipgloss.Color("212")
+	mutedColor  = lipgloss.Color("241")
+	errorColor  = lipgloss.Color("203")
+	doneColor   = lipgloss.Color("78")
+
+	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("0")).Background(accentColor).Padding(0, 1)
+	tabStyle      = lipgloss.NewStyle().Foreground(mutedColor).Padding(0, 1)
+	activeTab     = lipgloss.NewStyle().Bold(true).Foreground(accentColor).Underline(true).Padding(0, 1)
+	headingStyle  = lipgloss.NewStyle().Bold(true)
+	mutedStyle    = lipgloss.NewStyle().Foreground(mutedColor)
+	selectedStyle = lipgloss.NewStyle().Bold(true).Foreground(accentColor)
+	errorStyle    = lipgloss.NewStyle().Foreground(errorColor)
+	doneStyle     = lipgloss.NewStyle().Foreground(doneColor)
+)
+
+func (m *Model) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var body string
+	if m.screen == boardScreen {
+		body = m.boardView()
+	} else {
+		body = m.notesView()
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, m.header(), body, m.footer())
+}
+
+func (m *Model) header() string {
+	notesTab, boardTab := tabStyle.Render("Notes"), tabStyle.Render("Board")
+	if m.screen == notesScreen {
+		notesTab = activeTab.Render("Notes")
+	} else {
+		boardTab = activeTab.Render("Board")
+	}
+	left := lipgloss.JoinHorizontal(lipgloss.Top, titleStyle.Render("snip"), " ", notesTab, boardTab)
+
+	var info string
+	if m.screen == notesScreen {
+		info = m.notes.summary()
+	} else {
+		info = m.board.summary()
+	}
+	gap := max(m.width-lipgloss.Width(left)-lipgloss.Width(info)-1, 1)
+	return left + strings.Repeat(" ", gap) + mutedStyle.Render(info)
+}
+
+func (m *Model) footer() string {
+	switch {
+	case m.confirm != nil:
+		return selectedStyle.Render(m.confirm.question)
+	case m.prompt != nil:
+		return m.prompt.input.View()
+	case m.status != "" && m.isError:
+		return errorStyle.Render(fit(m.status, m.width))
+	case m.status != "":
+		return fit(m.status, m.width)
+	}
+
+	keys := notesKeys
+	if m.screen == boardScreen {
+		keys = boardKeys
+	}
+	if !m.showHelp {
+		keys = keys[:min(len(keys), 6)]
+		keys = append(keys[:len(keys):len(keys)], [2]string{"?", "more"})
+	}
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, headingStyle.Render(k[0])+" "+mutedStyle.Render(k[1]))
+	}
+	return fitANSI(strings.Join(parts, "  "), m.width)
+}
+
+// panel draws a bordered box of exactly width x height cells.
+func panel(title, body string, width, height int, active bool) string {
+	border := mutedColor
+	if active {
+		border = accentColor
+	}
+	innerWidth, innerHeight := max(width-2, 1), max(height-2, 1)
+
+	lines := []string{headingStyle.Render(fit(title, innerWidth))}
+	lines = append(lines, strings.Split(body, "\n")...)
+	if len(lines) > innerHeight {
+		lines = lines[:innerHeight]
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Width(innerWidth).
+		Height(innerHeight).
+		MaxWidth(width).
+		Render(strings.Join(lines, "\n"))
+}
+
+// fit truncates plain text to width cells.
+func fit(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return runewidth.Truncate(text, width, "…")
+}
+
+// fitANSI truncates styled text to width cells.
+func fitANSI(text string, width int) string {
+	return lipgloss.NewStyle().MaxWidth(max(width, 0)).Render(text)
+}
+
+// scroll returns the first row to show so that cursor is visible in a list
+// of rows rows that started at offset.
+func scroll(offset, cursor, rows int) int {
+	if cursor < offset {
+		return cursor
+	}
+	if cursor >= offset+rows {
+		return cursor - rows + 1
+	}
+	return max(offset, 0)
+}
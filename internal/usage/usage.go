@@ -0,0 +1,44 @@
+package usage
+
+import "time"
+
+const (
+	StatusOK        = "ok"
+	StatusError     = "error"
+	StatusCancelled = "cancelled"
+)
+
+type Usage struct {
+	ID               int       `json:"id"`
+	Command          string    `json:"command"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	LatencyMs        int64     `json:"latency_ms"`
+	Status           string    `json:"status"` // ok, error, cancelled
+	Error            string    `json:"error,omitempty"`
+	Redactions       string    `json:"redactions,omitempty"` // secrets masked in the request, e.g. "jwt=1, password=2"
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Summary aggregates usage rows for one group (model or command) and model,
+// so costs can be priced per model even when grouping by command.
+type Summary struct {
+	Group            string  `json:"group"`
+	Model            string  `json:"model"`
+	Calls            int     `json:"calls"`
+	Errors           int     `json:"errors"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	AvgLatencyMs     float64 `json:"avg_latency_ms"`
+	Redacted         int     `json:"redacted"` // calls that had secrets masked
+}
+
+func NewUsage(command, model string) *Usage {
+	return &Usage{
+		Command:   command,
+		Model:     model,
+		Status:    StatusOK,
+		CreatedAt: time.Now(),
+	}
+}